@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"go.keploy.io/server/v2/config"
+	replaySvc "go.keploy.io/server/v2/pkg/service/replay"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("graph", Graph)
+}
+
+// Graph retrieves the command to export the dependency graph of the test cases of a test set
+func Graph(ctx context.Context, logger *zap.Logger, _ *config.Config, serviceFactory ServiceFactory, cmdConfigurator CmdConfigurator) *cobra.Command {
+	var graphCmd = &cobra.Command{
+		Use:     "graph",
+		Short:   "Export the graph of the test cases of a test set, their dependencies and the mocks they consume",
+		Example: "keploy graph --test-set test-set-0 --format=mermaid --output=graph.md",
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return cmdConfigurator.ValidateFlags(ctx, cmd)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			svc, err := serviceFactory.GetService(ctx, cmd.Name())
+			if err != nil {
+				utils.LogError(logger, err, "failed to get service")
+				return nil
+			}
+			var replay replaySvc.Service
+			var ok bool
+			if replay, ok = svc.(replaySvc.Service); !ok {
+				utils.LogError(logger, nil, "service doesn't satisfy replay service interface")
+				return nil
+			}
+
+			testSetID, err := cmd.Flags().GetString("test-set")
+			if err != nil {
+				utils.LogError(logger, err, "failed to get the test set")
+				return nil
+			}
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				utils.LogError(logger, err, "failed to get the graph format")
+				return nil
+			}
+			output, err := cmd.Flags().GetString("output-path")
+			if err != nil {
+				utils.LogError(logger, err, "failed to get the output path of the graph")
+				return nil
+			}
+			err = replay.ExportDependencyGraph(ctx, testSetID, format, output)
+			if err != nil {
+				utils.LogError(logger, err, "failed to export the dependency graph", zap.String("test-set", testSetID))
+				return nil
+			}
+			logger.Info("dependency graph is written", zap.String("test-set", testSetID), zap.String("path", output))
+			return nil
+		},
+	}
+	if err := cmdConfigurator.AddFlags(graphCmd); err != nil {
+		utils.LogError(logger, err, "failed to add graph cmd flags")
+		return nil
+	}
+	return graphCmd
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"go.keploy.io/server/v2/config"
+	replaySvc "go.keploy.io/server/v2/pkg/service/replay"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("mock", Mock)
+}
+
+// Mock retrieves the command to manage the recorded mocks
+func Mock(ctx context.Context, logger *zap.Logger, _ *config.Config, serviceFactory ServiceFactory, cmdConfigurator CmdConfigurator) *cobra.Command {
+	var mockCmd = &cobra.Command{
+		Use:   "mock",
+		Short: "Manage the recorded mocks of the test sets",
+	}
+
+	var groupCmd = &cobra.Command{
+		Use:     "group",
+		Short:   "Group the mocks of a test set by the endpoint they mock",
+		Example: "keploy mock group --test-set test-set-0",
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return cmdConfigurator.ValidateFlags(ctx, cmd)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			svc, err := serviceFactory.GetService(ctx, mockCmd.Name())
+			if err != nil {
+				utils.LogError(logger, err, "failed to get service")
+				return nil
+			}
+			var replay replaySvc.Service
+			var ok bool
+			if replay, ok = svc.(replaySvc.Service); !ok {
+				utils.LogError(logger, nil, "service doesn't satisfy replay service interface")
+				return nil
+			}
+
+			testSetID, err := cmd.Flags().GetString("test-set")
+			if err != nil {
+				utils.LogError(logger, err, "failed to get the test set")
+				return nil
+			}
+			groups, err := replay.GetMocksGroupedByEndpoint(ctx, testSetID)
+			if err != nil {
+				utils.LogError(logger, err, "failed to group the mocks by endpoint", zap.String("test-set", testSetID))
+				return nil
+			}
+
+			// the endpoints with the most mocks first
+			endpoints := make([]string, 0, len(groups))
+			for endpoint := range groups {
+				endpoints = append(endpoints, endpoint)
+			}
+			sort.Slice(endpoints, func(i, j int) bool {
+				if len(groups[endpoints[i]]) != len(groups[endpoints[j]]) {
+					return len(groups[endpoints[i]]) > len(groups[endpoints[j]])
+				}
+				return endpoints[i] < endpoints[j]
+			})
+			for _, endpoint := range endpoints {
+				names := make([]string, 0, len(groups[endpoint]))
+				for _, mock := range groups[endpoint] {
+					names = append(names, mock.Name)
+				}
+				logger.Info(fmt.Sprintf("%s: %d mocks", endpoint, len(names)), zap.String("test-set", testSetID), zap.Strings("mocks", names))
+			}
+			return nil
+		},
+	}
+	if err := cmdConfigurator.AddFlags(groupCmd); err != nil {
+		utils.LogError(logger, err, "failed to add mock group cmd flags")
+		return nil
+	}
+	mockCmd.AddCommand(groupCmd)
+
+	for _, encrypt := range []bool{true, false} {
+		cmd := mockEncryptionCmd(ctx, logger, serviceFactory, cmdConfigurator, mockCmd.Name(), encrypt)
+		if err := cmdConfigurator.AddFlags(cmd); err != nil {
+			utils.LogError(logger, err, "failed to add mock "+cmd.Name()+" cmd flags")
+			return nil
+		}
+		mockCmd.AddCommand(cmd)
+	}
+	return mockCmd
+}
+
+// mockEncryptionCmd retrieves the command to encrypt or decrypt the mock files with the mockEncryption config
+func mockEncryptionCmd(ctx context.Context, logger *zap.Logger, serviceFactory ServiceFactory, cmdConfigurator CmdConfigurator, serviceName string, encrypt bool) *cobra.Command {
+	use, action := "decrypt", "Decrypt"
+	if encrypt {
+		use, action = "encrypt", "Encrypt"
+	}
+	return &cobra.Command{
+		Use:     use,
+		Short:   action + " the mock files of the test sets with the mockEncryption config, of all the test sets by default",
+		Example: "keploy mock " + use + " --test-set test-set-0",
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return cmdConfigurator.ValidateFlags(ctx, cmd)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			svc, err := serviceFactory.GetService(ctx, serviceName)
+			if err != nil {
+				utils.LogError(logger, err, "failed to get service")
+				return nil
+			}
+			var replay replaySvc.Service
+			var ok bool
+			if replay, ok = svc.(replaySvc.Service); !ok {
+				utils.LogError(logger, nil, "service doesn't satisfy replay service interface")
+				return nil
+			}
+
+			testSets, err := cmd.Flags().GetStringSlice("test-set")
+			if err != nil {
+				utils.LogError(logger, err, "failed to get the test sets")
+				return nil
+			}
+			var changed int
+			if encrypt {
+				changed, err = replay.EncryptMocks(ctx, testSets)
+			} else {
+				changed, err = replay.DecryptMocks(ctx, testSets)
+			}
+			if err != nil {
+				utils.LogError(logger, err, "failed to "+use+" the mocks")
+				return nil
+			}
+			logger.Info(fmt.Sprintf("%sed the mocks of %d test sets", use, changed))
+			return nil
+		},
+	}
+}
@@ -6,15 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"github.com/spf13/pflag"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg"
 	"go.keploy.io/server/v2/pkg/models"
 	"go.keploy.io/server/v2/utils"
 	"go.keploy.io/server/v2/utils/log"
@@ -173,8 +177,43 @@ func (c *CmdConfigurator) AddFlags(cmd *cobra.Command) error {
 		return nil
 	case "normalize":
 		cmd.Flags().StringP("path", "p", ".", "Path to local directory where generated testcases/mocks/reports are stored")
+		cmd.Flags().String("namespace", c.cfg.Namespace, "Namespace of the testcases and the reports in the path, to share the path between teams")
 		cmd.Flags().String("test-run", "", "Test Run to be normalized")
 		cmd.Flags().String("tests", "", "Test Sets to be normalized")
+		cmd.Flags().String("strategy", c.cfg.Normalize.Strategy, "Normalization strategy: full replaces the recorded responses, partial only updates the status code, headers and body which did not match")
+	case "report":
+		cmd.Flags().StringP("path", "p", ".", "Path to local directory where generated testcases/mocks/reports are stored")
+		cmd.Flags().String("namespace", c.cfg.Namespace, "Namespace of the testcases and the reports in the path, to share the path between teams")
+		cmd.Flags().StringSliceP("test-sets", "t", c.cfg.Report.SelectedTestSets, "Testsets to report e.g. --test-sets \"test-set-1, test-set-2\"")
+		cmd.Flags().Int("last-n", c.cfg.Report.StabilityRuns, "Number of the latest test runs to compute the stability over")
+		cmd.Flags().Float64("flaky-threshold", c.cfg.Report.FlakyThreshold, "Testcases passing less than this ratio of the test runs are reported as flaky")
+	case "group":
+		cmd.Flags().StringP("path", "p", ".", "Path to local directory where generated testcases/mocks/reports are stored")
+		cmd.Flags().String("namespace", c.cfg.Namespace, "Namespace of the testcases and the reports in the path, to share the path between teams")
+		cmd.Flags().String("test-set", "", "Testset whose mocks are grouped by endpoint e.g. --test-set test-set-0")
+		err := cmd.MarkFlagRequired("test-set")
+		if err != nil {
+			errMsg := "failed to mark test-set as required flag"
+			utils.LogError(c.logger, err, errMsg)
+			return errors.New(errMsg)
+		}
+	case "encrypt", "decrypt":
+		cmd.Flags().StringP("path", "p", ".", "Path to local directory where generated testcases/mocks/reports are stored")
+		cmd.Flags().String("namespace", c.cfg.Namespace, "Namespace of the testcases and the reports in the path, to share the path between teams")
+		cmd.Flags().StringSlice("test-set", nil, "Testsets whose mocks are "+cmd.Name()+"ed e.g. --test-set test-set-0, all the testsets by default")
+	case "graph":
+		cmd.Flags().StringP("path", "p", ".", "Path to local directory where generated testcases/mocks/reports are stored")
+		cmd.Flags().String("namespace", c.cfg.Namespace, "Namespace of the testcases and the reports in the path, to share the path between teams")
+		cmd.Flags().String("test-set", "", "Testset whose dependency graph is exported e.g. --test-set test-set-0")
+		cmd.Flags().String("format", "mermaid", "Format of the graph: dot, mermaid or json")
+		// the flag is not named output, which would shadow the output config in viper, --output is its alias
+		cmd.Flags().StringP("output-path", "o", "graph.md", "Path of the file the graph is written to")
+		err := cmd.MarkFlagRequired("test-set")
+		if err != nil {
+			errMsg := "failed to mark test-set as required flag"
+			utils.LogError(c.logger, err, errMsg)
+			return errors.New(errMsg)
+		}
 	case "config":
 		cmd.Flags().StringP("path", "p", ".", "Path to local directory where generated config is stored")
 		cmd.Flags().Bool("generate", false, "Generate a new keploy configuration file")
@@ -210,6 +249,7 @@ func (c *CmdConfigurator) AddFlags(cmd *cobra.Command) error {
 		}
 	case "record", "test":
 		cmd.Flags().StringP("path", "p", ".", "Path to local directory where generated testcases/mocks are stored")
+		cmd.Flags().String("namespace", c.cfg.Namespace, "Namespace of the testcases and the reports in the path, to share the path between teams")
 		cmd.Flags().Uint32("port", c.cfg.Port, "GraphQL server port used for executing testcases in unit test library integration")
 		cmd.Flags().Uint32("proxy-port", c.cfg.ProxyPort, "Port used by the Keploy proxy server to intercept the outgoing dependency calls")
 		cmd.Flags().Uint32("dns-port", c.cfg.DNSPort, "Port used by the Keploy DNS server to intercept the DNS queries")
@@ -221,6 +261,7 @@ func (c *CmdConfigurator) AddFlags(cmd *cobra.Command) error {
 		cmd.Flags().UintSlice("pass-through-ports", config.GetByPassPorts(c.cfg), "Ports to bypass the proxy server and ignore the traffic")
 		cmd.Flags().StringP("app-id", "a", c.cfg.AppID, "A unique name for the user's application")
 		cmd.Flags().Bool("generate-github-actions", c.cfg.GenerateGithubActions, "Generate Github Actions workflow file")
+		cmd.Flags().String("name-template", c.cfg.Test.NameTemplate, "Go template of the testcase names with {{.Method}}, {{.URL}}, {{.StatusCode}} and {{.Index}} e.g. \"{{.Method}}-{{.URL}}-{{.StatusCode}}\"")
 		err = cmd.Flags().MarkHidden("port")
 		if err != nil {
 			errMsg := "failed to mark port as hidden flag"
@@ -229,18 +270,82 @@ func (c *CmdConfigurator) AddFlags(cmd *cobra.Command) error {
 		}
 		if cmd.Name() == "test" {
 			cmd.Flags().StringSliceP("test-sets", "t", utils.Keys(c.cfg.Test.SelectedTests), "Testsets to run e.g. --testsets \"test-set-1, test-set-2\"")
+			cmd.Flags().String("since", "", "Only run the test sets with a testcase or a mock modified since the time e.g. --since 2024-01-02T15:04:05Z or --since 2024-01-02")
+			cmd.Flags().Int("shard-index", c.cfg.Test.ShardIndex, "Index of the shard of the test sets run by this CI worker, from 0 to shard-total - 1")
+			cmd.Flags().Int("shard-total", c.cfg.Test.ShardTotal, "Number of the CI workers the test sets are split across")
+			cmd.Flags().StringSlice("additional-test-paths", c.cfg.AdditionalTestPaths, "Keploy directories whose testcases are run alongside the ones of the path e.g. --additional-test-paths ../shared/keploy")
 			cmd.Flags().Uint64P("delay", "d", 5, "User provided time to run its application")
+			cmd.Flags().String("readiness-probe", c.cfg.Test.ReadinessProbe, "Url polled until the application responds with a 2xx status code, instead of waiting for the delay e.g. http://localhost:8080/health")
+			cmd.Flags().String("readiness-log-pattern", c.cfg.Test.ReadinessLogPattern, "Regular expression of the line the application logs once ready, waited for instead of the delay e.g. \"Server started on port\"")
+			cmd.Flags().Duration("readiness-timeout", c.cfg.Test.ReadinessTimeout, "Maximum time the readiness probe is polled before running the testcases anyway")
 			cmd.Flags().Uint64("api-timeout", c.cfg.Test.APITimeout, "User provided timeout for calling its application")
+			cmd.Flags().Int("max-response-body-kb", c.cfg.Test.MaxResponseBodyKB, "Size in KB the response bodies are truncated at, the bodies of that size are not compared (0 for no limit)")
+			cmd.Flags().String("test-name-filter", c.cfg.Test.TestNameFilter, "Regular expression the names of the testcases must match to run, combined with the selected testcases (e.g. ^test-[0-9]+$)")
+			cmd.Flags().String("output-format", c.cfg.Output.Format, "Format of the testcase results and the summaries written to stdout: text, json (one json object per line) or tap (Test Anything Protocol)")
+			cmd.Flags().String("deduplication", c.cfg.Test.Deduplication, "Delete the duplicate testcases (same method, url and body) of the test sets before running them: exact or semantic (json normalized bodies)")
+			cmd.Flags().Bool("dry-run", c.cfg.Test.DryRun, "List the test sets and the testcases which would run with the selected tests and the base path, without starting the application")
+			cmd.Flags().Duration("test-set-timeout", c.cfg.Test.TestSetTimeout, "Wall-clock limit of a test set (e.g. 10m), the test set is stopped and reported as timed out when exceeded")
+			cmd.Flags().Duration("mock-ttl", c.cfg.Test.MockTTL, "Lifetime of the recorded mocks (e.g. 2160h), the older mocks are stale and not replayed")
+			cmd.Flags().Duration("latency-budget", c.cfg.Test.LatencyBudget, "Latency budget of every testcase (e.g. 200ms), the testcases responding slower fail")
 			cmd.Flags().String("mongo-password", c.cfg.Test.MongoPassword, "Authentication password for mocking MongoDB conn")
 			cmd.Flags().String("coverage-report-path", c.cfg.Test.CoverageReportPath, "Write a go coverage profile to the file in the given directory.")
 			cmd.Flags().StringP("language", "l", c.cfg.Test.Language, "application programming language")
 			cmd.Flags().Bool("ignore-ordering", c.cfg.Test.IgnoreOrdering, "Ignore ordering of array in response")
+			cmd.Flags().Bool("ignore-extra-fields", c.cfg.Test.IgnoreExtraFields, "Ignore the fields of the responses which are not in the recorded responses")
+			cmd.Flags().Float64("float-abs-tolerance", c.cfg.Test.FloatAbsTolerance, "Absolute difference up to which the non-integral numbers in the json responses are considered equal")
+			cmd.Flags().Float64("float-rel-tolerance", c.cfg.Test.FloatRelTolerance, "Difference relative to the larger number up to which the non-integral numbers in the json responses are considered equal, e.g. 0.001 for 0.1%")
+			cmd.Flags().StringSlice("semver-fields", c.cfg.Test.GlobalNoise.SemverFields, "Json body fields of the responses compared as semantic versions, also set by globalNoise.semverFields")
+			cmd.Flags().String("semver-tolerance", c.cfg.Test.SemverTolerance, "Part of the version (patch, minor or major) up to which the differences of the semver fields are tolerated")
+			cmd.Flags().String("body-comparator", c.cfg.Test.BodyComparator, "Comparison of the response bodies: exact (default), subset (the actual body contains the recorded fields) or regex (the recorded strings are regular expressions)")
+			cmd.Flags().Bool("graphql-mode", c.cfg.Test.GraphQLMode, "Compare the data of the graphql responses and fail the responses with errors whatever their status code")
 			cmd.Flags().Bool("coverage", c.cfg.Test.Coverage, "Enable coverage reporting for the testcases. for golang please set language flag to golang, ref https://keploy.io/docs/server/sdk-installation/go/")
 			cmd.Flags().Bool("remove-unused-mocks", c.cfg.Test.RemoveUnusedMocks, "Clear the unused mocks for the passed test-sets")
+			cmd.Flags().String("mock-match-strategy", c.cfg.Test.MockMatchStrategy, "Order of the mocks matching the same outgoing call: firstMatch, roundRobin or random")
+			cmd.Flags().Bool("auto-sort-mocks", c.cfg.Test.AutoSortMocks, "Sort the mocks by the timestamp of their requests when they are out of order")
+			cmd.Flags().Bool("time-shift-replay", c.cfg.Test.TimeShiftReplay, "Shift the timestamps of the testcases and the mocks so that the test set replays as if it was recorded now")
+			cmd.Flags().String("coverage-driver", c.cfg.Test.CoverageDriver, "Coverage driver of the application, istanbul reads the coverage of the js apps from their /__coverage__ endpoint into coverage/coverage-final.json")
 			cmd.Flags().Bool("go-coverage", c.cfg.Test.GoCoverage, "Enable go coverage reporting for the testcases")
+			cmd.Flags().Float64("min-coverage", c.cfg.Test.MinCoverage, "Minimum go coverage percentage of the test run, keploy exits with a non-zero code below it")
+			cmd.Flags().Bool("coverage-merge", c.cfg.Test.CoverageMerge, "Merge the go coverage of the test run with the coverage of the previous runs kept in the coverage directory of keploy")
 			cmd.Flags().Bool("fallBack-on-miss", c.cfg.Test.FallBackOnMiss, "Enable connecting to actual service if mock not found during test mode")
+			cmd.Flags().StringToString("global-headers", c.cfg.Test.GlobalHeaders, "Headers added to every replayed request e.g. --global-headers X-Env=staging,Authorization=\"Bearer token\"")
+			cmd.Flags().Bool("override-headers", c.cfg.Test.OverrideHeaders, "Replace the headers of the testcases with the global headers of the same name")
+			cmd.Flags().String("client-cert", c.cfg.Test.ClientCert, "PEM client certificate presented to the app at the base path requiring mutual tls")
+			cmd.Flags().String("client-key", c.cfg.Test.ClientKey, "PEM private key of the client certificate")
+			cmd.Flags().String("client-key-passphrase", c.cfg.Test.ClientKeyPassphrase, "Passphrase of the encrypted private key of the client certificate")
+			cmd.Flags().String("ca-cert", c.cfg.Test.CACert, "PEM CA certificate the certificate of the app at the base path is verified against, the system CAs by default")
+			cmd.Flags().Bool("insecure-skip-verify", c.cfg.Test.InsecureSkipVerify, "Skip the verification of the certificate of the app at the base path")
+			cmd.Flags().Bool("auto-denoise", c.cfg.Test.AutoDenoise, "Replay the test sets --denoise-runs times and add the fields of the responses differing in the runs to the noise of the testcases")
+			cmd.Flags().Int("denoise-runs", c.cfg.Test.DenoiseRuns, "Number of the runs of the test sets to detect the noisy fields with --auto-denoise")
+			cmd.Flags().Float64("denoise-threshold", c.cfg.Test.DenoiseThreshold, "Fields differing in more than this fraction of the denoise runs are added to the noise")
 			cmd.Flags().String("base-path", c.cfg.Test.BasePath, "Custom api basePath/origin to replace the actual basePath/origin in the testcases; App flag is ignored and app will not be started & instrumented when this is set since the application running on a different machine")
+			cmd.Flags().String("reference-base-path", c.cfg.Test.ReferenceBasePath, "Base path/origin of the reference implementation; the responses of the app at the base path are compared with the live responses of the reference instead of the recorded ones")
 			cmd.Flags().Bool("mocking", true, "enable/disable mocking for the testcases")
+			cmd.Flags().String("report-overwrite-policy", c.cfg.Test.ReportOverwritePolicy, "Behaviour when a report already exists for the test run and test set (overwrite, append or error)")
+			cmd.Flags().String("remote-test-set-url", c.cfg.Test.RemoteTestSetURL, "s3://, gs:// or https:// url, e.g. pre-signed, of a .tar.gz archive of the keploy directory to run the tests from, the buckets are accessed with the aws credentials or the GOOGLE_OAUTH_ACCESS_TOKEN of the environment")
+			cmd.Flags().String("remote-report-url", c.cfg.Test.RemoteReportURL, "s3://, gs:// or https:// url, e.g. pre-signed for a PUT request, to upload the reports of the test run to")
+			cmd.Flags().Int("slow-test-top-n", c.cfg.Test.SlowTestTopN, "Number of the slowest testcases listed in the summary of the test run (0 to not list them)")
+			cmd.Flags().String("summary-json-path", c.cfg.Test.SummaryJSONPath, "Path of the machine-readable json summary of the test run")
+			cmd.Flags().Bool("html-report", c.cfg.Test.HTMLReport, "Generate an html report with the collapsible diffs of the testcases, rendered without any network access")
+			cmd.Flags().String("html-report-dir", c.cfg.Test.HTMLReportDir, "Directory of the html report, the html directory of the reports of the test run by default")
+			cmd.Flags().String("junit-report-path", c.cfg.Test.JUnitReportPath, "Path of the JUnit XML report of the test run for CI consumption")
+			cmd.Flags().String("sarif-output-path", c.cfg.Test.SARIFOutputPath, "Path of the SARIF report of the failed testcases for the IDE and code scanning integrations")
+			cmd.Flags().Int("parallelism", c.cfg.Test.Parallelism, "Number of testcases of a test set to run concurrently against the base path, ignored when the outgoing calls of the app are mocked")
+			cmd.Flags().Int("max-parallel", c.cfg.Test.MaxParallel, "Number of test sets to run concurrently against the base path, ignored when the outgoing calls of the app are mocked")
+			cmd.Flags().Bool("use-snapshot", c.cfg.Test.UseSnapshot, "Restore a CRIU snapshot of the warmed up application before each testcase to isolate the testcases (native applications only)")
+			cmd.Flags().Int("max-retries", c.cfg.Test.MaxRetries, "Number of times a failed testcase is re-run before it is marked failed, the testcases passing on a retry are flagged flaky")
+			cmd.Flags().IntSlice("retry-on-status", c.cfg.Test.RetryOnStatus, "Transient status codes on which a failed testcase is retried, any failure is retried if not set e.g. --retry-on-status 502,503,504")
+			cmd.Flags().Int("retry-delay-ms", c.cfg.Test.RetryDelayMs, "Delay in milliseconds between the re-runs of a failed testcase")
+			cmd.Flags().Bool("fail-fast", c.cfg.Test.FailFast, "Stop the test run at the first failing testcase")
+			cmd.Flags().Bool("order-by-last-failure", c.cfg.Test.OrderByLastFailure, "Run the testcases which failed in the last test run first")
+			cmd.Flags().Bool("randomize-order", c.cfg.Test.RandomizeOrder, "Run the testcases of the test sets in a random order to detect the testcases depending on their order")
+			cmd.Flags().Int64("random-seed", c.cfg.Test.RandomSeed, "Seed of the random order of the testcases, a random seed is picked and logged when 0")
+			cmd.Flags().Int("random-runs", c.cfg.Test.RandomRuns, "Number of the random orders each test set is run in, the testcases passing only in some of them are reported as order sensitive")
+			cmd.Flags().Int("max-failures", c.cfg.Test.MaxFailures, "Abort the remaining testcases of a test set once that many of its testcases failed (0 for no limit)")
+			cmd.Flags().String("on-success", c.cfg.Test.OnSuccess, "Shell command run at the end of a passing test run, e.g. to trigger a deployment")
+			cmd.Flags().String("on-failure", c.cfg.Test.OnFailure, "Shell command run at the end of a failing test run, e.g. to send a notification")
+			cmd.Flags().Bool("accept", c.cfg.Test.AutoAccept, "Accept the actual responses of the failing testcases as the expected ones after each test set runs (not allowed in CI)")
+			cmd.Flags().Bool("record-missing-test-cases", c.cfg.Test.RecordMissingTestCases, "Record the response of the testcases which don't have a recorded response instead of testing them")
 		} else {
 			cmd.Flags().Uint64("record-timer", 0, "User provided time to record its application")
 			cmd.Flags().StringP("rerecord", "r", c.cfg.Record.ReRecord, "Rerecord the testcases/mocks for the given testset(s)")
@@ -248,13 +353,21 @@ func (c *CmdConfigurator) AddFlags(cmd *cobra.Command) error {
 	case "keploy":
 		cmd.PersistentFlags().Bool("debug", c.cfg.Debug, "Run in debug mode")
 		cmd.PersistentFlags().Bool("disable-tele", c.cfg.DisableTele, "Run in telemetry mode")
+		cmd.PersistentFlags().Int("tele-batch-size", c.cfg.TeleBatchSize, "Number of the test run events sent together in a single telemetry request")
 		cmd.PersistentFlags().Bool("disable-ansi", c.cfg.DisableANSI, "Disable ANSI color in logs")
+		cmd.PersistentFlags().Bool("disable-color", c.cfg.DisableColor, "Print the summaries and the results of the testcases as plain text, also set by the NO_COLOR environment variable")
 		err = cmd.PersistentFlags().MarkHidden("disable-tele")
 		if err != nil {
 			errMsg := "failed to mark telemetry as hidden flag"
 			utils.LogError(c.logger, err, errMsg)
 			return errors.New(errMsg)
 		}
+		err = cmd.PersistentFlags().MarkHidden("tele-batch-size")
+		if err != nil {
+			errMsg := "failed to mark teleBatchSize as hidden flag"
+			utils.LogError(c.logger, err, errMsg)
+			return errors.New(errMsg)
+		}
 		cmd.PersistentFlags().Bool("enable-testing", c.cfg.EnableTesting, "Enable testing keploy with keploy")
 		err = cmd.PersistentFlags().MarkHidden("enable-testing")
 		if err != nil {
@@ -272,52 +385,120 @@ func (c *CmdConfigurator) AddFlags(cmd *cobra.Command) error {
 
 func aliasNormalizeFunc(_ *pflag.FlagSet, name string) pflag.NormalizedName {
 	var flagNameMapping = map[string]string{
-		"testsets":              "test-sets",
-		"delay":                 "delay",
-		"apiTimeout":            "api-timeout",
-		"mongoPassword":         "mongo-password",
-		"coverageReportPath":    "coverage-report-path",
-		"language":              "language",
-		"ignoreOrdering":        "ignore-ordering",
-		"coverage":              "coverage",
-		"removeUnusedMocks":     "remove-unused-mocks",
-		"goCoverage":            "go-coverage",
-		"fallBackOnMiss":        "fallBack-on-miss",
-		"basePath":              "base-path",
-		"mocking":               "mocking",
-		"sourceFilePath":        "source-file-path",
-		"testFilePath":          "test-file-path",
-		"testCommand":           "test-command",
-		"coverageFormat":        "coverage-format",
-		"expectedCoverage":      "expected-coverage",
-		"maxIterations":         "max-iterations",
-		"testDir":               "test-dir",
-		"llmBaseUrl":            "llm-base-url",
-		"model":                 "model",
-		"llmApiVersion":         "llm-api-version",
-		"configPath":            "config-path",
-		"path":                  "path",
-		"port":                  "port",
-		"proxyPort":             "proxy-port",
-		"dnsPort":               "dns-port",
-		"command":               "command",
-		"cmdType":               "cmd-type",
-		"buildDelay":            "build-delay",
-		"containerName":         "container-name",
-		"networkName":           "network-name",
-		"passThroughPorts":      "pass-through-ports",
-		"appId":                 "app-id",
-		"generateGithubActions": "generate-github-actions",
-		"disableTele":           "disable-tele",
-		"disableANSI":           "disable-ansi",
-		"selectedTests":         "selected-tests",
-		"testReport":            "test-report",
-		"enableTesting":         "enable-testing",
-		"inDocker":              "in-docker",
-		"keployContainer":       "keploy-container",
-		"keployNetwork":         "keploy-network",
-		"recordTimer":           "record-timer",
-		"urlMethods":            "url-methods",
+		"testsets":               "test-sets",
+		"delay":                  "delay",
+		"additionalTestPaths":    "additional-test-paths",
+		"runTestSetsSince":       "since",
+		"shardIndex":             "shard-index",
+		"shardTotal":             "shard-total",
+		"readinessProbe":         "readiness-probe",
+		"readinessLogPattern":    "readiness-log-pattern",
+		"nameTemplate":           "name-template",
+		"readinessTimeout":       "readiness-timeout",
+		"apiTimeout":             "api-timeout",
+		"maxResponseBodyKB":      "max-response-body-kb",
+		"dryRun":                 "dry-run",
+		"deduplication":          "deduplication",
+		"testNameFilter":         "test-name-filter",
+		"testSetTimeout":         "test-set-timeout",
+		"mockTTL":                "mock-ttl",
+		"latencyBudget":          "latency-budget",
+		"mongoPassword":          "mongo-password",
+		"coverageReportPath":     "coverage-report-path",
+		"language":               "language",
+		"ignoreOrdering":         "ignore-ordering",
+		"ignoreExtraFields":      "ignore-extra-fields",
+		"floatAbsTolerance":      "float-abs-tolerance",
+		"floatRelTolerance":      "float-rel-tolerance",
+		"semverFields":           "semver-fields",
+		"semverTolerance":        "semver-tolerance",
+		"graphQLMode":            "graphql-mode",
+		"bodyComparator":         "body-comparator",
+		"coverage":               "coverage",
+		"removeUnusedMocks":      "remove-unused-mocks",
+		"mockMatchStrategy":      "mock-match-strategy",
+		"autoSortMocks":          "auto-sort-mocks",
+		"timeShiftReplay":        "time-shift-replay",
+		"goCoverage":             "go-coverage",
+		"minCoverage":            "min-coverage",
+		"coverageMerge":          "coverage-merge",
+		"coverageDriver":         "coverage-driver",
+		"fallBackOnMiss":         "fallBack-on-miss",
+		"basePath":               "base-path",
+		"globalHeaders":          "global-headers",
+		"overrideHeaders":        "override-headers",
+		"clientCert":             "client-cert",
+		"clientKey":              "client-key",
+		"clientKeyPassphrase":    "client-key-passphrase",
+		"caCert":                 "ca-cert",
+		"insecureSkipVerify":     "insecure-skip-verify",
+		"autoDenoise":            "auto-denoise",
+		"denoiseRuns":            "denoise-runs",
+		"denoiseThreshold":       "denoise-threshold",
+		"referenceBasePath":      "reference-base-path",
+		"mocking":                "mocking",
+		"recordMissingTestCases": "record-missing-test-cases",
+		"reportOverwritePolicy":  "report-overwrite-policy",
+		"remoteTestSetUrl":       "remote-test-set-url",
+		"remoteReportUrl":        "remote-report-url",
+		"retryOnStatus":          "retry-on-status",
+		"maxRetries":             "max-retries",
+		"retryDelayMs":           "retry-delay-ms",
+		"failFast":               "fail-fast",
+		"orderByLastFailure":     "order-by-last-failure",
+		"randomizeOrder":         "randomize-order",
+		"randomSeed":             "random-seed",
+		"randomRuns":             "random-runs",
+		"maxFailures":            "max-failures",
+		"onSuccess":              "on-success",
+		"onFailure":              "on-failure",
+		"junitReportPath":        "junit-report-path",
+		"sarifOutputPath":        "sarif-output-path",
+		"htmlReport":             "html-report",
+		"htmlReportDir":          "html-report-dir",
+		"summaryJsonPath":        "summary-json-path",
+		"slowTestTopN":           "slow-test-top-n",
+		"maxParallel":            "max-parallel",
+		"useSnapshot":            "use-snapshot",
+		"lastN":                  "last-n",
+		"flakyThreshold":         "flaky-threshold",
+		"sourceFilePath":         "source-file-path",
+		"testFilePath":           "test-file-path",
+		"testCommand":            "test-command",
+		"coverageFormat":         "coverage-format",
+		"expectedCoverage":       "expected-coverage",
+		"maxIterations":          "max-iterations",
+		"testDir":                "test-dir",
+		"llmBaseUrl":             "llm-base-url",
+		"model":                  "model",
+		"llmApiVersion":          "llm-api-version",
+		"configPath":             "config-path",
+		"path":                   "path",
+		"namespace":              "namespace",
+		"port":                   "port",
+		"proxyPort":              "proxy-port",
+		"dnsPort":                "dns-port",
+		"command":                "command",
+		"cmdType":                "cmd-type",
+		"buildDelay":             "build-delay",
+		"containerName":          "container-name",
+		"networkName":            "network-name",
+		"passThroughPorts":       "pass-through-ports",
+		"appId":                  "app-id",
+		"generateGithubActions":  "generate-github-actions",
+		"disableTele":            "disable-tele",
+		"teleBatchSize":          "tele-batch-size",
+		"disableANSI":            "disable-ansi",
+		"disableColor":           "disable-color",
+		"selectedTests":          "selected-tests",
+		"testReport":             "test-report",
+		"enableTesting":          "enable-testing",
+		"inDocker":               "in-docker",
+		"keployContainer":        "keploy-container",
+		"keployNetwork":          "keploy-network",
+		"recordTimer":            "record-timer",
+		"urlMethods":             "url-methods",
+		"output":                 "output-path",
 	}
 
 	if newName, ok := flagNameMapping[name]; ok {
@@ -382,12 +563,23 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 		c.logger.Info("config file not found; proceeding with flags only")
 	}
 
-	if err := viper.Unmarshal(c.cfg); err != nil {
+	// the default decode hooks of viper, and the times of the config
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		config.StringToTimeHook(),
+	))
+	if err := viper.Unmarshal(c.cfg, decodeHook); err != nil {
 		errMsg := "failed to unmarshal the config"
 		utils.LogError(c.logger, err, errMsg)
 		return errors.New(errMsg)
 	}
 
+	if err := config.ValidateNamespace(c.cfg.Namespace); err != nil {
+		utils.LogError(c.logger, err, "invalid namespace")
+		return err
+	}
+
 	if c.cfg.Debug {
 		logger, err := log.ChangeLogLevel(zap.DebugLevel)
 		*c.logger = *logger
@@ -410,6 +602,13 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 		c.cfg.DisableTele = true
 	}
 
+	// the configs written before the telemetry batch size have none, they use the default batch size
+	if c.cfg.TeleBatchSize < 0 {
+		errMsg := fmt.Sprintf("invalid telemetry batch size %d, it should be at least 1", c.cfg.TeleBatchSize)
+		utils.LogError(c.logger, nil, errMsg)
+		return errors.New(errMsg)
+	}
+
 	if c.cfg.DisableANSI {
 		logger, err := log.ChangeColorEncoding()
 		*c.logger = *logger
@@ -421,13 +620,29 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 		c.logger.Info("Color encoding is disabled")
 	}
 
+	// NO_COLOR disables the colors when set to any non empty value, see https://no-color.org
+	if c.cfg.DisableColor || os.Getenv("NO_COLOR") != "" {
+		models.DisableColor()
+	}
+	if err := models.OverrideColorScheme(&models.PassingColorScheme, c.cfg.PassingColorScheme); err != nil {
+		errMsg := "invalid passing color scheme"
+		utils.LogError(c.logger, err, errMsg)
+		return fmt.Errorf("%s: %w", errMsg, err)
+	}
+	if err := models.OverrideColorScheme(&models.FailingColorScheme, c.cfg.FailingColorScheme); err != nil {
+		errMsg := "invalid failing color scheme"
+		utils.LogError(c.logger, err, errMsg)
+		return fmt.Errorf("%s: %w", errMsg, err)
+	}
+
 	c.logger.Debug("config has been initialised", zap.Any("for cmd", cmd.Name()), zap.Any("config", c.cfg))
 
 	switch cmd.Name() {
 	case "record", "test":
 
 		// handle the app command
-		if c.cfg.Command == "" {
+		// the dry run doesn't start the application
+		if c.cfg.Command == "" && !(cmd.Name() == "test" && c.cfg.Test.DryRun) {
 			if !alreadyRunning(cmd.Name(), c.cfg.Test.BasePath) {
 				return c.noCommandError()
 			}
@@ -436,6 +651,14 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 		// set the command type
 		c.cfg.CommandType = string(utils.FindDockerCmd(c.cfg.Command))
 
+		if c.cfg.Test.NameTemplate != "" {
+			if _, err := models.ParseNameTemplate(c.cfg.Test.NameTemplate); err != nil {
+				errMsg := fmt.Sprintf("invalid name template %q of the testcases, the fields are {{.Method}}, {{.URL}}, {{.StatusCode}} and {{.Index}}", c.cfg.Test.NameTemplate)
+				utils.LogError(c.logger, err, errMsg)
+				return fmt.Errorf("%s: %w", errMsg, err)
+			}
+		}
+
 		if c.cfg.GenerateGithubActions && utils.CmdType(c.cfg.CommandType) != utils.Empty {
 			defer utils.GenerateGithubActions(c.logger, c.cfg.Command)
 		}
@@ -502,8 +725,23 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 		config.SetByPassPorts(c.cfg, bypassPorts)
 
 		if cmd.Name() == "test" {
+			// the remote test sets are downloaded right before the test run, into a directory removed after it
+			for _, remoteURL := range []string{c.cfg.Test.RemoteTestSetURL, c.cfg.Test.RemoteReportURL} {
+				if remoteURL == "" {
+					continue
+				}
+				if err := utils.ValidateRemoteURL(remoteURL); err != nil {
+					errMsg := "invalid remote url"
+					utils.LogError(c.logger, err, errMsg, zap.String("url", remoteURL))
+					return fmt.Errorf("%s: %w", errMsg, err)
+				}
+			}
+			if c.cfg.Test.RemoteTestSetURL != "" && c.cfg.Test.RemoteReportURL == "" {
+				c.logger.Warn("the reports of the remote test sets are removed along with them after the test run, set remoteReportUrl to upload them")
+			}
+
 			//check if the keploy folder exists
-			if _, err := os.Stat(c.cfg.Path); os.IsNotExist(err) {
+			if _, err := os.Stat(config.StoragePath(c.cfg)); os.IsNotExist(err) && c.cfg.Test.RemoteTestSetURL == "" {
 				recordCmd := models.HighlightGrayString("keploy record")
 				errMsg := fmt.Sprintf("No test-sets found. Please record testcases using %s command", recordCmd)
 				utils.LogError(c.logger, nil, errMsg)
@@ -518,6 +756,239 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 			}
 			config.SetSelectedTests(c.cfg, testSets)
 
+			// the semver fields are listed in the noise config along with the type coerced fields
+			if cmd.Flags().Changed("semver-fields") {
+				c.cfg.Test.GlobalNoise.SemverFields, err = cmd.Flags().GetStringSlice("semver-fields")
+				if err != nil {
+					errMsg := "failed to get the semver fields"
+					utils.LogError(c.logger, err, errMsg)
+					return errors.New(errMsg)
+				}
+			}
+
+			if _, err := regexp.Compile(c.cfg.Test.TestNameFilter); err != nil {
+				errMsg := fmt.Sprintf("invalid test name filter %q", c.cfg.Test.TestNameFilter)
+				utils.LogError(c.logger, err, errMsg)
+				return fmt.Errorf("%s: %w", errMsg, err)
+			}
+
+			err = config.ValidateNoise(c.cfg.Test.GlobalNoise)
+			if err != nil {
+				utils.LogError(c.logger, err, "invalid noise config")
+				return err
+			}
+
+			accept, err := cmd.Flags().GetBool("accept")
+			if err != nil {
+				errMsg := "failed to get the accept flag"
+				utils.LogError(c.logger, err, errMsg)
+				return errors.New(errMsg)
+			}
+			c.cfg.Test.AutoAccept = c.cfg.Test.AutoAccept || accept
+			// accepting the failures silently updates the expectations, so it should never run in CI by accident
+			if c.cfg.Test.AutoAccept && os.Getenv("CI") != "" {
+				errMsg := "accept mode is not allowed in CI, as it updates the testcases with the actual responses. Please remove the --accept flag or autoAccept from the config"
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			outputFormat, err := cmd.Flags().GetString("output-format")
+			if err != nil {
+				errMsg := "failed to get the output format"
+				utils.LogError(c.logger, err, errMsg)
+				return errors.New(errMsg)
+			}
+			if cmd.Flags().Changed("output-format") {
+				c.cfg.Output.Format = outputFormat
+			}
+			switch c.cfg.Output.Format {
+			case "":
+				c.cfg.Output.Format = "text"
+			case "text", "json", "tap":
+			default:
+				errMsg := fmt.Sprintf("invalid output format %q, it should be text, json or tap", c.cfg.Output.Format)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			if c.cfg.Test.FloatAbsTolerance < 0 || c.cfg.Test.FloatRelTolerance < 0 {
+				errMsg := "the float tolerances should not be negative"
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+			// the consumed mocks are tracked per application, so they can't be attributed to the test cases running concurrently
+			if c.cfg.Test.Parallelism > 1 && c.cfg.Test.BasePath == "" {
+				c.logger.Warn("running the testcases serially, the parallelism is only supported with the base path as the outgoing calls of the app are mocked")
+				c.cfg.Test.Parallelism = 1
+			}
+
+			// the app and its mocks are shared by the test sets, so they can only run concurrently against the base path
+			if c.cfg.Test.MaxParallel > 1 && c.cfg.Test.BasePath == "" {
+				c.logger.Warn("running the test sets serially, running them concurrently is only supported with the base path as the outgoing calls of the app are mocked")
+				c.cfg.Test.MaxParallel = 1
+			}
+
+			// the snapshot is restored before every test case, which can't happen while other test cases are running
+			if c.cfg.Test.UseSnapshot && c.cfg.Test.Parallelism > 1 {
+				errMsg := "snapshots can't be used with parallel testcases, please remove --use-snapshot or --parallelism"
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			switch models.DedupStrategy(c.cfg.Test.Deduplication) {
+			case "", models.DedupExact, models.DedupSemantic:
+			default:
+				errMsg := fmt.Sprintf("invalid deduplication strategy %q, it should be exact or semantic", c.cfg.Test.Deduplication)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			switch models.MockMatchStrategy(c.cfg.Test.MockMatchStrategy) {
+			case "", models.FirstMatch, models.RoundRobin, models.Random:
+			default:
+				errMsg := fmt.Sprintf("invalid mock match strategy %q, it should be firstMatch, roundRobin or random", c.cfg.Test.MockMatchStrategy)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			if cmd.Flags().Changed("since") {
+				since, err := cmd.Flags().GetString("since")
+				if err != nil {
+					errMsg := "failed to get the since time"
+					utils.LogError(c.logger, err, errMsg)
+					return errors.New(errMsg)
+				}
+				c.cfg.Test.RunTestSetsSince, err = config.ParseTime(since)
+				if err != nil {
+					utils.LogError(c.logger, err, "invalid since time")
+					return err
+				}
+			}
+
+			// the configs written before the sharding have no shard total, they run all the test sets
+			if c.cfg.Test.ShardTotal == 0 {
+				c.cfg.Test.ShardTotal = 1
+			}
+			if c.cfg.Test.ShardTotal < 1 || c.cfg.Test.ShardIndex < 0 || c.cfg.Test.ShardIndex >= c.cfg.Test.ShardTotal {
+				errMsg := fmt.Sprintf("invalid shard %d of %d, the shard total should be at least 1 and the shard index between 0 and the shard total - 1", c.cfg.Test.ShardIndex, c.cfg.Test.ShardTotal)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			if c.cfg.Test.ReadinessLogPattern != "" {
+				if _, err := regexp.Compile(c.cfg.Test.ReadinessLogPattern); err != nil {
+					errMsg := fmt.Sprintf("invalid readiness log pattern %q", c.cfg.Test.ReadinessLogPattern)
+					utils.LogError(c.logger, err, errMsg)
+					return fmt.Errorf("%s: %w", errMsg, err)
+				}
+			}
+
+			if c.cfg.Test.ReadinessProbe != "" {
+				probe, err := url.Parse(c.cfg.Test.ReadinessProbe)
+				if err != nil || (probe.Scheme != "http" && probe.Scheme != "https") || probe.Host == "" {
+					errMsg := fmt.Sprintf("invalid readiness probe %q, it should be a http or https url", c.cfg.Test.ReadinessProbe)
+					utils.LogError(c.logger, nil, errMsg)
+					return errors.New(errMsg)
+				}
+			}
+
+			if c.cfg.Test.CoverageDriver != "" && c.cfg.Test.CoverageDriver != "istanbul" {
+				errMsg := fmt.Sprintf("invalid coverage driver %q, only istanbul is supported", c.cfg.Test.CoverageDriver)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			for testSetID, rules := range c.cfg.Test.Chaining {
+				for _, rule := range rules {
+					if rule.TestCase == "" || rule.Path == "" || rule.Var == "" {
+						errMsg := fmt.Sprintf("invalid chaining rule of the test set %s, the testCase, the path and the var should be set", testSetID)
+						utils.LogError(c.logger, nil, errMsg)
+						return errors.New(errMsg)
+					}
+				}
+			}
+
+			if c.cfg.Test.RandomRuns < 1 {
+				errMsg := fmt.Sprintf("invalid number of the random runs %d, it should be at least 1", c.cfg.Test.RandomRuns)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			if c.cfg.Test.AutoDenoise {
+				if c.cfg.Test.DenoiseRuns < 1 {
+					errMsg := fmt.Sprintf("invalid number of the denoise runs %d, it should be at least 1", c.cfg.Test.DenoiseRuns)
+					utils.LogError(c.logger, nil, errMsg)
+					return errors.New(errMsg)
+				}
+				if c.cfg.Test.DenoiseThreshold < 0 || c.cfg.Test.DenoiseThreshold >= 1 {
+					errMsg := fmt.Sprintf("invalid denoise threshold %v, it should be a fraction of the runs between 0 and 1", c.cfg.Test.DenoiseThreshold)
+					utils.LogError(c.logger, nil, errMsg)
+					return errors.New(errMsg)
+				}
+			}
+
+			if c.cfg.Test.CoverageMerge && !c.cfg.Test.GoCoverage {
+				c.logger.Warn("the coverage merge is ignored as the go coverage is not enabled, use --go-coverage to enable it")
+			}
+
+			if c.cfg.Test.MinCoverage < 0 || c.cfg.Test.MinCoverage > 100 {
+				errMsg := fmt.Sprintf("invalid minimum coverage %v, it should be a percentage between 0 and 100", c.cfg.Test.MinCoverage)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			switch c.cfg.Test.SemverTolerance {
+			case "", "patch", "minor", "major":
+			default:
+				errMsg := fmt.Sprintf("invalid semver tolerance %q, it should be patch, minor or major", c.cfg.Test.SemverTolerance)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			if signing := c.cfg.Test.RequestSigning; signing != nil {
+				switch strings.ToLower(signing.Algorithm) {
+				case "hmac-sha256", "hmac-sha512":
+				default:
+					errMsg := fmt.Sprintf("invalid request signing algorithm %q, it should be hmac-sha256 or hmac-sha512", signing.Algorithm)
+					utils.LogError(c.logger, nil, errMsg)
+					return errors.New(errMsg)
+				}
+				if signing.SecretKey == "" || signing.SignatureHeader == "" {
+					errMsg := "request signing requires the secret key and the signature header"
+					utils.LogError(c.logger, nil, errMsg)
+					return errors.New(errMsg)
+				}
+			}
+
+			if c.cfg.Test.ClientCert != "" || c.cfg.Test.ClientKey != "" || c.cfg.Test.CACert != "" || c.cfg.Test.InsecureSkipVerify {
+				if c.cfg.Test.BasePath == "" {
+					c.logger.Warn("the client tls config is only used with the base path, it is ignored")
+				} else if _, err := pkg.ClientTLSConfig(c.cfg.Test.ClientCert, c.cfg.Test.ClientKey, c.cfg.Test.ClientKeyPassphrase, c.cfg.Test.CACert, c.cfg.Test.InsecureSkipVerify); err != nil {
+					errMsg := "invalid client tls config"
+					utils.LogError(c.logger, err, errMsg)
+					return fmt.Errorf("%s: %w", errMsg, err)
+				}
+				if c.cfg.Test.InsecureSkipVerify {
+					c.logger.Warn("the certificate of the app at the base path is not verified, as insecureSkipVerify is set")
+				}
+			}
+
+			if c.cfg.Test.ReferenceBasePath != "" && c.cfg.Test.BasePath == "" {
+				errMsg := "reference base path requires the base path of the new implementation, please provide it with --base-path"
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
+			switch models.ReportOverwritePolicy(c.cfg.Test.ReportOverwritePolicy) {
+			case models.ReportOverwrite, models.ReportAppend, models.ReportError:
+			case "":
+				c.cfg.Test.ReportOverwritePolicy = string(models.ReportOverwrite)
+			default:
+				errMsg := fmt.Sprintf("invalid report overwrite policy: %s, should be one of overwrite, append or error", c.cfg.Test.ReportOverwritePolicy)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+
 			if utils.CmdType(c.cfg.CommandType) == utils.Native && c.cfg.Test.GoCoverage {
 				goCovPath, err := utils.SetCoveragePath(c.logger, c.cfg.Test.CoverageReportPath)
 				if err != nil {
@@ -536,7 +1007,7 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 				}
 			}
 		}
-	case "normalize":
+	case "normalize", "report", "group", "graph", "encrypt", "decrypt":
 		path := c.cfg.Path
 		//if user provides relative path
 		if len(path) > 0 && path[0] != '/' {
@@ -554,6 +1025,36 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 		}
 		path += "/keploy"
 		c.cfg.Path = path
+		if cmd.Name() == "report" {
+			return c.validateReportFlags(cmd)
+		}
+		if cmd.Name() == "group" {
+			return nil
+		}
+		if cmd.Name() == "encrypt" || cmd.Name() == "decrypt" {
+			if c.cfg.MockEncryption == nil {
+				errMsg := "the mock encryption is not configured, set the mockEncryption algorithm and keyFile in the keploy config"
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+			return nil
+		}
+		if cmd.Name() == "graph" {
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				errMsg := "failed to get the graph format"
+				utils.LogError(c.logger, err, errMsg)
+				return errors.New(errMsg)
+			}
+			switch format {
+			case "dot", "mermaid", "json":
+			default:
+				errMsg := fmt.Sprintf("invalid graph format %q, it should be dot, mermaid or json", format)
+				utils.LogError(c.logger, nil, errMsg)
+				return errors.New(errMsg)
+			}
+			return nil
+		}
 		tests, err := cmd.Flags().GetString("tests")
 		if err != nil {
 			errMsg := "failed to read tests to be normalized"
@@ -566,6 +1067,13 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 			utils.LogError(c.logger, err, errMsg)
 			return errors.New(errMsg)
 		}
+		switch models.NormalizeStrategy(c.cfg.Normalize.Strategy) {
+		case "", models.NormalizeFull, models.NormalizePartial:
+		default:
+			errMsg := fmt.Sprintf("invalid normalization strategy %q, it should be full or partial", c.cfg.Normalize.Strategy)
+			utils.LogError(c.logger, nil, errMsg)
+			return errors.New(errMsg)
+		}
 	case "gen":
 		if os.Getenv("API_KEY") == "" {
 			utils.LogError(c.logger, nil, "API_KEY is not set")
@@ -583,3 +1091,35 @@ func (c *CmdConfigurator) ValidateFlags(ctx context.Context, cmd *cobra.Command)
 	}
 	return nil
 }
+
+func (c *CmdConfigurator) validateReportFlags(cmd *cobra.Command) error {
+	testSets, err := cmd.Flags().GetStringSlice("test-sets")
+	if err != nil {
+		errMsg := "failed to get the testsets"
+		utils.LogError(c.logger, err, errMsg)
+		return errors.New(errMsg)
+	}
+	c.cfg.Report.SelectedTestSets = testSets
+
+	lastN, err := cmd.Flags().GetInt("last-n")
+	if err != nil {
+		errMsg := "failed to get the number of test runs"
+		utils.LogError(c.logger, err, errMsg)
+		return errors.New(errMsg)
+	}
+	c.cfg.Report.StabilityRuns = lastN
+
+	threshold, err := cmd.Flags().GetFloat64("flaky-threshold")
+	if err != nil {
+		errMsg := "failed to get the flaky threshold"
+		utils.LogError(c.logger, err, errMsg)
+		return errors.New(errMsg)
+	}
+	if threshold < 0 || threshold > 1 {
+		errMsg := fmt.Sprintf("invalid flaky threshold: %v, should be between 0 and 1", threshold)
+		utils.LogError(c.logger, nil, errMsg)
+		return errors.New(errMsg)
+	}
+	c.cfg.Report.FlakyThreshold = threshold
+	return nil
+}
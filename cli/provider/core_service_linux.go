@@ -16,6 +16,7 @@ import (
 	"go.keploy.io/server/v2/pkg/models"
 	"go.keploy.io/server/v2/pkg/platform/docker"
 	"go.keploy.io/server/v2/pkg/platform/telemetry"
+	"go.keploy.io/server/v2/pkg/platform/yaml"
 	"go.keploy.io/server/v2/pkg/platform/yaml/configdb/testset"
 	mockdb "go.keploy.io/server/v2/pkg/platform/yaml/mockdb"
 	reportdb "go.keploy.io/server/v2/pkg/platform/yaml/reportdb"
@@ -42,8 +43,17 @@ func Get(ctx context.Context, cmd string, cfg *config.Config, logger *zap.Logger
 	if cmd == "record" {
 		return record.New(logger, commonServices.YamlTestDB, commonServices.YamlMockDb, tel, commonServices.Instrumentation, cfg), nil
 	}
-	if cmd == "test" || cmd == "normalize" {
-		return replay.NewReplayer(logger, commonServices.YamlTestDB, commonServices.YamlMockDb, commonServices.YamlReportDb, commonServices.YamlTestSetDB, tel, commonServices.Instrumentation, cfg), nil
+	if cmd == "test" || cmd == "normalize" || cmd == "report" || cmd == "mock" || cmd == "graph" {
+		var testDB replay.TestDB = commonServices.YamlTestDB
+		// the test cases shared from the additional paths are read alongside the ones of the project
+		if len(cfg.AdditionalTestPaths) > 0 {
+			testDBs := []replay.TestDB{commonServices.YamlTestDB}
+			for _, path := range cfg.AdditionalTestPaths {
+				testDBs = append(testDBs, testdb.New(logger, path))
+			}
+			testDB = replay.NewMergedTestDB(testDBs)
+		}
+		return replay.NewReplayer(logger, testDB, commonServices.YamlMockDb, commonServices.YamlReportDb, commonServices.YamlTestSetDB, tel, commonServices.Instrumentation, cfg), nil
 	}
 	return nil, errors.New("invalid command")
 }
@@ -91,10 +101,27 @@ func GetCommonServices(ctx context.Context, c *config.Config, logger *zap.Logger
 	}
 
 	instrumentation := core.New(logger, h, p, t, client)
-	testDB := testdb.New(logger, c.Path)
-	mockDB := mockdb.New(logger, c.Path, "")
-	reportDB := reportdb.New(logger, c.Path+"/reports")
-	testSetDb := testset.New[*models.TestSet](logger, c.Path)
+	// the test sets and the reports of the namespace are kept apart from the other namespaces sharing the path
+	storagePath := config.StoragePath(c)
+	testDB := testdb.New(logger, storagePath)
+	if c.Test.NameTemplate != "" {
+		testDB.NameTemplate, err = models.ParseNameTemplate(c.Test.NameTemplate)
+		if err != nil {
+			utils.LogError(logger, err, "failed to parse the name template of the testcases")
+			return nil, err
+		}
+	}
+	mockDB := mockdb.New(logger, storagePath, "")
+	if c.MockEncryption != nil {
+		cipher, err := yaml.NewCipher(c.MockEncryption.Algorithm, c.MockEncryption.KeyFile)
+		if err != nil {
+			utils.LogError(logger, err, "failed to create the cipher of the mock encryption")
+			return nil, err
+		}
+		mockDB.Cipher = cipher
+	}
+	reportDB := reportdb.New(logger, storagePath+"/reports", models.ReportOverwritePolicy(c.Test.ReportOverwritePolicy))
+	testSetDb := testset.New[*models.TestSet](logger, storagePath)
 	return &CommonInternalService{
 		Instrumentation: instrumentation,
 		YamlTestDB:      testDB,
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.keploy.io/server/v2/config"
+	replaySvc "go.keploy.io/server/v2/pkg/service/replay"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("report", Report)
+}
+
+// Report retrieves the command to report the stability of the test sets over the past test runs
+func Report(ctx context.Context, logger *zap.Logger, cfg *config.Config, serviceFactory ServiceFactory, cmdConfigurator CmdConfigurator) *cobra.Command {
+	var reportCmd = &cobra.Command{
+		Use:     "report",
+		Short:   "Report the stability and the flaky testcases of the test sets over the past test runs, and the mock fidelity of the latest one",
+		Example: "keploy report --test-sets test-set-1,test-set-2 --last-n 10 --flaky-threshold 0.9",
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return cmdConfigurator.ValidateFlags(ctx, cmd)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			svc, err := serviceFactory.GetService(ctx, cmd.Name())
+			if err != nil {
+				utils.LogError(logger, err, "failed to get service")
+				return nil
+			}
+			var replay replaySvc.Service
+			var ok bool
+			if replay, ok = svc.(replaySvc.Service); !ok {
+				utils.LogError(logger, nil, "service doesn't satisfy replay service interface")
+				return nil
+			}
+
+			testSetIDs := cfg.Report.SelectedTestSets
+			if len(testSetIDs) == 0 {
+				testSetIDs, err = replay.GetAllTestSetIDs(ctx)
+				if err != nil {
+					utils.LogError(logger, err, "failed to get all test set ids")
+					return nil
+				}
+			}
+
+			for _, testSetID := range testSetIDs {
+				score, err := replay.GetStabilityScore(ctx, testSetID, cfg.Report.StabilityRuns)
+				if err != nil {
+					utils.LogError(logger, err, "failed to get the stability score", zap.String("test-set", testSetID))
+					continue
+				}
+				flaky, err := replay.ListFlakyTestCases(ctx, testSetID, cfg.Report.StabilityRuns, cfg.Report.FlakyThreshold)
+				if err != nil {
+					utils.LogError(logger, err, "failed to list the flaky test cases", zap.String("test-set", testSetID))
+					continue
+				}
+				logger.Info(fmt.Sprintf("stability score of %s over the last %d test runs: %.2f", testSetID, cfg.Report.StabilityRuns, score), zap.Strings("flaky test cases", flaky))
+			}
+
+			fidelity, err := replay.GetMockFidelityReport(ctx, "")
+			if err != nil {
+				utils.LogError(logger, err, "failed to get the mock fidelity report")
+				return nil
+			}
+			logger.Info(fmt.Sprintf("mock fidelity of %s: %d perfectly matched, %d fuzzily matched and %d unmatched of %d mocks", fidelity.TestRunID, fidelity.PerfectlyMatchedMocks, fidelity.FuzzilyMatchedMocks, fidelity.UnmatchedMocks, fidelity.TotalMocks))
+			return nil
+		},
+	}
+	if err := cmdConfigurator.AddFlags(reportCmd); err != nil {
+		utils.LogError(logger, err, "failed to add report cmd flags")
+		return nil
+	}
+	return reportCmd
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"go.keploy.io/server/v2/utils"
@@ -26,6 +27,23 @@ func Test(ctx context.Context, logger *zap.Logger, cfg *config.Config, serviceFa
 			return cmdConfigurator.Validate(ctx, cmd)
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			// download the test sets when they are stored remotely, before the services read them
+			var remoteDir string
+			if cfg.Test.RemoteTestSetURL != "" {
+				var err error
+				remoteDir, err = os.MkdirTemp("", "keploy-remote-")
+				if err != nil {
+					utils.LogError(logger, err, "failed to create a temporary directory for the remote test sets")
+					return nil
+				}
+				defer os.RemoveAll(remoteDir)
+				cfg.Path, err = utils.DownloadTestSets(ctx, logger, cfg.Test.RemoteTestSetURL, remoteDir)
+				if err != nil {
+					utils.LogError(logger, err, "failed to download the remote test sets", zap.String("url", cfg.Test.RemoteTestSetURL))
+					return nil
+				}
+			}
+
 			svc, err := serviceFactory.GetService(ctx, cmd.Name())
 			if err != nil {
 				utils.LogError(logger, err, "failed to get service")
@@ -58,6 +76,14 @@ func Test(ctx context.Context, logger *zap.Logger, cfg *config.Config, serviceFa
 			err = replay.Start(ctx)
 			if err != nil {
 				utils.LogError(logger, err, "failed to replay")
+				// the coverage threshold fails the CI pipelines running the tests
+				if errors.Is(err, replaySvc.ErrCoverageBelowThreshold) {
+					// the deferred removal does not run on exit
+					if remoteDir != "" {
+						os.RemoveAll(remoteDir)
+					}
+					os.Exit(1)
+				}
 				return nil
 			}
 
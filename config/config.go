@@ -3,35 +3,49 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
 	"time"
+
+	"github.com/mitchellh/mapstructure"
 )
 
 type Config struct {
-	Path                  string       `json:"path" yaml:"path" mapstructure:"path"`
-	AppID                 string       `json:"appId" yaml:"appId" mapstructure:"appId"`
-	Command               string       `json:"command" yaml:"command" mapstructure:"command"`
-	Port                  uint32       `json:"port" yaml:"port" mapstructure:"port"`
-	DNSPort               uint32       `json:"dnsPort" yaml:"dnsPort" mapstructure:"dnsPort"`
-	ProxyPort             uint32       `json:"proxyPort" yaml:"proxyPort" mapstructure:"proxyPort"`
-	Debug                 bool         `json:"debug" yaml:"debug" mapstructure:"debug"`
-	DisableTele           bool         `json:"disableTele" yaml:"disableTele" mapstructure:"disableTele"`
-	DisableANSI           bool         `json:"disableANSI" yaml:"disableANSI" mapstructure:"disableANSI"`
-	InDocker              bool         `json:"inDocker" yaml:"inDocker" mapstructure:"inDocker"`
-	ContainerName         string       `json:"containerName" yaml:"containerName" mapstructure:"containerName"`
-	NetworkName           string       `json:"networkName" yaml:"networkName" mapstructure:"networkName"`
-	BuildDelay            uint64       `json:"buildDelay" yaml:"buildDelay" mapstructure:"buildDelay"`
-	Test                  Test         `json:"test" yaml:"test" mapstructure:"test"`
-	Record                Record       `json:"record" yaml:"record" mapstructure:"record"`
-	Gen                   UtGen        `json:"gen" yaml:"gen" mapstructure:"gen"`
-	Normalize             Normalize    `json:"normalize" yaml:"normalize" mapstructure:"normalize"`
-	ConfigPath            string       `json:"configPath" yaml:"configPath" mapstructure:"configPath"`
-	BypassRules           []BypassRule `json:"bypassRules" yaml:"bypassRules" mapstructure:"bypassRules"`
-	EnableTesting         bool         `json:"enableTesting" yaml:"enableTesting" mapstructure:"enableTesting"`
-	GenerateGithubActions bool         `json:"generateGithubActions" yaml:"generateGithubActions" mapstructure:"generateGithubActions"`
-	KeployContainer       string       `json:"keployContainer" yaml:"keployContainer" mapstructure:"keployContainer"`
-	KeployNetwork         string       `json:"keployNetwork" yaml:"keployNetwork" mapstructure:"keployNetwork"`
-	CommandType           string       `json:"cmdType" yaml:"cmdType" mapstructure:"cmdType"`
+	Path                  string            `json:"path" yaml:"path" mapstructure:"path"`
+	Namespace             string            `json:"namespace" yaml:"namespace" mapstructure:"namespace"` // isolates the test sets and the reports of a team sharing the path with others
+	AppID                 string            `json:"appId" yaml:"appId" mapstructure:"appId"`
+	Command               string            `json:"command" yaml:"command" mapstructure:"command"`
+	Port                  uint32            `json:"port" yaml:"port" mapstructure:"port"`
+	DNSPort               uint32            `json:"dnsPort" yaml:"dnsPort" mapstructure:"dnsPort"`
+	ProxyPort             uint32            `json:"proxyPort" yaml:"proxyPort" mapstructure:"proxyPort"`
+	Debug                 bool              `json:"debug" yaml:"debug" mapstructure:"debug"`
+	DisableTele           bool              `json:"disableTele" yaml:"disableTele" mapstructure:"disableTele"`
+	TeleBatchSize         int               `json:"teleBatchSize" yaml:"teleBatchSize" mapstructure:"teleBatchSize"` // number of the test run events sent together in a single telemetry request
+	DisableANSI           bool              `json:"disableANSI" yaml:"disableANSI" mapstructure:"disableANSI"`
+	DisableColor          bool              `json:"disableColor" yaml:"disableColor" mapstructure:"disableColor"`                   // print the summaries and the results of the test cases as plain text, also set by the NO_COLOR environment variable
+	PassingColorScheme    map[string]string `json:"passingColorScheme" yaml:"passingColorScheme" mapstructure:"passingColorScheme"` // colors of the passing results by field of the pp color scheme, e.g. string: green|bold
+	FailingColorScheme    map[string]string `json:"failingColorScheme" yaml:"failingColorScheme" mapstructure:"failingColorScheme"` // colors of the failing results by field of the pp color scheme
+	InDocker              bool              `json:"inDocker" yaml:"inDocker" mapstructure:"inDocker"`
+	ContainerName         string            `json:"containerName" yaml:"containerName" mapstructure:"containerName"`
+	NetworkName           string            `json:"networkName" yaml:"networkName" mapstructure:"networkName"`
+	BuildDelay            uint64            `json:"buildDelay" yaml:"buildDelay" mapstructure:"buildDelay"`
+	AdditionalTestPaths   []string          `json:"additionalTestPaths" yaml:"additionalTestPaths" mapstructure:"additionalTestPaths"` // keploy directories whose test cases are merged with the ones of the path, e.g. shared by a common library
+	MockEncryption        *EncryptionConfig `json:"mockEncryption" yaml:"mockEncryption" mapstructure:"mockEncryption"`                // encrypts the mock files at rest
+	Test                  Test              `json:"test" yaml:"test" mapstructure:"test"`
+	Record                Record            `json:"record" yaml:"record" mapstructure:"record"`
+	Gen                   UtGen             `json:"gen" yaml:"gen" mapstructure:"gen"`
+	Normalize             Normalize         `json:"normalize" yaml:"normalize" mapstructure:"normalize"`
+	Report                Report            `json:"report" yaml:"report" mapstructure:"report"`
+	Output                Output            `json:"output" yaml:"output" mapstructure:"output"`
+	ConfigPath            string            `json:"configPath" yaml:"configPath" mapstructure:"configPath"`
+	BypassRules           []BypassRule      `json:"bypassRules" yaml:"bypassRules" mapstructure:"bypassRules"`
+	EnableTesting         bool              `json:"enableTesting" yaml:"enableTesting" mapstructure:"enableTesting"`
+	GenerateGithubActions bool              `json:"generateGithubActions" yaml:"generateGithubActions" mapstructure:"generateGithubActions"`
+	KeployContainer       string            `json:"keployContainer" yaml:"keployContainer" mapstructure:"keployContainer"`
+	KeployNetwork         string            `json:"keployNetwork" yaml:"keployNetwork" mapstructure:"keployNetwork"`
+	CommandType           string            `json:"cmdType" yaml:"cmdType" mapstructure:"cmdType"`
 }
 
 type UtGen struct {
@@ -57,6 +71,17 @@ type Record struct {
 type Normalize struct {
 	SelectedTests []SelectedTests `json:"selectedTests" yaml:"selectedTests" mapstructure:"selectedTests"`
 	TestRun       string          `json:"testReport" yaml:"testReport" mapstructure:"testReport"`
+	Strategy      string          `json:"strategy" yaml:"strategy" mapstructure:"strategy"` // full replaces the recorded response, partial only updates its fields which did not match
+}
+
+type Report struct {
+	SelectedTestSets []string `json:"selectedTestSets" yaml:"selectedTestSets" mapstructure:"selectedTestSets"`
+	StabilityRuns    int      `json:"stabilityRuns" yaml:"stabilityRuns" mapstructure:"stabilityRuns"`    // number of the latest test runs the stability score is computed over
+	FlakyThreshold   float64  `json:"flakyThreshold" yaml:"flakyThreshold" mapstructure:"flakyThreshold"` // test cases passing less than this ratio of the runs are reported as flaky
+}
+
+type Output struct {
+	Format string `json:"format" yaml:"format" mapstructure:"format"` // text for the terminals, json lines for the log aggregators or tap for the TAP consumers
 }
 
 type BypassRule struct {
@@ -72,25 +97,128 @@ type Filter struct {
 }
 
 type Test struct {
-	SelectedTests      map[string][]string `json:"selectedTests" yaml:"selectedTests" mapstructure:"selectedTests"`
-	GlobalNoise        Globalnoise         `json:"globalNoise" yaml:"globalNoise" mapstructure:"globalNoise"`
-	Delay              uint64              `json:"delay" yaml:"delay" mapstructure:"delay"`
-	APITimeout         uint64              `json:"apiTimeout" yaml:"apiTimeout" mapstructure:"apiTimeout"`
-	Coverage           bool                `json:"coverage" yaml:"coverage" mapstructure:"coverage"`                               // boolean to capture the coverage in test
-	CoverageReportPath string              `json:"coverageReportPath" yaml:"coverageReportPath" mapstructure:"coverageReportPath"` // directory path to store the coverage files
-	GoCoverage         bool                `json:"goCoverage" yaml:"goCoverage" mapstructure:"goCoverage"`                         // boolean to capture the coverage in test
-	IgnoreOrdering     bool                `json:"ignoreOrdering" yaml:"ignoreOrdering" mapstructure:"ignoreOrdering"`
-	MongoPassword      string              `json:"mongoPassword" yaml:"mongoPassword" mapstructure:"mongoPassword"`
-	Language           string              `json:"language" yaml:"language" mapstructure:"language"`
-	RemoveUnusedMocks  bool                `json:"removeUnusedMocks" yaml:"removeUnusedMocks" mapstructure:"removeUnusedMocks"`
-	FallBackOnMiss     bool                `json:"fallBackOnMiss" yaml:"fallBackOnMiss" mapstructure:"fallBackOnMiss"`
-	BasePath           string              `json:"basePath" yaml:"basePath" mapstructure:"basePath"`
-	Mocking            bool                `json:"mocking" yaml:"mocking" mapstructure:"mocking"`
+	SelectedTests          map[string][]string      `json:"selectedTests" yaml:"selectedTests" mapstructure:"selectedTests"`
+	TestNameFilter         string                   `json:"testNameFilter" yaml:"testNameFilter" mapstructure:"testNameFilter"` // regular expression the names of the selected test cases must match to run
+	Deduplication          string                   `json:"deduplication" yaml:"deduplication" mapstructure:"deduplication"`    // exact or semantic, delete the duplicate test cases of the test sets before running them
+	DryRun                 bool                     `json:"dryRun" yaml:"dryRun" mapstructure:"dryRun"`                         // list the test sets and the test cases which would run without starting the application
+	NameTemplate           string                   `json:"nameTemplate" yaml:"nameTemplate" mapstructure:"nameTemplate"`       // text/template of the names of the test cases e.g. {{.Method}}-{{.URL}}-{{.StatusCode}}, with {{.Index}} as well
+	GlobalNoise            Globalnoise              `json:"globalNoise" yaml:"globalNoise" mapstructure:"globalNoise"`
+	Delay                  uint64                   `json:"delay" yaml:"delay" mapstructure:"delay"`
+	ReadinessProbe         string                   `json:"readinessProbe" yaml:"readinessProbe" mapstructure:"readinessProbe"`                // url polled until the app responds with a 2xx status code, instead of waiting for the delay
+	ReadinessLogPattern    string                   `json:"readinessLogPattern" yaml:"readinessLogPattern" mapstructure:"readinessLogPattern"` // regular expression of the line the app logs to its stdout or stderr once ready, waited for instead of the delay
+	ReadinessTimeout       time.Duration            `json:"readinessTimeout" yaml:"readinessTimeout" mapstructure:"readinessTimeout"`          // maximum time the readiness probe is polled, the testcases run anyway after it
+	MaxResponseBodyKB      int                      `json:"maxResponseBodyKB" yaml:"maxResponseBodyKB" mapstructure:"maxResponseBodyKB"`       // size in kilobytes the response bodies are truncated at, the bodies of that size are not compared
+	APITimeout             uint64                   `json:"apiTimeout" yaml:"apiTimeout" mapstructure:"apiTimeout"`
+	TestSetTimeout         time.Duration            `json:"testSetTimeout" yaml:"testSetTimeout" mapstructure:"testSetTimeout"`             // wall-clock limit of a test set, the test set is stopped and reported as timed out when exceeded
+	MockTTL                time.Duration            `json:"mockTTL" yaml:"mockTTL" mapstructure:"mockTTL"`                                  // the mocks recorded longer ago than it, plus their own ttl, are stale and not replayed, 0 for no expiry
+	LatencyBudget          time.Duration            `json:"latencyBudget" yaml:"latencyBudget" mapstructure:"latencyBudget"`                // latency budget of every test case, a slower response fails the test case
+	LatencyBudgets         map[string]time.Duration `json:"latencyBudgets" yaml:"latencyBudgets" mapstructure:"latencyBudgets"`             // latency budgets by test set id or by <test-set>/<test-case>, overriding the latency budget
+	Chaining               map[string][]ChainRule   `json:"chaining" yaml:"chaining" mapstructure:"chaining"`                               // request chaining rules by test set id, the values of the responses are fed into the later requests
+	Coverage               bool                     `json:"coverage" yaml:"coverage" mapstructure:"coverage"`                               // boolean to capture the coverage in test
+	CoverageReportPath     string                   `json:"coverageReportPath" yaml:"coverageReportPath" mapstructure:"coverageReportPath"` // directory path to store the coverage files
+	CoverageDriver         string                   `json:"coverageDriver" yaml:"coverageDriver" mapstructure:"coverageDriver"`             // istanbul reads the coverage of the js apps from their /__coverage__ endpoint after each test case
+	GoCoverage             bool                     `json:"goCoverage" yaml:"goCoverage" mapstructure:"goCoverage"`                         // boolean to capture the coverage in test
+	MinCoverage            float64                  `json:"minCoverage" yaml:"minCoverage" mapstructure:"minCoverage"`                      // minimum go coverage percentage of the test run, below it the test run fails, 0 for no minimum
+	CoverageMerge          bool                     `json:"coverageMerge" yaml:"coverageMerge" mapstructure:"coverageMerge"`                // merge the go coverage of the test run into the cumulative coverage of the previous runs in the coverage directory
+	IgnoreOrdering         bool                     `json:"ignoreOrdering" yaml:"ignoreOrdering" mapstructure:"ignoreOrdering"`
+	IgnoreExtraFields      bool                     `json:"ignoreExtraFields" yaml:"ignoreExtraFields" mapstructure:"ignoreExtraFields"` // only compare the fields of the recorded responses, the fields added to the actual responses are ignored
+	FloatAbsTolerance      float64                  `json:"floatAbsTolerance" yaml:"floatAbsTolerance" mapstructure:"floatAbsTolerance"` // non-integral numbers in the json bodies are equal when they differ by at most the tolerance
+	FloatRelTolerance      float64                  `json:"floatRelTolerance" yaml:"floatRelTolerance" mapstructure:"floatRelTolerance"` // non-integral numbers in the json bodies are equal when they differ by at most the tolerance times the larger of them
+	SemverTolerance        string                   `json:"semverTolerance" yaml:"semverTolerance" mapstructure:"semverTolerance"`       // patch, minor or major, the difference of the semver fields tolerated up to that part of the version
+	BodyComparator         string                   `json:"bodyComparator" yaml:"bodyComparator" mapstructure:"bodyComparator"`          // exact, subset or regex, the comparison of the response bodies
+	GraphQLMode            bool                     `json:"graphQLMode" yaml:"graphQLMode" mapstructure:"graphQLMode"`                   // compare the data of the graphql responses and fail the ones with errors, whatever their status code
+	MongoPassword          string                   `json:"mongoPassword" yaml:"mongoPassword" mapstructure:"mongoPassword"`
+	Language               string                   `json:"language" yaml:"language" mapstructure:"language"`
+	RemoveUnusedMocks      bool                     `json:"removeUnusedMocks" yaml:"removeUnusedMocks" mapstructure:"removeUnusedMocks"`
+	MockMatchStrategy      string                   `json:"mockMatchStrategy" yaml:"mockMatchStrategy" mapstructure:"mockMatchStrategy"` // firstMatch, roundRobin or random, the order of the mocks matching the same outgoing call
+	AutoSortMocks          bool                     `json:"autoSortMocks" yaml:"autoSortMocks" mapstructure:"autoSortMocks"`             // sort the mocks by the timestamp of their requests when they are out of order
+	TimeShiftReplay        bool                     `json:"timeShiftReplay" yaml:"timeShiftReplay" mapstructure:"timeShiftReplay"`       // shift the timestamps of the test cases and the mocks so that the test set replays as if recorded now
+	FallBackOnMiss         bool                     `json:"fallBackOnMiss" yaml:"fallBackOnMiss" mapstructure:"fallBackOnMiss"`
+	BasePath               string                   `json:"basePath" yaml:"basePath" mapstructure:"basePath"`
+	ReferenceBasePath      string                   `json:"referenceBasePath" yaml:"referenceBasePath" mapstructure:"referenceBasePath"` // base path of the reference implementation, the responses are compared with its live responses instead of the recorded ones
+	Mocking                bool                     `json:"mocking" yaml:"mocking" mapstructure:"mocking"`
+	RecordMissingTestCases bool                     `json:"recordMissingTestCases" yaml:"recordMissingTestCases" mapstructure:"recordMissingTestCases"` // record the response of the test cases which don't have one instead of testing them
+	ReportOverwritePolicy  string                   `json:"reportOverwritePolicy" yaml:"reportOverwritePolicy" mapstructure:"reportOverwritePolicy"`    // overwrite, append or error when a report already exists for the test run and test set
+	CIPRComment            *PRCommentConfig         `json:"ciPRComment" yaml:"ciPRComment" mapstructure:"ciPRComment"`                                  // post the test run summary as a comment on the pull request
+	RequestSigning         *RequestSigningConfig    `json:"requestSigning" yaml:"requestSigning" mapstructure:"requestSigning"`                         // sign the replayed requests for the hmac authenticated apis
+	GlobalHeaders          map[string]string        `json:"globalHeaders" yaml:"globalHeaders" mapstructure:"globalHeaders"`                            // headers added to every replayed http request, e.g. X-Env, the headers of the test cases take precedence
+	OverrideHeaders        bool                     `json:"overrideHeaders" yaml:"overrideHeaders" mapstructure:"overrideHeaders"`                      // the global headers replace the headers of the test cases with the same name
+	ClientCert             string                   `json:"clientCert" yaml:"clientCert" mapstructure:"clientCert"`                                     // PEM client certificate presented to the app at the base path requiring mutual tls
+	ClientKey              string                   `json:"clientKey" yaml:"clientKey" mapstructure:"clientKey"`                                        // PEM private key of the client certificate
+	ClientKeyPassphrase    string                   `json:"clientKeyPassphrase" yaml:"clientKeyPassphrase" mapstructure:"clientKeyPassphrase"`          // passphrase of the encrypted private key
+	CACert                 string                   `json:"caCert" yaml:"caCert" mapstructure:"caCert"`                                                 // PEM CA certificate the certificate of the app at the base path is verified against, the system CAs when empty
+	InsecureSkipVerify     bool                     `json:"insecureSkipVerify" yaml:"insecureSkipVerify" mapstructure:"insecureSkipVerify"`             // skip the verification of the certificate of the app at the base path
+	AutoDenoise            bool                     `json:"autoDenoise" yaml:"autoDenoise" mapstructure:"autoDenoise"`                                  // replay the test sets DenoiseRuns times and add the fields differing in the runs to the noise of the test cases
+	DenoiseRuns            int                      `json:"denoiseRuns" yaml:"denoiseRuns" mapstructure:"denoiseRuns"`                                  // number of the runs of the auto denoise
+	DenoiseThreshold       float64                  `json:"denoiseThreshold" yaml:"denoiseThreshold" mapstructure:"denoiseThreshold"`                   // fields differing in more than this fraction of the runs are noise
+	RemoteTestSetURL       string                   `json:"remoteTestSetUrl" yaml:"remoteTestSetUrl" mapstructure:"remoteTestSetUrl"`                   // s3://, gs:// or https:// url, e.g. pre-signed, of a .tar.gz containing the keploy directory to test
+	RemoteReportURL        string                   `json:"remoteReportUrl" yaml:"remoteReportUrl" mapstructure:"remoteReportUrl"`                      // s3://, gs:// or https:// url, e.g. pre-signed for a PUT request, to upload the reports of the test run to
+	AutoAccept             bool                     `json:"autoAccept" yaml:"autoAccept" mapstructure:"autoAccept"`                                     // normalize the failing test cases right after their test set runs (not allowed in CI)
+	RetryOnStatus          []int                    `json:"retryOnStatus" yaml:"retryOnStatus" mapstructure:"retryOnStatus"`                            // transient status codes on which a failed test case is retried, it fails fast on any other status code, empty retries any failure
+	MaxRetries             int                      `json:"maxRetries" yaml:"maxRetries" mapstructure:"maxRetries"`                                     // number of times a failed test case is re-run before it is marked failed, the ones passing on a retry are flagged flaky
+	RetryDelayMs           int                      `json:"retryDelayMs" yaml:"retryDelayMs" mapstructure:"retryDelayMs"`                               // delay in milliseconds between the re-runs of a failed test case
+	FailFast               bool                     `json:"failFast" yaml:"failFast" mapstructure:"failFast"`                                           // stop the test run at the first failing test case
+	OrderByLastFailure     bool                     `json:"orderByLastFailure" yaml:"orderByLastFailure" mapstructure:"orderByLastFailure"`             // run the test cases which failed in the last test run of their test set first
+	RandomizeOrder         bool                     `json:"randomizeOrder" yaml:"randomizeOrder" mapstructure:"randomizeOrder"`                         // shuffle the test cases of the test sets to detect the test cases depending on their order
+	RandomSeed             int64                    `json:"randomSeed" yaml:"randomSeed" mapstructure:"randomSeed"`                                     // seed of the shuffle, a random one is picked and logged when 0
+	RandomRuns             int                      `json:"randomRuns" yaml:"randomRuns" mapstructure:"randomRuns"`                                     // number of the shuffled runs of each test set, the test cases passing only in some of them are order sensitive
+	MaxFailures            int                      `json:"maxFailures" yaml:"maxFailures" mapstructure:"maxFailures"`                                  // abort the remaining test cases of a test set once that many of its test cases failed, 0 for no limit
+	OnSuccess              string                   `json:"onSuccess" yaml:"onSuccess" mapstructure:"onSuccess"`                                        // shell command run at the end of a passing test run
+	OnFailure              string                   `json:"onFailure" yaml:"onFailure" mapstructure:"onFailure"`                                        // shell command run at the end of a failing or aborted test run
+	RunTestSetsSince       time.Time                `json:"runTestSetsSince" yaml:"runTestSetsSince" mapstructure:"runTestSetsSince"`                   // only the test sets with a test case or a mock modified since then are run
+	ShardIndex             int                      `json:"shardIndex" yaml:"shardIndex" mapstructure:"shardIndex"`                                     // index of the shard run by this CI worker, from 0 to ShardTotal-1
+	ShardTotal             int                      `json:"shardTotal" yaml:"shardTotal" mapstructure:"shardTotal"`                                     // number of the CI workers the test sets are split across, each worker runs every ShardTotal-th test set
+	SlowTestTopN           int                      `json:"slowTestTopN" yaml:"slowTestTopN" mapstructure:"slowTestTopN"`                               // number of the slowest test cases listed in the summary of the test run, 0 to not list them
+	SummaryJSONPath        string                   `json:"summaryJsonPath" yaml:"summaryJsonPath" mapstructure:"summaryJsonPath"`                      // path of the machine-readable json summary of the test run
+	JUnitReportPath        string                   `json:"junitReportPath" yaml:"junitReportPath" mapstructure:"junitReportPath"`                      // path of the JUnit XML report aggregating all the test sets of the test run
+	SARIFOutputPath        string                   `json:"sarifOutputPath" yaml:"sarifOutputPath" mapstructure:"sarifOutputPath"`                      // path of the SARIF report pointing the editors to the yaml files of the failed test cases
+	HTMLReport             bool                     `json:"htmlReport" yaml:"htmlReport" mapstructure:"htmlReport"`                                     // generate an html report with the collapsible diffs of the test cases of the test run
+	HTMLReportDir          string                   `json:"htmlReportDir" yaml:"htmlReportDir" mapstructure:"htmlReportDir"`                            // directory of the html report, the html directory of the reports of the test run by default
+	Parallelism            int                      `json:"parallelism" yaml:"parallelism" mapstructure:"parallelism"`                                  // number of test cases of a test set run concurrently against the base path, 0 or 1 runs them serially
+	UseSnapshot            bool                     `json:"useSnapshot" yaml:"useSnapshot" mapstructure:"useSnapshot"`                                  // restore a CRIU snapshot of the warmed up app before each test case, only for the native apps
+	MaxParallel            int                      `json:"maxParallel" yaml:"maxParallel" mapstructure:"maxParallel"`                                  // number of test sets run concurrently against the base path, 0 or 1 runs them serially
+	ReportNoise            bool                     `json:"reportNoise" yaml:"reportNoise" mapstructure:"reportNoise"`                                  // include the effective noise config of each test set in its report
+	Base64JSONFields       []string                 `json:"base64JsonFields" yaml:"base64JsonFields" mapstructure:"base64JsonFields"`                   // body fields holding base64 encoded json, decoded before comparison
+}
+
+// PRCommentConfig is the configuration to post the test run summary as a comment on a pull/merge request.
+type PRCommentConfig struct {
+	Provider  string `json:"provider" yaml:"provider" mapstructure:"provider"` // github or gitlab
+	Token     string `json:"token" yaml:"token" mapstructure:"token"`
+	RepoOwner string `json:"repoOwner" yaml:"repoOwner" mapstructure:"repoOwner"`
+	RepoName  string `json:"repoName" yaml:"repoName" mapstructure:"repoName"`
+	PRNumber  int    `json:"prNumber" yaml:"prNumber" mapstructure:"prNumber"`
+	APIURL    string `json:"apiUrl" yaml:"apiUrl" mapstructure:"apiUrl"` // optional, for self hosted github enterprise or gitlab instances
+}
+
+// EncryptionConfig is the configuration to encrypt the mock files at rest.
+type EncryptionConfig struct {
+	Algorithm string `json:"algorithm" yaml:"algorithm" mapstructure:"algorithm"` // aes-256-gcm
+	KeyFile   string `json:"keyFile" yaml:"keyFile" mapstructure:"keyFile"`       // path of the file holding the 32 bytes key, or env:NAME to read it from the NAME environment variable
+}
+
+// RequestSigningConfig is the configuration to sign the replayed requests with a hmac of the canonical request.
+type RequestSigningConfig struct {
+	Algorithm       string   `json:"algorithm" yaml:"algorithm" mapstructure:"algorithm"` // hmac-sha256 or hmac-sha512
+	SecretKey       string   `json:"secretKey" yaml:"secretKey" mapstructure:"secretKey"`
+	SignedHeaders   []string `json:"signedHeaders" yaml:"signedHeaders" mapstructure:"signedHeaders"`       // headers included in the canonical request
+	SignatureHeader string   `json:"signatureHeader" yaml:"signatureHeader" mapstructure:"signatureHeader"` // header the hex encoded signature is sent in
+}
+
+// ChainRule extracts the value at the JSONPath of the actual response body of a test case (e.g. $.data.id) into a
+// variable, the later test cases of the test set use it as {{.Chain.NAME}} in their url, headers and body.
+type ChainRule struct {
+	TestCase string `json:"testCase" yaml:"testCase" mapstructure:"testCase"` // name of the test case the value is extracted from
+	Path     string `json:"path" yaml:"path" mapstructure:"path"`
+	Var      string `json:"var" yaml:"var" mapstructure:"var"`
 }
 
 type Globalnoise struct {
-	Global   GlobalNoise  `json:"global" yaml:"global" mapstructure:"global"`
-	Testsets TestsetNoise `json:"test-sets" yaml:"test-sets" mapstructure:"test-sets"`
+	Global       GlobalNoise  `json:"global" yaml:"global" mapstructure:"global"`
+	Testsets     TestsetNoise `json:"test-sets" yaml:"test-sets" mapstructure:"test-sets"`
+	GraphQL      GraphQLNoise `json:"graphql" yaml:"graphql" mapstructure:"graphql"`                // JSONPath-style fields of the graphql data (e.g. $.data.user.id) ignored in the graphql mode
+	TypeCoerce   []string     `json:"typeCoerce" yaml:"typeCoerce" mapstructure:"typeCoerce"`       // dot separated paths of the body fields compared as strings, e.g. 42 equals "42"
+	SemverFields []string     `json:"semverFields" yaml:"semverFields" mapstructure:"semverFields"` // dot separated paths of the body fields compared as semantic versions, within the test.semverTolerance
 }
 
 type SelectedTests struct {
@@ -102,6 +230,7 @@ type (
 	Noise        map[string][]string
 	GlobalNoise  map[string]map[string][]string
 	TestsetNoise map[string]map[string]map[string][]string
+	GraphQLNoise map[string][]string
 )
 
 func SetByPassPorts(conf *Config, ports []uint) {
@@ -122,6 +251,56 @@ func GetByPassPorts(conf *Config) []uint {
 	return ports
 }
 
+// NamespacesDir is the reserved directory of the path the namespaces are stored in, so that a namespace is neither
+// listed as a test set nor collides with one.
+const NamespacesDir = "namespaces"
+
+// StoragePath returns the directory of the test sets of the namespace, the reports are in its reports directory.
+func StoragePath(conf *Config) string {
+	if conf.Namespace == "" {
+		return conf.Path
+	}
+	return filepath.Join(conf.Path, NamespacesDir, conf.Namespace)
+}
+
+// ValidateNamespace checks that the namespace is a single directory name, so that it can't escape the path.
+func ValidateNamespace(namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	if namespace == "." || namespace == ".." || strings.ContainsAny(namespace, `/\`) {
+		return fmt.Errorf("invalid namespace %q, it should be a single directory name", namespace)
+	}
+	return nil
+}
+
+// timeLayouts are the layouts of the times of the config, a date is midnight UTC.
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// StringToTimeHook decodes the times of the config from strings, the empty string is the zero time.
+func StringToTimeHook() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(time.Time{}) {
+			return data, nil
+		}
+		return ParseTime(data.(string))
+	}
+}
+
+// ParseTime parses a time of the config, a RFC 3339 time or a date. The empty string is the zero time.
+func ParseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, it should be a RFC 3339 time (e.g. 2024-01-02T15:04:05Z) or a date (e.g. 2024-01-02)", value)
+}
+
 func SetSelectedTests(conf *Config, testSets []string) {
 	if conf.Test.SelectedTests == nil {
 		conf.Test.SelectedTests = make(map[string][]string)
@@ -132,6 +311,36 @@ func SetSelectedTests(conf *Config, testSets []string) {
 	}
 }
 
+// NoiseRegexPrefix marks a noise field as a regular expression matching the paths of the fields, e.g. regex:session_.*
+const NoiseRegexPrefix = "regex:"
+
+// ValidateNoise checks that the regular expressions of the regex noise fields of the global and the test set noise compile.
+func ValidateNoise(noise Globalnoise) error {
+	validate := func(scope string, gn GlobalNoise) error {
+		for part, fields := range gn {
+			for field := range fields {
+				pattern, ok := strings.CutPrefix(field, NoiseRegexPrefix)
+				if !ok {
+					continue
+				}
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("invalid regex noise field %q in the %s %s noise: %w", field, scope, part, err)
+				}
+			}
+		}
+		return nil
+	}
+	if err := validate("global", noise.Global); err != nil {
+		return err
+	}
+	for testSet, gn := range noise.Testsets {
+		if err := validate(testSet, gn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func SetSelectedTestsNormalize(conf *Config, value string) error {
 	testSets := strings.FieldsFunc(value, func(r rune) bool {
 		return r == ',' || r == ' '
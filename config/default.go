@@ -10,6 +10,7 @@ import (
 // defaultConfig is a variable to store the default configuration of the Keploy CLI. It is not a constant because enterprise need update the default configuration.
 var defaultConfig = `
 path: ""
+namespace: ""
 appId: ""
 command: ""
 port: 0
@@ -17,31 +18,101 @@ proxyPort: 16789
 dnsPort: 26789
 debug: false
 disableANSI: false
+disableColor: false
+passingColorScheme: {}
+failingColorScheme: {}
 disableTele: false
+teleBatchSize: 10
 inDocker: false
 generateGithubActions: true
 containerName: ""
 networkName: ""
 buildDelay: 30
+additionalTestPaths: []
 test:
   selectedTests: {}
+  testNameFilter: ""
+  deduplication: ""
+  dryRun: false
+  nameTemplate: ""
   globalNoise:
     global: {}
     test-sets: {}
+    graphql: {}
+    typeCoerce: []
+    semverFields: []
   delay: 5
+  readinessProbe: ""
+  readinessLogPattern: ""
+  readinessTimeout: 60s
+  maxResponseBodyKB: 10240
   apiTimeout: 5
+  testSetTimeout: 0s
+  mockTTL: 0s
+  latencyBudget: 0s
+  latencyBudgets: {}
+  chaining: {}
   coverage: false
+  coverageDriver: ""
   goCoverage: false
+  minCoverage: 0
+  coverageMerge: false
   coverageReportPath: ""
   ignoreOrdering: true
+  ignoreExtraFields: false
+  floatAbsTolerance: 0
+  floatRelTolerance: 0
+  semverTolerance: ""
+  bodyComparator: ""
+  graphQLMode: false
   mongoPassword: "default@123"
   language: ""
   removeUnusedMocks: false
+  mockMatchStrategy: ""
+  autoSortMocks: false
+  timeShiftReplay: false
   basePath: ""
+  referenceBasePath: ""
+  globalHeaders: {}
+  overrideHeaders: false
+  clientCert: ""
+  clientKey: ""
+  clientKeyPassphrase: ""
+  caCert: ""
+  insecureSkipVerify: false
+  autoDenoise: false
+  denoiseRuns: 3
+  denoiseThreshold: 0.5
   mocking: true
+  reportOverwritePolicy: "overwrite"
+  htmlReport: false
+  htmlReportDir: ""
+  maxRetries: 0
+  failFast: false
+  orderByLastFailure: false
+  randomizeOrder: false
+  randomSeed: 0
+  randomRuns: 1
+  maxFailures: 0
+  onSuccess: ""
+  onFailure: ""
+  runTestSetsSince: ""
+  shardIndex: 0
+  shardTotal: 1
+  slowTestTopN: 0
+  useSnapshot: false
+  retryDelayMs: 500
+  retryOnStatus: []
 record:
   recordTimer: 0s
   filters: []
+normalize:
+  strategy: "full"
+report:
+  stabilityRuns: 10
+  flakyThreshold: 0.9
+output:
+  format: "text"
 configPath: ""
 bypassRules: []
 `
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"sync"
 	"syscall"
 	"time"
 
@@ -55,6 +56,11 @@ type App struct {
 	inodeChan        chan uint64
 	EnableTesting    bool
 	Mode             models.Mode
+	// procMu guards the process tree of the native app, which is replaced when a snapshot is restored
+	procMu    sync.Mutex
+	pid       int
+	restoring bool
+	snapshots map[string]string
 }
 
 type Options struct {
@@ -363,7 +369,7 @@ func (a *App) getDockerMeta(ctx context.Context) <-chan error {
 	return errCh
 }
 
-func (a *App) runDocker(ctx context.Context) models.AppError {
+func (a *App) runDocker(ctx context.Context, opts models.RunOptions) models.AppError {
 	// if a.cmd is empty, it means the user wants to run the application manually,
 	// so we don't need to run the application in a goroutine
 	if a.cmd == "" {
@@ -387,7 +393,7 @@ func (a *App) runDocker(ctx context.Context) models.AppError {
 	g.Go(func() error {
 		defer utils.Recover(a.logger)
 		defer close(errCh)
-		err := a.run(ctx)
+		err := a.run(ctx, opts)
 		if err.Err != nil {
 			utils.LogError(a.logger, err.Err, "Application stopped with the error")
 			errCh <- err.Err
@@ -411,13 +417,13 @@ func (a *App) runDocker(ctx context.Context) models.AppError {
 	}
 }
 
-func (a *App) Run(ctx context.Context, inodeChan chan uint64) models.AppError {
+func (a *App) Run(ctx context.Context, inodeChan chan uint64, opts models.RunOptions) models.AppError {
 	a.inodeChan = inodeChan
 
 	if utils.IsDockerKind(a.kind) {
-		return a.runDocker(ctx)
+		return a.runDocker(ctx, opts)
 	}
-	return a.run(ctx)
+	return a.run(ctx, opts)
 }
 func (a *App) waitTillExit() {
 	timeout := time.NewTimer(30 * time.Second)
@@ -448,7 +454,7 @@ func (a *App) waitTillExit() {
 	}
 }
 
-func (a *App) run(ctx context.Context) models.AppError {
+func (a *App) run(ctx context.Context, opts models.RunOptions) models.AppError {
 
 	userCmd := a.cmd
 
@@ -469,7 +475,12 @@ func (a *App) run(ctx context.Context) models.AppError {
 	}
 
 	var err error
-	cmdErr := utils.ExecuteCommand(ctx, a.logger, userCmd, cmdCancel, 25*time.Second)
+	defer a.removeSnapshots()
+	cmdErr := utils.ExecuteCommand(ctx, a.logger, userCmd, cmdCancel, 25*time.Second, a.setPid, opts.Stdout, opts.Stderr)
+	// the original process tree exits when a snapshot is restored, the app keeps running from the snapshot
+	if a.waitRestored(ctx) {
+		cmdErr = utils.CmdError{}
+	}
 	if cmdErr.Err != nil {
 		switch cmdErr.Type {
 		case utils.Init:
@@ -0,0 +1,150 @@
+//go:build linux
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// The snapshots are checkpoints of the process tree of the native app taken with CRIU (https://criu.org).
+// CRIU restores the processes with their original pids, so restoring a snapshot kills the running tree first.
+// The restored tree is detached from keploy, the app is still considered running until it exits.
+
+// setPid remembers the pid of the root process of the app tree, the shell running the app command.
+func (a *App) setPid(pid int) {
+	a.procMu.Lock()
+	defer a.procMu.Unlock()
+	a.pid = pid
+}
+
+// TakeSnapshot checkpoints the running process tree of the app, leaving it running, and returns the id of the snapshot.
+func (a *App) TakeSnapshot(ctx context.Context) (string, error) {
+	if a.kind != utils.Native {
+		return "", fmt.Errorf("snapshots are only supported for the native apps, not for %s", a.kind)
+	}
+	a.procMu.Lock()
+	pid := a.pid
+	a.procMu.Unlock()
+	if pid == 0 {
+		return "", errors.New("the app is not running")
+	}
+
+	dir, err := os.MkdirTemp("", "keploy-snapshot-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the snapshot directory: %w", err)
+	}
+	out, err := exec.CommandContext(ctx, "criu", "dump", "-t", strconv.Itoa(pid), "-D", dir, "--shell-job", "--leave-running", "--tcp-established", "--ext-unix-sk", "--file-locks").CombinedOutput()
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to dump the app process tree with criu: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	snapshotID := filepath.Base(dir)
+	a.procMu.Lock()
+	if a.snapshots == nil {
+		a.snapshots = map[string]string{}
+	}
+	a.snapshots[snapshotID] = dir
+	a.procMu.Unlock()
+	a.logger.Debug("snapshot of the app is taken", zap.String("snapshot", snapshotID), zap.Int("pid", pid))
+	return snapshotID, nil
+}
+
+// RestoreSnapshot replaces the running process tree of the app with the snapshot.
+func (a *App) RestoreSnapshot(ctx context.Context, snapshotID string) error {
+	a.procMu.Lock()
+	dir, ok := a.snapshots[snapshotID]
+	pid := a.pid
+	if ok {
+		a.restoring = true
+	}
+	a.procMu.Unlock()
+	if !ok {
+		return fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+	defer func() {
+		a.procMu.Lock()
+		a.restoring = false
+		a.procMu.Unlock()
+	}()
+
+	// the pids of the tree must be free before criu can restore it
+	err := syscall.Kill(-pid, syscall.SIGKILL)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to stop the app process tree: %w", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("app process %d did not exit before the restore", pid)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	out, err := exec.CommandContext(ctx, "criu", "restore", "-D", dir, "--shell-job", "--restore-detached", "--tcp-established", "--ext-unix-sk", "--file-locks").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to restore the app process tree with criu: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	a.logger.Debug("snapshot of the app is restored", zap.String("snapshot", snapshotID), zap.Int("pid", pid))
+	return nil
+}
+
+// waitRestored blocks while the app runs from a restored snapshot, as the restored tree is not a child of keploy.
+// It reports whether the app was restored, so that the exit of the original tree is not taken as the app stopping.
+func (a *App) waitRestored(ctx context.Context) bool {
+	restored := false
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		a.procMu.Lock()
+		restoring, pid := a.restoring, a.pid
+		a.procMu.Unlock()
+		if !restoring && (pid == 0 || !processAlive(pid)) {
+			return restored
+		}
+		restored = true
+		select {
+		case <-ctx.Done():
+			if processAlive(pid) {
+				err := utils.InterruptProcessTree(a.logger, pid, syscall.SIGINT)
+				if err != nil {
+					utils.LogError(a.logger, err, "failed to stop the restored app")
+				}
+			}
+			return restored
+		case <-ticker.C:
+		}
+	}
+}
+
+// removeSnapshots deletes the snapshots of the app once it has stopped.
+func (a *App) removeSnapshots() {
+	a.procMu.Lock()
+	defer a.procMu.Unlock()
+	for snapshotID, dir := range a.snapshots {
+		if err := os.RemoveAll(dir); err != nil {
+			a.logger.Debug("failed to remove the snapshot", zap.String("snapshot", snapshotID), zap.Error(err))
+		}
+	}
+	a.snapshots = nil
+}
+
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
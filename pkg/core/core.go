@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -72,13 +73,14 @@ func (c *Core) getApp(id uint64) (*app.App, error) {
 	return h, nil
 }
 
-func (c *Core) Hook(ctx context.Context, id uint64, opts models.HookOptions) error {
+func (c *Core) Hook(ctx context.Context, id uint64, opts models.HookOptions) (*models.HookMetadata, error) {
 	hookErr := errors.New("failed to hook into the app")
+	startTime := time.Now()
 
 	a, err := c.getApp(id)
 	if err != nil {
 		utils.LogError(c.logger, err, "failed to get app")
-		return hookErr
+		return nil, hookErr
 	}
 
 	isDocker := false
@@ -90,13 +92,13 @@ func (c *Core) Hook(ctx context.Context, id uint64, opts models.HookOptions) err
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 
 	g, ok := ctx.Value(models.ErrGroupKey).(*errgroup.Group)
 	if !ok {
-		return errors.New("failed to get the error group from the context")
+		return nil, errors.New("failed to get the error group from the context")
 	}
 
 	// create a new error group for the hooks
@@ -139,17 +141,17 @@ func (c *Core) Hook(ctx context.Context, id uint64, opts models.HookOptions) err
 	})
 	if err != nil {
 		utils.LogError(c.logger, err, "failed to load hooks")
-		return hookErr
+		return nil, hookErr
 	}
 
 	if c.proxyStarted {
 		c.logger.Debug("Proxy already started")
-		return nil
+		return c.hookMetadata(startTime), nil
 	}
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 
@@ -163,7 +165,7 @@ func (c *Core) Hook(ctx context.Context, id uint64, opts models.HookOptions) err
 	})
 	if err != nil {
 		utils.LogError(c.logger, err, "failed to start proxy")
-		return hookErr
+		return nil, hookErr
 	}
 
 	c.proxyStarted = true
@@ -179,14 +181,23 @@ func (c *Core) Hook(ctx context.Context, id uint64, opts models.HookOptions) err
 		err := c.Tester.Setup(ctx, models.TestingOptions{Mode: opts.Mode})
 		if err != nil {
 			utils.LogError(c.logger, err, "error while setting up the test bench environment")
-			return errors.New("failed to setup the test bench")
+			return nil, errors.New("failed to setup the test bench")
 		}
 	}
 
-	return nil
+	return c.hookMetadata(startTime), nil
 }
 
-func (c *Core) Run(ctx context.Context, id uint64, _ models.RunOptions) models.AppError {
+// hookMetadata collects the details of the loaded hooks and the proxy for diagnostics.
+func (c *Core) hookMetadata(startTime time.Time) *models.HookMetadata {
+	return &models.HookMetadata{
+		LoadedHooks:   c.Hooks.LoadedHooks(),
+		ProxyPort:     int(c.Hooks.ProxyPort()),
+		StartDuration: time.Since(startTime),
+	}
+}
+
+func (c *Core) Run(ctx context.Context, id uint64, opts models.RunOptions) models.AppError {
 	a, err := c.getApp(id)
 	if err != nil {
 		utils.LogError(c.logger, err, "failed to get app")
@@ -230,7 +241,7 @@ func (c *Core) Run(ctx context.Context, id uint64, _ models.RunOptions) models.A
 	runAppErrGrp.Go(func() error {
 		defer utils.Recover(c.logger)
 		defer close(appErrCh)
-		appErr := a.Run(runAppCtx, inodeChan)
+		appErr := a.Run(runAppCtx, inodeChan, opts)
 		if appErr.Err != nil {
 			utils.LogError(c.logger, appErr.Err, "error while running the app")
 			appErrCh <- appErr
@@ -263,3 +274,23 @@ func (c *Core) GetContainerIP(_ context.Context, id uint64) (string, error) {
 
 	return ip, nil
 }
+
+// TakeSnapshot checkpoints the process tree of the native app with CRIU and returns the id of the snapshot.
+func (c *Core) TakeSnapshot(ctx context.Context, id uint64) (string, error) {
+	a, err := c.getApp(id)
+	if err != nil {
+		utils.LogError(c.logger, err, "failed to get app")
+		return "", err
+	}
+	return a.TakeSnapshot(ctx)
+}
+
+// RestoreSnapshot replaces the process tree of the native app with the snapshot taken by TakeSnapshot.
+func (c *Core) RestoreSnapshot(ctx context.Context, id uint64, snapshotID string) error {
+	a, err := c.getApp(id)
+	if err != nil {
+		utils.LogError(c.logger, err, "failed to get app")
+		return err
+	}
+	return a.RestoreSnapshot(ctx, snapshotID)
+}
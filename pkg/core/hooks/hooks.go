@@ -148,6 +148,54 @@ func (h *Hooks) Load(ctx context.Context, id uint64, opts core.HookCfg) error {
 	return nil
 }
 
+func (h *Hooks) ProxyPort() uint32 {
+	return h.proxyPort
+}
+
+func (h *Hooks) LoadedHooks() []string {
+	probes := []struct {
+		name string
+		l    link.Link
+	}{
+		{"sys_socket", h.socket},
+		{"cgroup/connect4", h.connect4},
+		{"sys_bind", h.bind},
+		{"cgroup/getpeername4", h.gp4},
+		{"udp_pre_connect", h.udpp4},
+		{"tcp_v4_pre_connect", h.tcppv4},
+		{"tcp_v4_connect", h.tcpv4},
+		{"tcp_v4_connect_ret", h.tcpv4Ret},
+		{"cgroup/connect6", h.connect6},
+		{"cgroup/getpeername6", h.gp6},
+		{"tcp_v6_pre_connect", h.tcppv6},
+		{"tcp_v6_connect", h.tcpv6},
+		{"tcp_v6_connect_ret", h.tcpv6Ret},
+		{"sys_accept", h.accept},
+		{"sys_accept_ret", h.acceptRet},
+		{"sys_accept4", h.accept4},
+		{"sys_accept4_ret", h.accept4Ret},
+		{"sys_read", h.read},
+		{"sys_read_ret", h.readRet},
+		{"sys_write", h.write},
+		{"sys_write_ret", h.writeRet},
+		{"sys_writev", h.writev},
+		{"sys_writev_ret", h.writevRet},
+		{"sys_close", h.close},
+		{"sys_close_ret", h.closeRet},
+		{"sys_sendto", h.sendto},
+		{"sys_sendto_ret", h.sendtoRet},
+		{"sys_recvfrom", h.recvfrom},
+		{"sys_recvfrom_ret", h.recvfromRet},
+	}
+	var loaded []string
+	for _, p := range probes {
+		if p.l != nil {
+			loaded = append(loaded, p.name)
+		}
+	}
+	return loaded
+}
+
 func (h *Hooks) load(_ context.Context, opts core.HookCfg) error {
 	// Allow the current process to lock memory for eBPF resources.
 	if err := rlimit.RemoveMemlock(); err != nil {
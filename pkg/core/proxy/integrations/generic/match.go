@@ -47,11 +47,16 @@ func fuzzyMatch(ctx context.Context, reqBuff [][]byte, mockDb integrations.MockM
 
 			index := findExactMatch(filteredMocks, reqBuff)
 
+			fuzzy := false
 			if index == -1 {
 				index = findBinaryMatch(filteredMocks, reqBuff, 0.9)
+				fuzzy = index != -1
 			}
 
 			if index != -1 {
+				if fuzzy {
+					_ = mockDb.FlagMockAsFuzzyMatched(*filteredMocks[index])
+				}
 				responseMock := make([]models.Payload, len(filteredMocks[index].Spec.GenericResponses))
 				copy(responseMock, filteredMocks[index].Spec.GenericResponses)
 				originalFilteredMock := *filteredMocks[index]
@@ -76,6 +81,7 @@ func fuzzyMatch(ctx context.Context, reqBuff [][]byte, mockDb integrations.MockM
 			index = findBinaryMatch(totalMocks, reqBuff, 0.4)
 
 			if index != -1 {
+				_ = mockDb.FlagMockAsFuzzyMatched(*totalMocks[index])
 				responseMock := make([]models.Payload, len(totalMocks[index].Spec.GenericResponses))
 				copy(responseMock, totalMocks[index].Spec.GenericResponses)
 				originalFilteredMock := *totalMocks[index]
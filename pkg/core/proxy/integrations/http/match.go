@@ -157,6 +157,7 @@ func match(ctx context.Context, logger *zap.Logger, input *req, mockDb integrati
 				if !updateMock(ctx, logger, bodyMatched[0], mockDb) {
 					continue
 				}
+				flagFuzzyMatch(logger, bodyMatched[0], mockDb)
 				return true, bodyMatched[0], nil
 			}
 
@@ -172,6 +173,7 @@ func match(ctx context.Context, logger *zap.Logger, input *req, mockDb integrati
 			if !updateMock(ctx, logger, bestMatch, mockDb) {
 				continue
 			}
+			flagFuzzyMatch(logger, bestMatch, mockDb)
 			return true, bestMatch, nil
 		}
 		return false, nil, nil
@@ -395,3 +397,10 @@ func updateMock(_ context.Context, logger *zap.Logger, matchedMock *models.Mock,
 
 	return true
 }
+
+// flagFuzzyMatch flags the mock matched by its body schema or fuzzily, the body of its request differs from the actual one.
+func flagFuzzyMatch(logger *zap.Logger, matchedMock *models.Mock, mockDb integrations.MockMemDb) {
+	if err := mockDb.FlagMockAsFuzzyMatched(*matchedMock); err != nil {
+		logger.Error("failed to flag mock as fuzzy matched", zap.Error(err))
+	}
+}
@@ -54,4 +54,6 @@ type MockMemDb interface {
 	DeleteUnFilteredMock(mock models.Mock) bool
 	// Flag the mock as used which matches the external request from application in test mode
 	FlagMockAsUsed(mock models.Mock) error
+	// Flag the mock as matched fuzzily, not exactly, to report the fidelity of the mocks
+	FlagMockAsFuzzyMatched(mock models.Mock) error
 }
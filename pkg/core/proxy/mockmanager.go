@@ -4,6 +4,7 @@ package proxy
 
 import (
 	"fmt"
+	"math/rand"
 	"sort"
 	"strconv"
 	"strings"
@@ -18,6 +19,13 @@ type MockManager struct {
 	unfiltered    *TreeDb
 	logger        *zap.Logger
 	consumedMocks sync.Map
+	// fuzzyMocks are the consumed mocks matched fuzzily rather than exactly
+	fuzzyMocks sync.Map
+	// matchStrategy orders the mocks handed to the parsers, which use the first of the mocks matching the call
+	matchStrategy models.MockMatchStrategy
+	// mu guards uses, the number of times each mock was used for the round robin
+	mu   sync.Mutex
+	uses map[string]int
 }
 
 func NewMockManager(filtered, unfiltered *TreeDb, logger *zap.Logger) *MockManager {
@@ -26,9 +34,45 @@ func NewMockManager(filtered, unfiltered *TreeDb, logger *zap.Logger) *MockManag
 		unfiltered:    unfiltered,
 		logger:        logger,
 		consumedMocks: sync.Map{},
+		fuzzyMocks:    sync.Map{},
+		uses:          map[string]int{},
 	}
 }
 
+// SetMatchStrategy sets the order in which the mocks matching the same outgoing call are used.
+func (m *MockManager) SetMatchStrategy(strategy models.MockMatchStrategy) {
+	m.matchStrategy = strategy
+}
+
+// order orders the mocks with the match strategy. The round robin puts the least used mocks first, so that the
+// parsers cycle through the mocks matching the same call, and random shuffles them. The first match keeps the
+// order of the mocks as set.
+func (m *MockManager) order(mocks []*models.Mock) []*models.Mock {
+	switch m.matchStrategy {
+	case models.RoundRobin:
+		m.mu.Lock()
+		sort.SliceStable(mocks, func(i, j int) bool {
+			return m.uses[mocks[i].Name] < m.uses[mocks[j].Name]
+		})
+		m.mu.Unlock()
+	case models.Random:
+		rand.Shuffle(len(mocks), func(i, j int) {
+			mocks[i], mocks[j] = mocks[j], mocks[i]
+		})
+	}
+	return mocks
+}
+
+// countUse counts the use of the mock for the round robin, it is counted before the next call is matched.
+func (m *MockManager) countUse(name string) {
+	if m.matchStrategy != models.RoundRobin {
+		return
+	}
+	m.mu.Lock()
+	m.uses[name]++
+	m.mu.Unlock()
+}
+
 func (m *MockManager) SetFilteredMocks(mocks []*models.Mock) {
 	m.filtered.deleteAll()
 	for index, mock := range mocks {
@@ -58,7 +102,7 @@ func (m *MockManager) GetFilteredMocks() ([]*models.Mock, error) {
 	for _, m := range mockCopy {
 		tcsMocks = append(tcsMocks, &m)
 	}
-	return tcsMocks, nil
+	return m.order(tcsMocks), nil
 }
 
 func (m *MockManager) GetUnFilteredMocks() ([]*models.Mock, error) {
@@ -72,15 +116,16 @@ func (m *MockManager) GetUnFilteredMocks() ([]*models.Mock, error) {
 	for _, m := range mockCopy {
 		configMocks = append(configMocks, &m)
 	}
-	return configMocks, nil
+	return m.order(configMocks), nil
 }
 
 func (m *MockManager) UpdateUnFilteredMock(old *models.Mock, new *models.Mock) bool {
 	updated := m.unfiltered.update(old.TestModeInfo, new.TestModeInfo, new)
 	if updated {
+		m.countUse(old.Name)
 		// mark the unfiltered mock as used for the current simulated test-case
 		go func() {
-			if err := m.FlagMockAsUsed(*old); err != nil {
+			if err := m.flagMockAsUsed(*old); err != nil {
 				m.logger.Error("failed to flag mock as used", zap.Error(err))
 			}
 		}()
@@ -89,6 +134,16 @@ func (m *MockManager) UpdateUnFilteredMock(old *models.Mock, new *models.Mock) b
 }
 
 func (m *MockManager) FlagMockAsUsed(mock models.Mock) error {
+	err := m.flagMockAsUsed(mock)
+	if err != nil {
+		return err
+	}
+	m.countUse(mock.Name)
+	return nil
+}
+
+// flagMockAsUsed flags the mock as consumed by the test case, without counting the use which the caller counted.
+func (m *MockManager) flagMockAsUsed(mock models.Mock) error {
 	if mock.Name == "" {
 		return fmt.Errorf("mock is empty")
 	}
@@ -96,11 +151,22 @@ func (m *MockManager) FlagMockAsUsed(mock models.Mock) error {
 	return nil
 }
 
+// FlagMockAsFuzzyMatched flags the mock as matched fuzzily, e.g. by its body schema or the similarity of its
+// request, the mock is flagged as used on its own.
+func (m *MockManager) FlagMockAsFuzzyMatched(mock models.Mock) error {
+	if mock.Name == "" {
+		return fmt.Errorf("mock is empty")
+	}
+	m.fuzzyMocks.Store(mock.Name, true)
+	return nil
+}
+
 func (m *MockManager) DeleteFilteredMock(mock models.Mock) bool {
 	isDeleted := m.filtered.delete(mock.TestModeInfo)
 	if isDeleted {
+		m.countUse(mock.Name)
 		go func() {
-			if err := m.FlagMockAsUsed(mock); err != nil {
+			if err := m.flagMockAsUsed(mock); err != nil {
 				m.logger.Error("failed to flag mock as used", zap.Error(err))
 			}
 		}()
@@ -111,8 +177,9 @@ func (m *MockManager) DeleteFilteredMock(mock models.Mock) bool {
 func (m *MockManager) DeleteUnFilteredMock(mock models.Mock) bool {
 	isDeleted := m.unfiltered.delete(mock.TestModeInfo)
 	if isDeleted {
+		m.countUse(mock.Name)
 		go func() {
-			if err := m.FlagMockAsUsed(mock); err != nil {
+			if err := m.flagMockAsUsed(mock); err != nil {
 				m.logger.Error("failed to flag mock as used", zap.Error(err))
 			}
 		}()
@@ -139,3 +206,17 @@ func (m *MockManager) GetConsumedMocks() []string {
 	}
 	return keys
 }
+
+// GetFuzzyMatchedMocks returns the names of the mocks matched fuzzily since the last call.
+func (m *MockManager) GetFuzzyMatchedMocks() []string {
+	var keys []string
+	m.fuzzyMocks.Range(func(key, _ interface{}) bool {
+		if name, ok := key.(string); ok {
+			keys = append(keys, name)
+			m.fuzzyMocks.Delete(key)
+		}
+		return true
+	})
+	sort.Strings(keys)
+	return keys
+}
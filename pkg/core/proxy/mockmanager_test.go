@@ -0,0 +1,80 @@
+//go:build linux
+
+package proxy
+
+import (
+	"reflect"
+	"testing"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestMockManagerMatchStrategy(t *testing.T) {
+	// the calls match the mocks of their kind, like the parsers matching the outgoing calls
+	mocks := []*models.Mock{
+		{Name: "mock-0", Kind: models.HTTP},
+		{Name: "mock-1", Kind: models.REDIS},
+		{Name: "mock-2", Kind: models.HTTP},
+		{Name: "mock-3", Kind: models.HTTP},
+	}
+
+	tests := []struct {
+		name     string
+		strategy models.MockMatchStrategy
+		calls    []models.Kind
+		want     []string
+	}{
+		{
+			name:     "first match uses the first matching mock every time",
+			strategy: models.FirstMatch,
+			calls:    []models.Kind{models.HTTP, models.HTTP, models.HTTP},
+			want:     []string{"mock-0", "mock-0", "mock-0"},
+		},
+		{
+			name:     "round robin cycles through the matching mocks",
+			strategy: models.RoundRobin,
+			calls:    []models.Kind{models.HTTP, models.HTTP, models.HTTP, models.HTTP, models.HTTP},
+			want:     []string{"mock-0", "mock-2", "mock-3", "mock-0", "mock-2"},
+		},
+		{
+			name:     "round robin is not rotated by the calls matching other mocks",
+			strategy: models.RoundRobin,
+			calls:    []models.Kind{models.HTTP, models.REDIS, models.REDIS, models.HTTP, models.REDIS},
+			want:     []string{"mock-0", "mock-1", "mock-1", "mock-2", "mock-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockManager(NewTreeDb(customComparator), NewTreeDb(customComparator), zap.NewNop())
+			m.SetMatchStrategy(tt.strategy)
+			m.SetUnFilteredMocks(mocks)
+
+			var got []string
+			for _, kind := range tt.calls {
+				candidates, err := m.GetUnFilteredMocks()
+				if err != nil {
+					t.Fatalf("failed to get the mocks: %v", err)
+				}
+				var matched *models.Mock
+				for _, mock := range candidates {
+					if mock.Kind == kind {
+						matched = mock
+						break
+					}
+				}
+				if matched == nil {
+					t.Fatalf("no mock matched the %s call", kind)
+				}
+				if err := m.FlagMockAsUsed(*matched); err != nil {
+					t.Fatalf("failed to flag the mock as used: %v", err)
+				}
+				got = append(got, matched.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got the mocks %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
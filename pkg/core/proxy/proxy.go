@@ -579,7 +579,9 @@ func (p *Proxy) Mock(_ context.Context, id uint64, opts models.OutgoingOptions)
 		Mode:            models.MODE_TEST,
 		OutgoingOptions: opts,
 	})
-	p.MockManagers.Store(id, NewMockManager(NewTreeDb(customComparator), NewTreeDb(customComparator), p.logger))
+	mockManager := NewMockManager(NewTreeDb(customComparator), NewTreeDb(customComparator), p.logger)
+	mockManager.SetMatchStrategy(opts.MockMatchStrategy)
+	p.MockManagers.Store(id, mockManager)
 
 	if !opts.Mocking {
 		p.logger.Info("🔀 Mocking is disabled, the response will be fetched from the actual service")
@@ -625,3 +627,12 @@ func (p *Proxy) GetConsumedMocks(_ context.Context, id uint64) ([]string, error)
 	}
 	return m.(*MockManager).GetConsumedMocks(), nil
 }
+
+// GetFuzzyMatchedMocks returns the mocks matched fuzzily for a given app id
+func (p *Proxy) GetFuzzyMatchedMocks(_ context.Context, id uint64) ([]string, error) {
+	m, ok := p.MockManagers.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("mock manager not found to get fuzzy matched mocks")
+	}
+	return m.(*MockManager).GetFuzzyMatchedMocks(), nil
+}
@@ -18,6 +18,10 @@ type Hooks interface {
 	OutgoingInfo
 	TestBenchInfo
 	Load(ctx context.Context, id uint64, cfg HookCfg) error
+	// LoadedHooks returns the names of the eBPF probes that are currently attached.
+	LoadedHooks() []string
+	// ProxyPort returns the port on which the hooks redirect the traffic to the proxy.
+	ProxyPort() uint32
 	Record(ctx context.Context, id uint64, opts models.IncomingOptions) (<-chan *models.TestCase, error)
 }
 
@@ -43,6 +47,7 @@ type Proxy interface {
 	Mock(ctx context.Context, id uint64, opts models.OutgoingOptions) error
 	SetMocks(ctx context.Context, id uint64, filtered []*models.Mock, unFiltered []*models.Mock) error
 	GetConsumedMocks(ctx context.Context, id uint64) ([]string, error)
+	GetFuzzyMatchedMocks(ctx context.Context, id uint64) ([]string, error)
 }
 
 type ProxyOptions struct {
@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/protocolbuffers/protoscope"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+// CreateLengthPrefixedMessageFromPayload decodes the length prefixed grpc message into its human readable protoscope form.
+func CreateLengthPrefixedMessageFromPayload(data []byte) models.GrpcLengthPrefixedMessage {
+	msg := models.GrpcLengthPrefixedMessage{}
+
+	// If the body is not length prefixed, we return the default value.
+	if len(data) < 5 {
+		return msg
+	}
+
+	// The first byte is the compression flag.
+	msg.CompressionFlag = uint(data[0])
+
+	// The next 4 bytes are message length.
+	msg.MessageLength = binary.BigEndian.Uint32(data[1:5])
+
+	// The payload could be empty. We only parse it if it is present.
+	if len(data) >= 5 {
+		// Use protoscope to decode the message.
+		msg.DecodedData = protoscope.Write(data[5:], protoscope.WriterOptions{})
+	}
+
+	return msg
+}
+
+// CreatePayloadFromLengthPrefixedMessage encodes the protoscope form of the grpc message back into the length prefixed wire format.
+func CreatePayloadFromLengthPrefixedMessage(msg models.GrpcLengthPrefixedMessage) ([]byte, error) {
+	scanner := protoscope.NewScanner(msg.DecodedData)
+	encodedData, err := scanner.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("could not encode grpc msg using protoscope: %v", err)
+	}
+
+	// Note that the encoded length is present in the msg, but it is also equal to the len of encodedData.
+	// We should give the preference to the length of encodedData, since the mocks might have been altered.
+
+	// Reserve 1 byte for compression flag, 4 bytes for length capture.
+	payload := make([]byte, 1+4)
+	payload[0] = uint8(msg.CompressionFlag)
+	binary.BigEndian.PutUint32(payload[1:5], uint32(len(encodedData)))
+	payload = append(payload, encodedData...)
+
+	return payload, nil
+}
+
+// SimulateGRPC replays the recorded unary grpc request of the test case over http2 and returns the grpc response.
+func SimulateGRPC(ctx context.Context, tc models.TestCase, testSet string, logger *zap.Logger, apiTimeout uint64) (*models.GrpcResp, error) {
+	logger.Info("starting test for", zap.Any("test case", models.HighlightString(tc.Name)), zap.Any("test set", models.HighlightString(testSet)))
+
+	payload, err := CreatePayloadFromLengthPrefixedMessage(tc.GrpcReq.Body)
+	if err != nil {
+		utils.LogError(logger, err, "failed to create the grpc payload from the yaml document")
+		return nil, err
+	}
+
+	pseudoHeaders := tc.GrpcReq.Headers.PseudoHeaders
+	scheme := pseudoHeaders[":scheme"]
+	if scheme == "" {
+		scheme = "http"
+	}
+	reqURL := fmt.Sprintf("%s://%s%s", scheme, pseudoHeaders[":authority"], pseudoHeaders[":path"])
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(payload))
+	if err != nil {
+		utils.LogError(logger, err, "failed to create a grpc request from the yaml document")
+		return nil, err
+	}
+	for key, value := range tc.GrpcReq.Headers.OrdinaryHeaders {
+		// the length of the payload may differ from the recorded one
+		if strings.EqualFold(key, "content-length") {
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("KEPLOY-TEST-ID", tc.Name)
+	logger.Debug(fmt.Sprintf("Sending grpc request to user app:%v", req))
+
+	transport := &http2.Transport{}
+	if scheme == "http" {
+		// grpc over plain text uses http2 with prior knowledge (h2c)
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+	client := &http.Client{
+		Timeout:   time.Second * time.Duration(apiTimeout),
+		Transport: transport,
+	}
+
+	httpResp, err := client.Do(req)
+	if err != nil {
+		utils.LogError(logger, err, "failed to send testcase grpc request to app")
+		return nil, err
+	}
+	defer func() {
+		if err := httpResp.Body.Close(); err != nil {
+			utils.LogError(logger, err, "failed to close the grpc response body")
+		}
+	}()
+
+	// the trailers are only available once the body is read completely
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		utils.LogError(logger, err, "failed reading grpc response body")
+		return nil, err
+	}
+
+	resp := &models.GrpcResp{
+		Headers: models.GrpcHeaders{
+			PseudoHeaders:   map[string]string{":status": strconv.Itoa(httpResp.StatusCode)},
+			OrdinaryHeaders: grpcHeaders(httpResp.Header),
+		},
+		Body: CreateLengthPrefixedMessageFromPayload(respBody),
+		Trailers: models.GrpcHeaders{
+			PseudoHeaders:   map[string]string{},
+			OrdinaryHeaders: grpcHeaders(httpResp.Trailer),
+		},
+	}
+	return resp, nil
+}
+
+// grpcHeaders converts the http2 header into the lower cased grpc header map as recorded.
+func grpcHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		headers[strings.ToLower(key)] = strings.Join(values, ",")
+	}
+	return headers
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/k0kubun/pp/v3"
+)
+
+var colorDisabled bool
+
+// DisableColor makes the summaries and the results of the test cases plain text, for the log files and the CI
+// terminals rendering the ANSI colors as garbage.
+func DisableColor() {
+	colorDisabled = true
+	color.NoColor = true
+	// the printers created for the results of the test cases are disabled as well by the package switch
+	pp.ColoringEnabled = false
+}
+
+// IsColorDisabled reports whether the colors are disabled by the disableColor config or the NO_COLOR environment.
+func IsColorDisabled() bool {
+	return colorDisabled
+}
+
+var ppColors = map[string]uint16{
+	"nocolor": pp.NoColor,
+	"black":   pp.Black,
+	"red":     pp.Red,
+	"green":   pp.Green,
+	"yellow":  pp.Yellow,
+	"blue":    pp.Blue,
+	"magenta": pp.Magenta,
+	"cyan":    pp.Cyan,
+	"white":   pp.White,
+	"bold":    pp.Bold,
+}
+
+// OverrideColorScheme sets the colors of the fields of the color scheme, e.g. {"string": "red|bold"}. The fields are
+// the ones of pp.ColorScheme in camel case and the colors are the foreground colors, bold or nocolor joined by |.
+func OverrideColorScheme(scheme *pp.ColorScheme, overrides map[string]string) error {
+	fields := map[string]*uint16{
+		"bool":            &scheme.Bool,
+		"integer":         &scheme.Integer,
+		"float":           &scheme.Float,
+		"string":          &scheme.String,
+		"stringquotation": &scheme.StringQuotation,
+		"escapedchar":     &scheme.EscapedChar,
+		"fieldname":       &scheme.FieldName,
+		"pointeraddress":  &scheme.PointerAdress,
+		"nil":             &scheme.Nil,
+		"time":            &scheme.Time,
+		"structname":      &scheme.StructName,
+		"objectlength":    &scheme.ObjectLength,
+	}
+	// the fields are set in order, so that the same invalid config always reports the same error
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unknown field %q of the color scheme", name)
+		}
+		var value uint16
+		for _, part := range strings.Split(overrides[name], "|") {
+			c, ok := ppColors[strings.ToLower(strings.TrimSpace(part))]
+			if !ok {
+				return fmt.Errorf("unknown color %q of the %s of the color scheme", part, name)
+			}
+			value |= c
+		}
+		*field = value
+	}
+	return nil
+}
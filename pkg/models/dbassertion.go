@@ -0,0 +1,28 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DBAssertionDrivers are the database/sql drivers the database assertions can use.
+var DBAssertionDrivers = []string{"mysql", "postgres"}
+
+// Validate checks the driver of the database assertion is supported and its dsn and query are set.
+func (a DBAssertion) Validate() error {
+	supported := false
+	for _, driver := range DBAssertionDrivers {
+		if a.Driver == driver {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("unsupported driver %q of the database assertion, the drivers are %s", a.Driver, strings.Join(DBAssertionDrivers, ", "))
+	}
+	if a.DSN == "" || a.Query == "" {
+		return errors.New("the dsn and the query of the database assertion should be set")
+	}
+	return nil
+}
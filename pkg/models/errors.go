@@ -1,6 +1,12 @@
 package models
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTestCaseNotFound is returned when the test case is not in the test set
+var ErrTestCaseNotFound = errors.New("test case not found")
 
 type AppError struct {
 	AppErrorType AppErrorType
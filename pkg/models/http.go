@@ -16,6 +16,7 @@ type HTTPReq struct {
 	Body       string            `json:"body" yaml:"body"`
 	Binary     string            `json:"binary" yaml:"binary,omitempty"`
 	Form       []FormData        `json:"form" yaml:"form,omitempty"`
+	FormFiles  []FormFile        `json:"form_files" yaml:"form_files,omitempty"`
 	Timestamp  time.Time         `json:"timestamp" yaml:"timestamp"`
 }
 
@@ -28,6 +29,11 @@ type HTTPSchema struct {
 	Created          int64                  `json:"created" yaml:"created,omitempty"`
 	ReqTimestampMock time.Time              `json:"reqTimestampMock" yaml:"reqTimestampMock,omitempty"`
 	ResTimestampMock time.Time              `json:"resTimestampMock" yaml:"resTimestampMock,omitempty"`
+	DBAssertions     []DBAssertion          `json:"dbAssertions" yaml:"dbAssertions,omitempty"`
+	PreScript        string                 `json:"preScript" yaml:"preScript,omitempty"`
+	PostScript       string                 `json:"postScript" yaml:"postScript,omitempty"`
+	DependsOn        []string               `json:"dependsOn" yaml:"dependsOn,omitempty"`
+	APITimeout       uint64                 `json:"apiTimeout" yaml:"apiTimeout,omitempty"`
 }
 
 type FormData struct {
@@ -36,6 +42,14 @@ type FormData struct {
 	Paths  []string `json:"paths" bson:"paths,omitempty" yaml:"paths,omitempty"`
 }
 
+// FormFile is a file part of a multipart/form-data request.
+type FormFile struct {
+	Name        string `json:"name" bson:"name" yaml:"name"`
+	Filename    string `json:"filename" bson:"filename" yaml:"filename"`
+	ContentType string `json:"content_type" bson:"content_type,omitempty" yaml:"content_type,omitempty"`
+	Data        []byte `json:"data" bson:"data" yaml:"data"`
+}
+
 type HTTPResp struct {
 	StatusCode    int               `json:"status_code" yaml:"status_code"` // e.g. 200
 	Header        map[string]string `json:"header" yaml:"header"`
@@ -45,4 +59,5 @@ type HTTPResp struct {
 	ProtoMinor    int               `json:"proto_minor" yaml:"proto_minor"`
 	Binary        string            `json:"binary" yaml:"binary,omitempty"`
 	Timestamp     time.Time         `json:"timestamp" yaml:"timestamp"`
+	Truncated     bool              `json:"truncated,omitempty" yaml:"truncated,omitempty"` // set when the body is cut at the max response body size
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"io"
 	"time"
 
 	"go.keploy.io/server/v2/config"
@@ -11,6 +12,14 @@ type HookOptions struct {
 	EnableTesting bool
 }
 
+// HookMetadata describes what was loaded by Hook. It is used for diagnosing
+// failures where the hooks could not be loaded (e.g. restricted kernels).
+type HookMetadata struct {
+	LoadedHooks   []string      `json:"loadedHooks" yaml:"loaded_hooks"`
+	ProxyPort     int           `json:"proxyPort" yaml:"proxy_port"`
+	StartDuration time.Duration `json:"startDuration" yaml:"start_duration"`
+}
+
 type OutgoingOptions struct {
 	Rules         []config.BypassRule
 	MongoPassword string
@@ -18,8 +27,22 @@ type OutgoingOptions struct {
 	SQLDelay       time.Duration // This is the same as Application delay.
 	FallBackOnMiss bool          // this enables to pass the request to the actual server if no mock is found during test mode.
 	Mocking        bool          // used to enable/disable mocking
+	// MockMatchStrategy picks the mock when multiple mocks match the same outgoing call
+	MockMatchStrategy MockMatchStrategy
 }
 
+// MockMatchStrategy is the order in which the mocks matching the same outgoing call are used.
+type MockMatchStrategy string
+
+const (
+	// FirstMatch uses the mocks in the order of their requests
+	FirstMatch MockMatchStrategy = "firstMatch"
+	// RoundRobin uses the least used of the mocks matching the call, so that each of them is used in turn
+	RoundRobin MockMatchStrategy = "roundRobin"
+	// Random uses any of the mocks matching the call
+	Random MockMatchStrategy = "random"
+)
+
 type IncomingOptions struct {
 	Filters []config.Filter
 }
@@ -32,6 +55,9 @@ type SetupOptions struct {
 
 type RunOptions struct {
 	//IgnoreErrors bool
+	// Stdout and Stderr get a copy of the output of the app when set, e.g. to wait for a readiness log line
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 //For test bench
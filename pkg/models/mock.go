@@ -1,14 +1,20 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
 
 type Mock struct {
-	Version      Version      `json:"Version,omitempty" bson:"Version,omitempty"`
-	Name         string       `json:"Name,omitempty" bson:"Name,omitempty"`
-	Kind         Kind         `json:"Kind,omitempty" bson:"Kind,omitempty"`
-	Spec         MockSpec     `json:"Spec,omitempty" bson:"Spec,omitempty"`
-	TestModeInfo TestModeInfo `json:"TestModeInfo,omitempty"  bson:"TestModeInfo,omitempty"` // Map for additional test mode information
-	ConnectionID string       `json:"ConnectionId,omitempty" bson:"ConnectionId,omitempty"`
+	Version      Version       `json:"Version,omitempty" bson:"Version,omitempty"`
+	Name         string        `json:"Name,omitempty" bson:"Name,omitempty"`
+	Kind         Kind          `json:"Kind,omitempty" bson:"Kind,omitempty"`
+	Spec         MockSpec      `json:"Spec,omitempty" bson:"Spec,omitempty"`
+	TestModeInfo TestModeInfo  `json:"TestModeInfo,omitempty"  bson:"TestModeInfo,omitempty"` // Map for additional test mode information
+	ConnectionID string        `json:"ConnectionId,omitempty" bson:"ConnectionId,omitempty"`
+	RecordedAt   time.Time     `json:"RecordedAt,omitempty" bson:"RecordedAt,omitempty"` // time the mock was recorded, the request timestamp of the mock when not set
+	TTL          time.Duration `json:"TTL,omitempty" bson:"TTL,omitempty"`               // lifetime of the mock added to the mock ttl of the test config before it is stale
 }
 
 type TestModeInfo struct {
@@ -17,6 +23,12 @@ type TestModeInfo struct {
 	SortOrder  int  `json:"sortOrder,omitempty" bson:"SortOrder,omitempty"`
 }
 
+// MockMigrator migrates the yaml spec of a mock of the kind from a schema version to another,
+// it reports whether the spec was changed.
+type MockMigrator interface {
+	Migrate(kind Kind, spec *yaml.Node) (bool, error)
+}
+
 func (m *Mock) GetKind() string {
 	return string(m.Kind)
 }
@@ -28,11 +28,13 @@ const (
 	SQL            Kind     = "MySQL"
 	Postgres       Kind     = "Postgres"
 	GRPC_EXPORT    Kind     = "gRPC"
+	WS             Kind     = "WebSocket"
 	Mongo          Kind     = "Mongo"
 	BodyTypeUtf8   BodyType = "utf-8"
 	BodyTypeBinary BodyType = "binary"
 	BodyTypePlain  BodyType = "PLAIN"
 	BodyTypeJSON   BodyType = "JSON"
+	BodyTypeXML    BodyType = "XML"
 	BodyTypeError  BodyType = "ERROR"
 )
 
@@ -48,17 +50,59 @@ type TestCase struct {
 	AllKeys  map[string][]string `json:"all_keys" bson:"all_keys"`
 	GrpcResp GrpcResp            `json:"grpcResp" bson:"grpcResp"`
 	GrpcReq  GrpcReq             `json:"grpcReq" bson:"grpcReq"`
+	WSReq    WSReq               `json:"wsReq" bson:"wsReq"`
+	WSResp   WSResp              `json:"wsResp" bson:"wsResp"`
 	Anchors  map[string][]string `json:"anchors" bson:"anchors"`
 	Noise    map[string][]string `json:"noise" bson:"noise"`
 	Mocks    []*Mock             `json:"mocks" bson:"mocks"`
 	Type     string              `json:"type" bson:"type"`
 	Curl     string              `json:"curl" bson:"curl"`
+	// DBAssertions are run against the database after the request of the test case is replayed
+	DBAssertions []DBAssertion `json:"db_assertions" bson:"db_assertions"`
+	// PreTestCaseScript and PostTestCaseScript are shell commands run before the request of the test case is
+	// replayed and after its response is compared, a failing script fails the test case
+	PreTestCaseScript  string `json:"pre_test_case_script" bson:"pre_test_case_script"`
+	PostTestCaseScript string `json:"post_test_case_script" bson:"post_test_case_script"`
+	// DependsOn are the names of the test cases of the test set this test case depends on, e.g. the one creating the resource it reads
+	DependsOn []string `json:"depends_on" bson:"depends_on"`
+	// APITimeout is the timeout in seconds of the request of the test case, overriding test.apiTimeout when set
+	APITimeout uint64 `json:"api_timeout" bson:"api_timeout"`
+}
+
+// DBAssertion is a query run against the database of the application, whose rows are compared with the expected rows.
+// The Driver is one of the DBAssertionDrivers registered in the keploy binary.
+type DBAssertion struct {
+	Driver       string                   `json:"driver" yaml:"driver" bson:"driver"`
+	DSN          string                   `json:"dsn" yaml:"dsn" bson:"dsn"`
+	Query        string                   `json:"query" yaml:"query" bson:"query"`
+	ExpectedRows []map[string]interface{} `json:"expectedRows" yaml:"expectedRows" bson:"expected_rows"`
 }
 
 func (tc *TestCase) GetKind() string {
 	return string(tc.Kind)
 }
 
+// DedupStrategy is how the requests of the test cases are compared to find the duplicate test cases.
+type DedupStrategy string
+
+const (
+	// DedupExact compares the method, the url and the body byte for byte
+	DedupExact DedupStrategy = "exact"
+	// DedupSemantic compares the json normalized bodies and the urls regardless of the order of the query parameters
+	DedupSemantic DedupStrategy = "semantic"
+)
+
+// NormalizeStrategy is how the actual response of a failing test case is accepted as its expected response.
+type NormalizeStrategy string
+
+const (
+	// NormalizeFull replaces the recorded response with the actual response
+	NormalizeFull NormalizeStrategy = "full"
+	// NormalizePartial only updates the status code, the headers and the body which did not match,
+	// keeping the fields of the recorded response edited by hand
+	NormalizePartial NormalizeStrategy = "partial"
+)
+
 type NoiseParams struct {
 	TestCaseID string              `json:"testCaseID"`
 	EditedBy   string              `json:"editedBy"`
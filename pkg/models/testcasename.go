@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TestCaseNameData is the data the name template of the test cases is executed with, e.g. the template
+// test-{{.Method}}-{{.URL}}-{{.StatusCode}} names the test case test-GET-/api/users-200.
+type TestCaseNameData struct {
+	Method string
+	// URL is the path of the request url, without the host and the query
+	URL        string
+	StatusCode int
+	// Index is the 1-based position of the test case in its test set
+	Index int
+}
+
+// ParseNameTemplate parses the name template of the test cases, it fails on the unknown fields of the template
+// as well, so that a typo is reported at startup rather than when the first test case is named.
+func ParseNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("testCaseName").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, TestCaseNameData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// TestCaseNameDataOf returns the name template data of the test case at the index of its test set.
+func TestCaseNameDataOf(tc *TestCase, index int) TestCaseNameData {
+	data := TestCaseNameData{Index: index}
+	switch tc.Kind {
+	case GRPC_EXPORT:
+		data.Method = "POST"
+		data.URL = tc.GrpcReq.Headers.PseudoHeaders[":path"]
+	default:
+		data.Method = string(tc.HTTPReq.Method)
+		data.URL = tc.HTTPReq.URL
+		if u, err := url.Parse(tc.HTTPReq.URL); err == nil && u.Path != "" {
+			data.URL = u.Path
+		}
+		data.StatusCode = tc.HTTPResp.StatusCode
+	}
+	return data
+}
+
+// ExecuteNameTemplate returns the name of the test case, the empty name is an error as it can't name a file.
+func ExecuteNameTemplate(tmpl *template.Template, data TestCaseNameData) (string, error) {
+	var name strings.Builder
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("failed to execute the name template of the test cases: %w", err)
+	}
+	if strings.TrimSpace(name.String()) == "" {
+		return "", errors.New("the name template of the test cases produced an empty name")
+	}
+	return strings.TrimSpace(name.String()), nil
+}
+
+// TestCaseName returns the name of the test case at the 1-based index of its test set executed from the name
+// template, suffixed with _N when the file name of the name is taken. It names the test cases when they are
+// recorded and when they are reported alike, so that the name in the report is the one of the file.
+func TestCaseName(tmpl *template.Template, tc *TestCase, index int, taken func(fileName string) bool) (string, error) {
+	name, err := ExecuteNameTemplate(tmpl, TestCaseNameDataOf(tc, index))
+	if err != nil {
+		return "", err
+	}
+	return UniqueTestCaseName(name, func(candidate string) bool { return taken(TestCaseFileName(candidate)) }), nil
+}
+
+// TestCaseFileName returns the name of the file of the test case named from the name template, the path of the
+// url is not a directory of the test set.
+func TestCaseFileName(name string) string {
+	return strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(name)
+}
+
+// UniqueTestCaseName returns the name suffixed with _N, the lowest N from 1 which is not taken, when the name is
+// already taken.
+func UniqueTestCaseName(name string, taken func(string) bool) string {
+	if !taken(name) {
+		return name
+	}
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
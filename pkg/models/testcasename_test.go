@@ -0,0 +1,120 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestTestCaseName(t *testing.T) {
+	users := &TestCase{
+		Kind:     HTTP,
+		HTTPReq:  HTTPReq{Method: "GET", URL: "http://localhost:8080/api/users?page=2"},
+		HTTPResp: HTTPResp{StatusCode: 200},
+	}
+	grpc := &TestCase{
+		Kind:    GRPC_EXPORT,
+		GrpcReq: GrpcReq{Headers: GrpcHeaders{PseudoHeaders: map[string]string{":path": "/users.Users/Get"}}},
+	}
+
+	tests := []struct {
+		name         string
+		template     string
+		tc           *TestCase
+		index        int
+		taken        []string
+		want         string
+		wantFileName string
+		wantErr      bool
+	}{
+		{
+			name:         "method, url and status code",
+			template:     "test-{{.Method}}-{{.URL}}-{{.StatusCode}}",
+			tc:           users,
+			index:        1,
+			want:         "test-GET-/api/users-200",
+			wantFileName: "test-GET-_api_users-200",
+		},
+		{
+			name:         "file name taken",
+			template:     "test-{{.Method}}-{{.URL}}-{{.StatusCode}}",
+			tc:           users,
+			index:        2,
+			taken:        []string{"test-GET-_api_users-200"},
+			want:         "test-GET-/api/users-200_1",
+			wantFileName: "test-GET-_api_users-200_1",
+		},
+		{
+			name:         "suffixed file name taken",
+			template:     "test-{{.Method}}-{{.URL}}-{{.StatusCode}}",
+			tc:           users,
+			index:        3,
+			taken:        []string{"test-GET-_api_users-200", "test-GET-_api_users-200_1"},
+			want:         "test-GET-/api/users-200_2",
+			wantFileName: "test-GET-_api_users-200_2",
+		},
+		{
+			name:         "index",
+			template:     "{{.Method}}-{{.Index}}",
+			tc:           users,
+			index:        7,
+			want:         "GET-7",
+			wantFileName: "GET-7",
+		},
+		{
+			name:         "grpc method",
+			template:     "{{.Method}}{{.URL}}",
+			tc:           grpc,
+			index:        1,
+			want:         "POST/users.Users/Get",
+			wantFileName: "POST_users.Users_Get",
+		},
+		{
+			name:     "empty name",
+			template: "{{if .StatusCode}}{{end}}",
+			tc:       users,
+			index:    1,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseNameTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseNameTemplate() error = %v", err)
+			}
+			taken := make(map[string]bool, len(tt.taken))
+			for _, name := range tt.taken {
+				taken[name] = true
+			}
+			got, err := TestCaseName(tmpl, tt.tc, tt.index, func(fileName string) bool { return taken[fileName] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TestCaseName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("TestCaseName() = %q, want %q", got, tt.want)
+			}
+			if !tt.wantErr && TestCaseFileName(got) != tt.wantFileName {
+				t.Fatalf("TestCaseFileName(%q) = %q, want %q", got, TestCaseFileName(got), tt.wantFileName)
+			}
+		})
+	}
+}
+
+func TestParseNameTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "known fields", template: "{{.Method}}-{{.URL}}-{{.StatusCode}}-{{.Index}}"},
+		{name: "syntax error", template: "{{.Method", wantErr: true},
+		{name: "unknown field", template: "{{.Path}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseNameTemplate(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNameTemplate(%q) error = %v, wantErr %v", tt.template, err, tt.wantErr)
+			}
+		})
+	}
+}
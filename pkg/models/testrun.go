@@ -5,14 +5,21 @@ import (
 )
 
 type TestReport struct {
-	Version Version      `json:"version" yaml:"version"`
-	Name    string       `json:"name" yaml:"name"`
-	Status  string       `json:"status" yaml:"status"`
-	Success int          `json:"success" yaml:"success"`
-	Failure int          `json:"failure" yaml:"failure"`
-	Total   int          `json:"total" yaml:"total"`
-	Tests   []TestResult `json:"tests" yaml:"tests,omitempty"`
-	TestSet string       `json:"testSet" yaml:"test_set"`
+	Version                Version       `json:"version" yaml:"version"`
+	Name                   string        `json:"name" yaml:"name"`
+	Status                 string        `json:"status" yaml:"status"`
+	Success                int           `json:"success" yaml:"success"`
+	Failure                int           `json:"failure" yaml:"failure"`
+	Total                  int           `json:"total" yaml:"total"`
+	Tests                  []TestResult  `json:"tests" yaml:"tests,omitempty"`
+	TestSet                string        `json:"testSet" yaml:"test_set"`
+	InstrumentationDetails *HookMetadata `json:"instrumentationDetails,omitempty" yaml:"instrumentation_details,omitempty"`
+	// AppliedNoise is the effective (global merged with test set) noise config used to compare the responses
+	AppliedNoise map[string]map[string][]string `json:"appliedNoise,omitempty" yaml:"applied_noise,omitempty"`
+	// ConsumedMocks are the names of the mocks consumed in the test set along with the test cases consuming them
+	ConsumedMocks map[string][]string `json:"consumedMocks,omitempty" yaml:"consumed_mocks,omitempty"`
+	// FuzzyMatchedMocks are the names of the consumed mocks which were matched fuzzily rather than exactly
+	FuzzyMatchedMocks []string `json:"fuzzyMatchedMocks,omitempty" yaml:"fuzzy_matched_mocks,omitempty"`
 }
 
 func (tr *TestReport) GetKind() string {
@@ -28,16 +35,81 @@ type TestResult struct {
 	TestCasePath string     `json:"testCasePath" yaml:"test_case_path"`
 	MockPath     string     `json:"mockPath" yaml:"mock_path"`
 	TestCaseID   string     `json:"testCaseID" yaml:"test_case_id"`
-	Req          HTTPReq    `json:"req" yaml:"req,omitempty"`
-	Res          HTTPResp   `json:"resp" yaml:"resp,omitempty"`
-	Noise        Noise      `json:"noise" yaml:"noise,omitempty"`
-	Result       Result     `json:"result" yaml:"result"`
+	// TestCaseName is the name of the test case executed from the name template, the TestCaseID is the name of its file
+	TestCaseName string   `json:"testCaseName,omitempty" yaml:"test_case_name,omitempty"`
+	Req          HTTPReq  `json:"req" yaml:"req,omitempty"`
+	Res          HTTPResp `json:"resp" yaml:"resp,omitempty"`
+	Noise        Noise    `json:"noise" yaml:"noise,omitempty"`
+	Result       Result   `json:"result" yaml:"result"`
+	Attempts     int      `json:"attempts,omitempty" yaml:"attempts,omitempty"` // number of times the test case is run, including the retries
+	Flaky        bool     `json:"flaky,omitempty" yaml:"flaky,omitempty"`       // set when the test case passed only on a retry
+	LatencyMs    int64    `json:"latencyMs" yaml:"latency_ms"`                  // time taken by the application to respond to the request
+	// OrderSensitive is set when the test case passed in some random orderings of its test set and failed in others
+	OrderSensitive bool `json:"orderSensitive,omitempty" yaml:"order_sensitive,omitempty"`
+	// FailureReason explains the failure of the test case which has no response to compare, e.g. a timed out request
+	FailureReason string `json:"failureReason,omitempty" yaml:"failure_reason,omitempty"`
+	// DBAssertionFailures are the database assertions of the test case which did not match
+	DBAssertionFailures []DBAssertionFailure `json:"dbAssertionFailures,omitempty" yaml:"db_assertion_failures,omitempty"`
+	// MockConsumptionEvents are the mocks consumed by the test case and whether they were matched fuzzily
+	MockConsumptionEvents []MockConsumptionEvent `json:"mockConsumptionEvents,omitempty" yaml:"mock_consumption_events,omitempty"`
+}
+
+// MockConsumptionEvent is a mock consumed by a test case, Fuzzy is set when the mock was not matched exactly,
+// e.g. by the schema of its body or the similarity of its request.
+type MockConsumptionEvent struct {
+	Name  string `json:"name" yaml:"name"`
+	Fuzzy bool   `json:"fuzzy,omitempty" yaml:"fuzzy,omitempty"`
+}
+
+// MockFidelityReport counts the mocks of the test run by how they were matched, it tells how well the mocks
+// represent the actual requests of the application to its dependencies.
+type MockFidelityReport struct {
+	TestRunID             string `json:"testRunID" yaml:"test_run_id"`
+	TotalMocks            int    `json:"totalMocks" yaml:"total_mocks"`
+	PerfectlyMatchedMocks int    `json:"perfectlyMatchedMocks" yaml:"perfectly_matched_mocks"`
+	FuzzilyMatchedMocks   int    `json:"fuzzilyMatchedMocks" yaml:"fuzzily_matched_mocks"` // matched within the body noise, by schema or similarity
+	UnmatchedMocks        int    `json:"unmatchedMocks" yaml:"unmatched_mocks"`
+}
+
+// MockCoverageReport lists the mocks of a test set and whether they were consumed in the test run, and by which test cases.
+type MockCoverageReport struct {
+	TestRunID string         `json:"testRunID" yaml:"test_run_id"`
+	TestSet   string         `json:"testSet" yaml:"test_set"`
+	Total     int            `json:"total" yaml:"total"`
+	Consumed  int            `json:"consumed" yaml:"consumed"`
+	Mocks     []MockCoverage `json:"mocks" yaml:"mocks"`
+}
+
+type MockCoverage struct {
+	Name       string   `json:"name" yaml:"name"`
+	Kind       Kind     `json:"kind" yaml:"kind"`
+	Consumed   bool     `json:"consumed" yaml:"consumed"`
+	ConsumedBy []string `json:"consumedBy,omitempty" yaml:"consumed_by,omitempty"` // empty when the consuming test cases ran in parallel
+}
+
+// DBAssertionFailure is a database assertion whose actual rows did not match the expected rows.
+type DBAssertionFailure struct {
+	Query    string `json:"query" yaml:"query"`
+	Expected string `json:"expected" yaml:"expected"`
+	Actual   string `json:"actual" yaml:"actual"`
+	Message  string `json:"message" yaml:"message"`
 }
 
 func (tr *TestResult) GetKind() string {
 	return string(tr.Kind)
 }
 
+// ReportOverwritePolicy defines what happens when a report already exists for a test run and test set
+// which was not written by the current run (e.g. left by a crashed run).
+type ReportOverwritePolicy string
+
+// constants for report overwrite policy
+const (
+	ReportOverwrite ReportOverwritePolicy = "overwrite" // replace the existing report
+	ReportAppend    ReportOverwritePolicy = "append"    // keep the existing report and write a new one next to it
+	ReportError     ReportOverwritePolicy = "error"     // fail instead of touching the existing report
+)
+
 type TestSetStatus string
 
 // constants for testSet status
@@ -50,6 +122,7 @@ const (
 	TestSetStatusFaultUserApp TestSetStatus = "APP_FAULT"
 	TestSetStatusInternalErr  TestSetStatus = "INTERNAL_ERR"
 	TestSetStatusFaultScript  TestSetStatus = "SCRIPT_FAULT"
+	TestSetStatusTimedOut     TestSetStatus = "TIMED_OUT"
 )
 
 func StringToTestSetStatus(s string) (TestSetStatus, error) {
@@ -68,6 +141,8 @@ func StringToTestSetStatus(s string) (TestSetStatus, error) {
 		return TestSetStatusFaultUserApp, nil
 	case "INTERNAL_ERR":
 		return TestSetStatusInternalErr, nil
+	case "TIMED_OUT":
+		return TestSetStatusTimedOut, nil
 	default:
 		return "", errors.New("invalid TestSetStatus value")
 	}
@@ -76,8 +151,16 @@ func StringToTestSetStatus(s string) (TestSetStatus, error) {
 type Result struct {
 	StatusCode    IntResult      `json:"status_code" bson:"status_code" yaml:"status_code"`
 	HeadersResult []HeaderResult `json:"headers_result" bson:"headers_result" yaml:"headers_result"`
-	BodyResult    []BodyResult   `json:"body_result" bson:"body_result" yaml:"body_result"`
-	DepResult     []DepResult    `json:"dep_result" bson:"dep_result" yaml:"dep_result"`
+	// HeadersDiff groups the unmatched headers by how the actual response changed them, nil when all the headers match.
+	HeadersDiff     *HeadersDiff     `json:"headers_diff,omitempty" bson:"headers_diff,omitempty" yaml:"headers_diff,omitempty"`
+	BodyResult      []BodyResult     `json:"body_result" bson:"body_result" yaml:"body_result"`
+	DepResult       []DepResult      `json:"dep_result" bson:"dep_result" yaml:"dep_result"`
+	FormFilesResult []FormFileResult `json:"form_files_result,omitempty" bson:"form_files_result,omitempty" yaml:"form_files_result,omitempty"`
+	// LatencyResult is the latency of the response against its budget, only set when a latency budget applies.
+	LatencyResult *LatencyResult `json:"latency_result,omitempty" bson:"latency_result,omitempty" yaml:"latency_result,omitempty"`
+	// ConfidenceScore is the ratio of the matched fields to all the fields of the response (0.0 - 1.0).
+	// A low score means most of the response is noised out and hardly compared.
+	ConfidenceScore float64 `json:"confidence_score" bson:"confidence_score" yaml:"confidence_score"`
 }
 
 type DepResult struct {
@@ -105,16 +188,54 @@ type HeaderResult struct {
 	Actual   Header `json:"actual" bson:"actual" yaml:"actual"`
 }
 
+// HeadersDiff is the difference of the actual headers to the expected ones by header key, the values are the
+// expected and the actual values of the header joined by commas, empty for a missing header.
+type HeadersDiff struct {
+	Added    map[string][2]string `json:"added,omitempty" bson:"added,omitempty" yaml:"added,omitempty"`
+	Removed  map[string][2]string `json:"removed,omitempty" bson:"removed,omitempty" yaml:"removed,omitempty"`
+	Modified map[string][2]string `json:"modified,omitempty" bson:"modified,omitempty" yaml:"modified,omitempty"`
+}
+
 type Header struct {
 	Key   string   `json:"key" bson:"key" yaml:"key"`
 	Value []string `json:"value" bson:"value" yaml:"value"`
 }
 
+// FormFileResult is the comparison of a multipart file part, the files are compared by their sha256 hash.
+type FormFileResult struct {
+	Normal   bool           `json:"normal" bson:"normal" yaml:"normal"`
+	Name     string         `json:"name" bson:"name" yaml:"name"`
+	Expected FormFileDigest `json:"expected" bson:"expected" yaml:"expected"`
+	Actual   FormFileDigest `json:"actual" bson:"actual" yaml:"actual"`
+}
+
+type FormFileDigest struct {
+	Filename    string `json:"filename" bson:"filename" yaml:"filename"`
+	ContentType string `json:"content_type" bson:"content_type" yaml:"content_type"`
+	SHA256      string `json:"sha256" bson:"sha256" yaml:"sha256"`
+}
+
+// LatencyResult compares the latency of the response with its budget, in milliseconds.
+type LatencyResult struct {
+	Normal   bool  `json:"normal" bson:"normal" yaml:"normal"`
+	BudgetMs int64 `json:"budget_ms" bson:"budget_ms" yaml:"budget_ms"`
+	ActualMs int64 `json:"actual_ms" bson:"actual_ms" yaml:"actual_ms"`
+}
+
 type BodyResult struct {
 	Normal   bool     `json:"normal" bson:"normal" yaml:"normal"`
 	Type     BodyType `json:"type" bson:"type" yaml:"type"`
 	Expected string   `json:"expected" bson:"expected" yaml:"expected"`
 	Actual   string   `json:"actual" bson:"actual" yaml:"actual"`
+	// Diffs are the mismatching fields of the body, only set by the configurable body comparators
+	Diffs []BodyDiff `json:"diffs,omitempty" bson:"diffs,omitempty" yaml:"diffs,omitempty"`
+}
+
+// BodyDiff is a mismatching field of a body, the values are json encoded and empty when the field is missing.
+type BodyDiff struct {
+	Path     string `json:"path" bson:"path" yaml:"path"`
+	Expected string `json:"expected" bson:"expected" yaml:"expected"`
+	Actual   string `json:"actual" bson:"actual" yaml:"actual"`
 }
 
 type TestStatus string
@@ -125,8 +246,22 @@ const (
 	TestStatusRunning TestStatus = "RUNNING"
 	TestStatusFailed  TestStatus = "FAILED"
 	TestStatusPassed  TestStatus = "PASSED"
+	// TestStatusRecorded is the status of the test cases whose response got recorded instead of compared
+	TestStatusRecorded TestStatus = "RECORDED"
 )
 
+// TestProgressEvent is sent when a test case starts running, with the RUNNING status, and when it completes.
+// ElapsedMs is the time elapsed since the test set started.
+type TestProgressEvent struct {
+	TestRunID      string     `json:"testRunID" yaml:"test_run_id"`
+	TestSetID      string     `json:"testSetID" yaml:"test_set_id"`
+	TestCaseID     string     `json:"testCaseID" yaml:"test_case_id"`
+	Status         TestStatus `json:"status" yaml:"status"`
+	ElapsedMs      int64      `json:"elapsedMs" yaml:"elapsed_ms"`
+	TotalCases     int        `json:"totalCases" yaml:"total_cases"`
+	CompletedCases int        `json:"completedCases" yaml:"completed_cases"`
+}
+
 type (
 	Noise        map[string][]string
 	GlobalNoise  map[string]map[string][]string
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// WSDirection is the direction of a websocket frame.
+type WSDirection string
+
+const (
+	WSDirectionClient WSDirection = "client" // sent by the client to the application
+	WSDirectionServer WSDirection = "server" // sent by the application to the client
+)
+
+// WSFrame is a data frame of a websocket connection. The payload of the binary frames (opcode 2) is base64 encoded.
+type WSFrame struct {
+	Opcode    int         `json:"opcode" yaml:"opcode"`
+	Payload   string      `json:"payload" yaml:"payload"`
+	Direction WSDirection `json:"direction" yaml:"direction"`
+	Timestamp time.Time   `json:"timestamp" yaml:"timestamp"`
+}
+
+// WSReq is the websocket handshake request along with the frames sent by the client, in order.
+type WSReq struct {
+	URL       string            `json:"url" yaml:"url"`
+	Header    map[string]string `json:"header" yaml:"header"`
+	Frames    []WSFrame         `json:"frames" yaml:"frames"`
+	Timestamp time.Time         `json:"timestamp" yaml:"timestamp"`
+}
+
+// WSResp is the websocket handshake response along with the frames sent by the application, in order.
+type WSResp struct {
+	StatusCode int               `json:"status_code" yaml:"status_code"`
+	Header     map[string]string `json:"header" yaml:"header"`
+	Frames     []WSFrame         `json:"frames" yaml:"frames"`
+	Timestamp  time.Time         `json:"timestamp" yaml:"timestamp"`
+}
+
+// WSSchema is the yaml spec of a websocket test case.
+type WSSchema struct {
+	Metadata   map[string]string      `json:"metadata" yaml:"metadata"`
+	Request    WSReq                  `json:"req" yaml:"req"`
+	Response   WSResp                 `json:"resp" yaml:"resp"`
+	Assertions map[string]interface{} `json:"assertions" yaml:"assertions,omitempty"`
+	Created    int64                  `json:"created" yaml:"created,omitempty"`
+}
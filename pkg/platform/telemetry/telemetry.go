@@ -3,8 +3,10 @@ package telemetry
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"runtime"
+	"sync"
 	"time"
 
 	"go.keploy.io/server/v2/pkg/models"
@@ -13,6 +15,9 @@ import (
 
 var teleURL = "https://telemetry.keploy.io/analytics"
 
+// DefaultBatchSize is the number of buffered events after which the buffer is flushed.
+const DefaultBatchSize = 10
+
 type Telemetry struct {
 	Enabled        bool
 	OffMode        bool
@@ -21,6 +26,12 @@ type Telemetry struct {
 	KeployVersion  string
 	GlobalMap      map[string]interface{}
 	client         *http.Client
+	// BatchSize is the number of test run events buffered before sending them
+	BatchSize int
+	mu        sync.Mutex
+	buffer    []models.TeleEvent
+	// sending tracks the buffered events being sent in the background, so that Flush waits for them
+	sending sync.WaitGroup
 }
 
 type Options struct {
@@ -28,9 +39,13 @@ type Options struct {
 	Version        string
 	GlobalMap      map[string]interface{}
 	InstallationID string
+	BatchSize      int
 }
 
 func NewTelemetry(logger *zap.Logger, opt Options) *Telemetry {
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = DefaultBatchSize
+	}
 	return &Telemetry{
 		Enabled:        opt.Enabled,
 		logger:         logger,
@@ -38,6 +53,7 @@ func NewTelemetry(logger *zap.Logger, opt Options) *Telemetry {
 		GlobalMap:      opt.GlobalMap,
 		InstallationID: opt.InstallationID,
 		client:         &http.Client{Timeout: 10 * time.Second},
+		BatchSize:      opt.BatchSize,
 	}
 }
 
@@ -53,12 +69,62 @@ func (tel *Telemetry) Ping() {
 	}()
 }
 
+// TestSetRun is buffered and sent in batches, as it is called once for every test set of the run
 func (tel *Telemetry) TestSetRun(success int, failure int, testSet string, runStatus string) {
-	go tel.SendTelemetry("TestSetRun", map[string]interface{}{"Passed-Tests": success, "Failed-Tests": failure, "Test-Set": testSet, "Run-Status": runStatus})
+	tel.queue("TestSetRun", map[string]interface{}{"Passed-Tests": success, "Failed-Tests": failure, "Test-Set": testSet, "Run-Status": runStatus})
 }
 
 func (tel *Telemetry) TestRun(success int, failure int, testSets int, runStatus string) {
-	go tel.SendTelemetry("TestRun", map[string]interface{}{"Passed-Tests": success, "Failed-Tests": failure, "Test-Sets": testSets, "Run-Status": runStatus})
+	tel.queue("TestRun", map[string]interface{}{"Passed-Tests": success, "Failed-Tests": failure, "Test-Sets": testSets, "Run-Status": runStatus})
+}
+
+// queue buffers the event and flushes the buffer once it reaches the batch size
+func (tel *Telemetry) queue(eventType string, output map[string]interface{}) {
+	if !tel.Enabled {
+		return
+	}
+	tel.mu.Lock()
+	tel.buffer = append(tel.buffer, tel.newEvent(eventType, output))
+	full := len(tel.buffer) >= tel.BatchSize
+	if full {
+		tel.sending.Add(1)
+	}
+	tel.mu.Unlock()
+	if full {
+		go func() {
+			defer tel.sending.Done()
+			tel.sendBuffered()
+		}()
+	}
+}
+
+// Flush sends all the buffered events and waits for the ones already being sent, it is called before the process
+// can exit so that no event is lost.
+func (tel *Telemetry) Flush() {
+	tel.sendBuffered()
+	tel.sending.Wait()
+}
+
+// sendBuffered sends the buffered events as json arrays of at most BatchSize events, one request per batch.
+func (tel *Telemetry) sendBuffered() {
+	tel.mu.Lock()
+	events := tel.buffer
+	tel.buffer = nil
+	tel.mu.Unlock()
+	batchSize := tel.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	for len(events) > 0 {
+		n := min(batchSize, len(events))
+		bin, err := json.Marshal(events[:n])
+		events = events[n:]
+		if err != nil {
+			tel.logger.Debug("failed to marshal the batch of events", zap.Error(err))
+			continue
+		}
+		tel.send(bin)
+	}
 }
 
 // MockTestRun is Telemetry event for the Mocking feature test run
@@ -90,46 +156,57 @@ func (tel *Telemetry) RecordedTestCaseMock(mockType string) {
 
 func (tel *Telemetry) SendTelemetry(eventType string, output ...map[string]interface{}) {
 	if tel.Enabled {
-		event := models.TeleEvent{
-			EventType: eventType,
-			CreatedAt: time.Now().Unix(),
-		}
-		event.Meta = make(map[string]interface{})
+		var meta map[string]interface{}
 		if len(output) != 0 {
-			event.Meta = output[0]
-		}
-
-		if tel.GlobalMap != nil {
-			event.Meta["global-map"] = tel.GlobalMap
+			meta = output[0]
 		}
-
-		event.InstallationID = tel.InstallationID
-		event.OS = runtime.GOOS
-		event.KeployVersion = tel.KeployVersion
-		event.Arch = runtime.GOARCH
-		bin, err := marshalEvent(event, tel.logger)
+		bin, err := marshalEvent(tel.newEvent(eventType, meta), tel.logger)
 		if err != nil {
 			tel.logger.Debug("failed to marshal event", zap.Error(err))
 			return
 		}
+		tel.send(bin)
+	}
+}
 
-		req, err := http.NewRequest(http.MethodPost, teleURL, bytes.NewBuffer(bin))
-		if err != nil {
-			tel.logger.Debug("failed to create request for analytics", zap.Error(err))
-			return
-		}
+func (tel *Telemetry) newEvent(eventType string, meta map[string]interface{}) models.TeleEvent {
+	event := models.TeleEvent{
+		EventType: eventType,
+		CreatedAt: time.Now().Unix(),
+	}
+	event.Meta = make(map[string]interface{})
+	if meta != nil {
+		event.Meta = meta
+	}
 
-		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if tel.GlobalMap != nil {
+		event.Meta["global-map"] = tel.GlobalMap
+	}
 
-		resp, err := tel.client.Do(req)
-		if err != nil {
-			tel.logger.Debug("failed to send request for analytics", zap.Error(err))
-			return
-		}
-		_, err = unmarshalResp(resp, tel.logger)
-		if err != nil {
-			tel.logger.Debug("failed to unmarshal response", zap.Error(err))
-			return
-		}
+	event.InstallationID = tel.InstallationID
+	event.OS = runtime.GOOS
+	event.KeployVersion = tel.KeployVersion
+	event.Arch = runtime.GOARCH
+	return event
+}
+
+func (tel *Telemetry) send(bin []byte) {
+	req, err := http.NewRequest(http.MethodPost, teleURL, bytes.NewBuffer(bin))
+	if err != nil {
+		tel.logger.Debug("failed to create request for analytics", zap.Error(err))
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := tel.client.Do(req)
+	if err != nil {
+		tel.logger.Debug("failed to send request for analytics", zap.Error(err))
+		return
+	}
+	_, err = unmarshalResp(resp, tel.logger)
+	if err != nil {
+		tel.logger.Debug("failed to unmarshal response", zap.Error(err))
+		return
 	}
 }
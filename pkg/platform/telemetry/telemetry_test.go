@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestFlushSendsTheBufferedEventsInBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		events    int
+		// minRequests is the number of requests when no event is queued while a full batch is being sent
+		minRequests int
+	}{
+		{name: "below the batch size", batchSize: 10, events: 3, minRequests: 1},
+		{name: "full batches sent in the background", batchSize: 2, events: 5, minRequests: 3},
+		{name: "exactly one batch", batchSize: 4, events: 4, minRequests: 1},
+		{name: "default batch size", events: 10, minRequests: 1},
+		{name: "no events", batchSize: 10, events: 0, minRequests: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchSize := tt.batchSize
+			if batchSize == 0 {
+				batchSize = DefaultBatchSize
+			}
+			var mu sync.Mutex
+			var received []models.TeleEvent
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read the request: %v", err)
+				}
+				var events []models.TeleEvent
+				if err := json.Unmarshal(body, &events); err != nil {
+					t.Errorf("the request is not a batch of events: %v, body: %s", err, body)
+				}
+				if len(events) == 0 || len(events) > batchSize {
+					t.Errorf("the request has %d events, want between 1 and %d", len(events), batchSize)
+				}
+				mu.Lock()
+				received = append(received, events...)
+				requests++
+				mu.Unlock()
+				_, _ = w.Write([]byte(`{"InstallationID":"test"}`))
+			}))
+			defer server.Close()
+			defer func(url string) { teleURL = url }(teleURL)
+			teleURL = server.URL
+
+			tel := NewTelemetry(zap.NewNop(), Options{Enabled: true, BatchSize: tt.batchSize})
+			for i := 0; i < tt.events; i++ {
+				tel.TestSetRun(1, 0, "test-set-0", "PASSED")
+			}
+			tel.Flush()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(received) != tt.events {
+				t.Fatalf("received %d events, want %d", len(received), tt.events)
+			}
+			if requests < tt.minRequests || requests > tt.events {
+				t.Fatalf("sent %d requests for %d events with the batch size %d", requests, tt.events, batchSize)
+			}
+			if tt.minRequests == 1 && requests != 1 {
+				t.Fatalf("sent %d requests, want the events of a single batch in one request", requests)
+			}
+			for _, event := range received {
+				if event.EventType != "TestSetRun" {
+					t.Errorf("received the event type %q, want TestSetRun", event.EventType)
+				}
+			}
+		})
+	}
+}
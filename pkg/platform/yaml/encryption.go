@@ -0,0 +1,121 @@
+package yaml
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// AES256GCM is the algorithm the files are encrypted with
+	AES256GCM = "aes-256-gcm"
+	// encryptedHeader starts the encrypted files, it is followed by the base64 encoded nonce and sealed data
+	encryptedHeader = "# keploy:encrypted " + AES256GCM + "\n"
+)
+
+// Cipher encrypts the yaml files at rest, e.g. the mocks holding PII or secrets committed to version control.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates the cipher of the algorithm with the key of the key file. The key file is the path of the file
+// holding the key or env:NAME to read the key from the NAME environment variable, the key is 32 bytes either raw,
+// hex or base64 encoded.
+func NewCipher(algorithm, keyFile string) (*Cipher, error) {
+	if !strings.EqualFold(algorithm, AES256GCM) {
+		return nil, fmt.Errorf("unsupported encryption algorithm %q, it should be %s", algorithm, AES256GCM)
+	}
+	key, err := readKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the aes cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the gcm cipher: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+func readKey(keyFile string) ([]byte, error) {
+	var raw []byte
+	if name, ok := strings.CutPrefix(keyFile, "env:"); ok {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("the environment variable %s of the encryption key is not set", name)
+		}
+		raw = []byte(value)
+	} else {
+		if keyFile == "" {
+			return nil, errors.New("the key file of the encryption is not set")
+		}
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the encryption key file: %w", err)
+		}
+		raw = data
+	}
+
+	if len(raw) == 32 {
+		return raw, nil
+	}
+	trimmed := strings.TrimSpace(string(raw))
+	if key, err := hex.DecodeString(trimmed); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(trimmed); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if len(trimmed) == 32 {
+		return []byte(trimmed), nil
+	}
+	return nil, errors.New("the encryption key should be 32 bytes, raw, hex or base64 encoded")
+}
+
+// IsEncrypted reports whether the data of the file is encrypted by a cipher.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedHeader))
+}
+
+// Encrypt seals the data with a random nonce, the encrypted data is a yaml comment header followed by the base64
+// encoded nonce and sealed data.
+func (c *Cipher) Encrypt(data []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate the nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, data, nil)
+	encrypted := []byte(encryptedHeader)
+	encrypted = append(encrypted, base64.StdEncoding.EncodeToString(sealed)...)
+	return append(encrypted, '\n'), nil
+}
+
+// Decrypt opens the encrypted data, the data which is not encrypted is returned as is.
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data[len(encryptedHeader):])))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the encrypted data: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+	plain, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data, the encryption key may be wrong: %w", err)
+	}
+	return plain, nil
+}
@@ -9,9 +9,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,7 +29,9 @@ type MockYaml struct {
 	MockPath  string
 	MockName  string
 	Logger    *zap.Logger
+	Cipher    *yaml.Cipher // encrypts the mock files at rest when set
 	idCounter int64
+	encMu     sync.Mutex
 }
 
 func New(Logger *zap.Logger, mockPath string, mockName string) *MockYaml {
@@ -59,7 +64,7 @@ func (ys *MockYaml) UpdateMocks(ctx context.Context, testSetID string, mockNames
 		utils.LogError(ys.Logger, err, "failed to find the mocks yaml file")
 		return err
 	}
-	data, err := yaml.ReadFile(ctx, ys.Logger, path, mockFileName)
+	data, err := ys.readMockFile(ctx, path, mockFileName)
 	if err != nil {
 		utils.LogError(ys.Logger, err, "failed to read the mocks from yaml file", zap.Any("at path", filepath.Join(path, mockFileName+".yaml")))
 		return err
@@ -111,7 +116,7 @@ func (ys *MockYaml) UpdateMocks(ctx context.Context, testSetID string, mockNames
 			utils.LogError(ys.Logger, err, "failed to marshal the mock to yaml", zap.Any("mock", newMock.Name), zap.Any("for testset", testSetID))
 			return err
 		}
-		err = yaml.WriteFile(ctx, ys.Logger, path, mockFileName, data, true)
+		err = ys.writeMockFile(ctx, path, mockFileName, data, true)
 		if err != nil {
 			utils.LogError(ys.Logger, err, "failed to write the mock to yaml", zap.Any("mock", newMock.Name), zap.Any("for testset", testSetID))
 			return err
@@ -122,6 +127,9 @@ func (ys *MockYaml) UpdateMocks(ctx context.Context, testSetID string, mockNames
 
 func (ys *MockYaml) InsertMock(ctx context.Context, mock *models.Mock, testSetID string) error {
 	mock.Name = fmt.Sprint("mock-", ys.getNextID())
+	if mock.RecordedAt.IsZero() {
+		mock.RecordedAt = time.Now()
+	}
 	mockYaml, err := EncodeMock(mock, ys.Logger)
 	if err != nil {
 		return err
@@ -135,14 +143,104 @@ func (ys *MockYaml) InsertMock(ctx context.Context, mock *models.Mock, testSetID
 	if err != nil {
 		return err
 	}
-	err = yaml.WriteFile(ctx, ys.Logger, mockPath, mockFileName, data, true)
+	err = ys.writeMockFile(ctx, mockPath, mockFileName, data, true)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (ys *MockYaml) GetFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time) ([]*models.Mock, error) {
+// ReplaceMocks replaces the mocks of the test set with the mocks, their yaml specs migrated by the migrator when
+// it is not nil. It returns the number of the mocks changed by the migrator.
+func (ys *MockYaml) ReplaceMocks(ctx context.Context, testSetID string, mocks []*models.Mock, migrator models.MockMigrator) (int, error) {
+	mockFileName := "mocks"
+	if ys.MockName != "" {
+		mockFileName = ys.MockName
+	}
+	path := filepath.Join(ys.MockPath, testSetID)
+
+	// the decoded mocks are encoded in the schema the decoder reads, so a mock is counted as migrated when the
+	// migrator changes its spec as it is in the mock file
+	var fileDocs map[string]*yaml.NetworkTrafficDoc
+	if migrator != nil {
+		var err error
+		fileDocs, err = ys.readMockDocs(ctx, path, mockFileName)
+		if err != nil {
+			utils.LogError(ys.Logger, err, "failed to read the mocks to migrate", zap.Any("for testset", testSetID))
+			return 0, err
+		}
+	}
+
+	migrated := 0
+	var data []byte
+	for i, mock := range mocks {
+		mockYaml, err := EncodeMock(mock, ys.Logger)
+		if err != nil {
+			utils.LogError(ys.Logger, err, "failed to encode the mock to yaml", zap.Any("mock", mock.Name), zap.Any("for testset", testSetID))
+			return 0, err
+		}
+		if migrator != nil {
+			changed, err := migrator.Migrate(mockYaml.Kind, &mockYaml.Spec)
+			if err != nil {
+				return 0, fmt.Errorf("failed to migrate the mock %s: %w", mock.Name, err)
+			}
+			if fileDoc, ok := fileDocs[mock.Name]; ok {
+				changed, err = migrator.Migrate(fileDoc.Kind, &fileDoc.Spec)
+				if err != nil {
+					return 0, fmt.Errorf("failed to migrate the mock %s: %w", mock.Name, err)
+				}
+			}
+			if changed {
+				migrated++
+			}
+		}
+		doc, err := yamlLib.Marshal(&mockYaml)
+		if err != nil {
+			utils.LogError(ys.Logger, err, "failed to marshal the mock to yaml", zap.Any("mock", mock.Name), zap.Any("for testset", testSetID))
+			return 0, err
+		}
+		if i > 0 {
+			data = append(data, []byte("---\n")...)
+		}
+		data = append(data, doc...)
+	}
+
+	// the mocks are written at once, so that a failure to encode a mock leaves the mock file untouched
+	err := ys.writeMockFile(ctx, path, mockFileName, data, false)
+	if err != nil {
+		utils.LogError(ys.Logger, err, "failed to write the mocks to yaml", zap.Any("for testset", testSetID))
+		return 0, err
+	}
+	return migrated, nil
+}
+
+// readMockDocs returns the yaml documents of the mock file by the names of their mocks.
+func (ys *MockYaml) readMockDocs(ctx context.Context, path, name string) (map[string]*yaml.NetworkTrafficDoc, error) {
+	docs := map[string]*yaml.NetworkTrafficDoc{}
+	if !fileExists(filepath.Join(path, name+".yaml")) {
+		return docs, nil
+	}
+	data, err := ys.readMockFile(ctx, path, name)
+	if err != nil {
+		return nil, err
+	}
+	dec := yamlLib.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc *yaml.NetworkTrafficDoc
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the yaml file documents. error: %v", err.Error())
+		}
+		if doc != nil {
+			docs[doc.Name] = doc
+		}
+	}
+}
+
+func (ys *MockYaml) GetFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time, cutoff time.Time) ([]*models.Mock, error) {
 
 	var tcsMocks = make([]*models.Mock, 0)
 	var filteredTcsMocks = make([]*models.Mock, 0)
@@ -159,7 +257,7 @@ func (ys *MockYaml) GetFilteredMocks(ctx context.Context, testSetID string, afte
 
 	if _, err := os.Stat(mockPath); err == nil {
 		var mockYamls []*yaml.NetworkTrafficDoc
-		data, err := yaml.ReadFile(ctx, ys.Logger, path, mockFileName)
+		data, err := ys.readMockFile(ctx, path, mockFileName)
 		if err != nil {
 			utils.LogError(ys.Logger, err, "failed to read the mocks from config yaml", zap.Any("session", filepath.Base(path)))
 			return nil, err
@@ -199,6 +297,7 @@ func (ys *MockYaml) GetFilteredMocks(ctx context.Context, testSetID string, afte
 			}
 		}
 	}
+	tcsMocks = ys.excludeStaleMocks(tcsMocks, testSetID, cutoff)
 	filteredTcsMocks, _ = ys.filterByTimeStamp(ctx, tcsMocks, afterTime, beforeTime, ys.Logger)
 
 	sort.SliceStable(filteredTcsMocks, func(i, j int) bool {
@@ -208,7 +307,7 @@ func (ys *MockYaml) GetFilteredMocks(ctx context.Context, testSetID string, afte
 	return filteredTcsMocks, nil
 }
 
-func (ys *MockYaml) GetUnFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time) ([]*models.Mock, error) {
+func (ys *MockYaml) GetUnFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time, cutoff time.Time) ([]*models.Mock, error) {
 
 	var configMocks = make([]*models.Mock, 0)
 
@@ -226,7 +325,7 @@ func (ys *MockYaml) GetUnFilteredMocks(ctx context.Context, testSetID string, af
 
 	if _, err := os.Stat(mockPath); err == nil {
 		var mockYamls []*yaml.NetworkTrafficDoc
-		data, err := yaml.ReadFile(ctx, ys.Logger, path, mockName)
+		data, err := ys.readMockFile(ctx, path, mockName)
 		if err != nil {
 			utils.LogError(ys.Logger, err, "failed to read the mocks from config yaml", zap.Any("session", filepath.Base(path)))
 			return nil, err
@@ -265,6 +364,7 @@ func (ys *MockYaml) GetUnFilteredMocks(ctx context.Context, testSetID string, af
 			}
 		}
 	}
+	configMocks = ys.excludeStaleMocks(configMocks, testSetID, cutoff)
 
 	filteredMocks, unfilteredMocks := ys.filterByTimeStamp(ctx, configMocks, afterTime, beforeTime, ys.Logger)
 
@@ -285,10 +385,70 @@ func (ys *MockYaml) GetUnFilteredMocks(ctx context.Context, testSetID string, af
 	return mocks, nil
 }
 
+// GetMocksGroupedByEndpoint groups all the mocks of the test set by the endpoint they mock, "METHOD /path" without
+// the query for the http mocks and the kind of the mock for the other protocols.
+func (ys *MockYaml) GetMocksGroupedByEndpoint(ctx context.Context, testSetID string) (map[string][]*models.Mock, error) {
+	// the zero times select all the mocks of the test set
+	filteredMocks, err := ys.GetFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the filtered mocks: %w", err)
+	}
+	unfilteredMocks, err := ys.GetUnFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the unfiltered mocks: %w", err)
+	}
+
+	groups := map[string][]*models.Mock{}
+	for _, mock := range append(filteredMocks, unfilteredMocks...) {
+		endpoint := mockEndpoint(mock)
+		groups[endpoint] = append(groups[endpoint], mock)
+	}
+	return groups, nil
+}
+
+// mockEndpoint returns the endpoint of the mock, its method and path for the http mocks or its kind.
+func mockEndpoint(mock *models.Mock) string {
+	if mock.Kind != models.HTTP || mock.Spec.HTTPReq == nil {
+		return string(mock.Kind)
+	}
+	path := mock.Spec.HTTPReq.URL
+	if u, err := url.Parse(path); err == nil {
+		path = u.Path
+	} else if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" {
+		path = "/"
+	}
+	return strings.ToUpper(string(mock.Spec.HTTPReq.Method)) + " " + path
+}
+
 func (ys *MockYaml) getNextID() int64 {
 	return atomic.AddInt64(&ys.idCounter, 1)
 }
 
+// excludeStaleMocks drops the mocks whose recorded time plus their ttl is before the cutoff, the mocks of a retired
+// api version replayed months after being recorded. The zero cutoff keeps all the mocks, and so do the mocks
+// without a recorded time.
+func (ys *MockYaml) excludeStaleMocks(mocks []*models.Mock, testSetID string, cutoff time.Time) []*models.Mock {
+	if cutoff.IsZero() {
+		return mocks
+	}
+	fresh := make([]*models.Mock, 0, len(mocks))
+	for _, mock := range mocks {
+		recordedAt := mock.RecordedAt
+		if recordedAt.IsZero() {
+			recordedAt = mock.Spec.ReqTimestampMock
+		}
+		if !recordedAt.IsZero() && recordedAt.Add(mock.TTL).Before(cutoff) {
+			ys.Logger.Warn("excluding the stale mock, it was recorded before the mock ttl", zap.String("mock", mock.Name), zap.Time("recorded at", recordedAt), zap.String("testset", testSetID))
+			continue
+		}
+		fresh = append(fresh, mock)
+	}
+	return fresh
+}
+
 func (ys *MockYaml) filterByTimeStamp(_ context.Context, m []*models.Mock, afterTime time.Time, beforeTime time.Time, logger *zap.Logger) ([]*models.Mock, []*models.Mock) {
 
 	filteredMocks := make([]*models.Mock, 0)
@@ -0,0 +1,72 @@
+//go:build linux
+
+package mockdb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestExcludeStaleMocks(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	mock := func(name string, recordedAt, reqTimestamp time.Time, ttl time.Duration) *models.Mock {
+		return &models.Mock{
+			Name:       name,
+			RecordedAt: recordedAt,
+			TTL:        ttl,
+			Spec:       models.MockSpec{ReqTimestampMock: reqTimestamp},
+		}
+	}
+	mocks := []*models.Mock{
+		mock("fresh", now.Add(-time.Hour), time.Time{}, 0),
+		mock("week-old", now.Add(-7*day), time.Time{}, 0),
+		mock("month-old", now.Add(-31*day), time.Time{}, 0),
+		mock("year-old", now.Add(-365*day), time.Time{}, 0),
+		mock("year-old-with-a-year-ttl", now.Add(-365*day), time.Time{}, 366*day),
+		mock("year-old-request", time.Time{}, now.Add(-365*day), 0),
+		mock("no-timestamp", time.Time{}, time.Time{}, 0),
+	}
+
+	tests := []struct {
+		name   string
+		cutoff time.Time
+		want   []string
+	}{
+		{
+			name: "no cutoff",
+			want: []string{"fresh", "week-old", "month-old", "year-old", "year-old-with-a-year-ttl", "year-old-request", "no-timestamp"},
+		},
+		{
+			name:   "30 days",
+			cutoff: now.Add(-30 * day),
+			want:   []string{"fresh", "week-old", "year-old-with-a-year-ttl", "no-timestamp"},
+		},
+		{
+			name:   "1 day",
+			cutoff: now.Add(-day),
+			want:   []string{"fresh", "year-old-with-a-year-ttl", "no-timestamp"},
+		},
+		{
+			name:   "2 years",
+			cutoff: now.Add(-2 * 365 * day),
+			want:   []string{"fresh", "week-old", "month-old", "year-old", "year-old-with-a-year-ttl", "year-old-request", "no-timestamp"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ys := New(zap.NewNop(), t.TempDir(), "mocks")
+			var got []string
+			for _, m := range ys.excludeStaleMocks(mocks, "test-set-0", tt.cutoff) {
+				got = append(got, m.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("excludeStaleMocks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+//go:build linux
+
+package mockdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.keploy.io/server/v2/pkg/platform/yaml"
+)
+
+// readMockFile reads the mock file, decrypting it when it is encrypted.
+func (ys *MockYaml) readMockFile(ctx context.Context, path, name string) ([]byte, error) {
+	data, err := yaml.ReadFile(ctx, ys.Logger, path, name)
+	if err != nil {
+		return nil, err
+	}
+	if !yaml.IsEncrypted(data) {
+		return data, nil
+	}
+	if ys.Cipher == nil {
+		return nil, fmt.Errorf("the mock file %s is encrypted, configure the mockEncryption to read it", filepath.Join(path, name+".yaml"))
+	}
+	return ys.Cipher.Decrypt(data)
+}
+
+// writeMockFile writes the mock file, encrypted when a cipher is set. The encrypted file can't be appended to,
+// so its mocks are decrypted and encrypted again with the appended mocks.
+func (ys *MockYaml) writeMockFile(ctx context.Context, path, name string, data []byte, isAppend bool) error {
+	if ys.Cipher == nil {
+		return yaml.WriteFile(ctx, ys.Logger, path, name, data, isAppend)
+	}
+	ys.encMu.Lock()
+	defer ys.encMu.Unlock()
+
+	if isAppend && fileExists(filepath.Join(path, name+".yaml")) {
+		existing, err := ys.readMockFile(ctx, path, name)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			if existing[len(existing)-1] != '\n' {
+				existing = append(existing, '\n')
+			}
+			data = append(append(existing, []byte("---\n")...), data...)
+		}
+	}
+	encrypted, err := ys.Cipher.Encrypt(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the mocks: %w", err)
+	}
+	return yaml.WriteFile(ctx, ys.Logger, path, name, encrypted, false)
+}
+
+// EncryptMocks encrypts the mock file of the test set with the cipher, it returns false when the test set has
+// no mock file or the file is already encrypted.
+func (ys *MockYaml) EncryptMocks(ctx context.Context, testSetID string) (bool, error) {
+	if ys.Cipher == nil {
+		return false, errors.New("the mock encryption is not configured")
+	}
+	path, name := ys.mockFile(testSetID)
+	if !fileExists(filepath.Join(path, name+".yaml")) {
+		return false, nil
+	}
+	data, err := yaml.ReadFile(ctx, ys.Logger, path, name)
+	if err != nil {
+		return false, err
+	}
+	if yaml.IsEncrypted(data) {
+		return false, nil
+	}
+	encrypted, err := ys.Cipher.Encrypt(data)
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt the mocks: %w", err)
+	}
+	err = yaml.WriteFile(ctx, ys.Logger, path, name, encrypted, false)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DecryptMocks decrypts the mock file of the test set with the cipher, it returns false when the test set has
+// no mock file or the file is not encrypted.
+func (ys *MockYaml) DecryptMocks(ctx context.Context, testSetID string) (bool, error) {
+	if ys.Cipher == nil {
+		return false, errors.New("the mock encryption is not configured")
+	}
+	path, name := ys.mockFile(testSetID)
+	if !fileExists(filepath.Join(path, name+".yaml")) {
+		return false, nil
+	}
+	data, err := yaml.ReadFile(ctx, ys.Logger, path, name)
+	if err != nil {
+		return false, err
+	}
+	if !yaml.IsEncrypted(data) {
+		return false, nil
+	}
+	plain, err := ys.Cipher.Decrypt(data)
+	if err != nil {
+		return false, err
+	}
+	err = yaml.WriteFile(ctx, ys.Logger, path, name, plain, false)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// mockFile returns the directory and the name of the mock file of the test set.
+func (ys *MockYaml) mockFile(testSetID string) (string, string) {
+	mockFileName := "mocks"
+	if ys.MockName != "" {
+		mockFileName = ys.MockName
+	}
+	return filepath.Join(ys.MockPath, testSetID), mockFileName
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
@@ -19,6 +19,8 @@ func EncodeMock(mock *models.Mock, logger *zap.Logger) (*yaml.NetworkTrafficDoc,
 		Kind:         mock.Kind,
 		Name:         mock.Name,
 		ConnectionID: mock.ConnectionID,
+		RecordedAt:   mock.RecordedAt,
+		TTL:          mock.TTL,
 	}
 	switch mock.Kind {
 	case models.Mongo:
@@ -190,6 +192,8 @@ func decodeMocks(yamlMocks []*yaml.NetworkTrafficDoc, logger *zap.Logger) ([]*mo
 			Name:         m.Name,
 			Kind:         m.Kind,
 			ConnectionID: m.ConnectionID,
+			RecordedAt:   m.RecordedAt,
+			TTL:          m.TTL,
 		}
 		mockCheck := strings.Split(string(m.Kind), "-")
 		if len(mockCheck) > 1 {
@@ -198,6 +202,8 @@ func decodeMocks(yamlMocks []*yaml.NetworkTrafficDoc, logger *zap.Logger) ([]*mo
 		}
 		switch m.Kind {
 		case models.HTTP:
+			// the v2 schema of the mocks names the headers of the request headers
+			yaml.RenameKey(&m.Spec, []string{"req"}, "headers", "header")
 			httpSpec := models.HTTPSchema{}
 			err := m.Spec.Decode(&httpSpec)
 			if err != nil {
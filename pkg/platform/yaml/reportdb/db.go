@@ -7,7 +7,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"go.keploy.io/server/v2/pkg/models"
@@ -23,14 +26,23 @@ type TestReport struct {
 	Logger *zap.Logger
 	Path   string
 	Name   string
+	// Policy is applied when a report, not written by this run, already exists for the test run and test set
+	Policy models.ReportOverwritePolicy
+	// names stores the report file name chosen for each test run and test set by this run
+	names map[string]string
 }
 
-func New(logger *zap.Logger, reportPath string) *TestReport {
+func New(logger *zap.Logger, reportPath string, policy models.ReportOverwritePolicy) *TestReport {
+	if policy == "" {
+		policy = models.ReportOverwrite
+	}
 	return &TestReport{
 		tests:  make(map[string]map[string][]models.TestResult),
 		m:      sync.Mutex{},
 		Logger: logger,
 		Path:   reportPath,
+		Policy: policy,
+		names:  make(map[string]string),
 	}
 }
 
@@ -67,7 +79,8 @@ func (fe *TestReport) GetTestCaseResults(_ context.Context, testRunID string, te
 
 func (fe *TestReport) GetReport(ctx context.Context, testRunID string, testSetID string) (*models.TestReport, error) {
 	path := filepath.Join(fe.Path, testRunID)
-	reportName := testSetID + "-report"
+	// reports appended by the reruns are suffixed with an index, the latest one is returned
+	reportName := latestReportName(path, testSetID)
 	_, err := yaml.ValidatePath(filepath.Join(path, reportName+".yaml"))
 	if err != nil {
 		return nil, err
@@ -92,7 +105,11 @@ func (fe *TestReport) InsertReport(ctx context.Context, testRunID string, testSe
 	reportPath := filepath.Join(fe.Path, testRunID)
 
 	if testReport.Name == "" {
-		testReport.Name = testSetID + "-report"
+		name, err := fe.reportName(reportPath, testRunID, testSetID)
+		if err != nil {
+			return err
+		}
+		testReport.Name = name
 	}
 
 	data := []byte{}
@@ -109,3 +126,77 @@ func (fe *TestReport) InsertReport(ctx context.Context, testRunID string, testSe
 	}
 	return nil
 }
+
+// InsertMockCoverageReport writes the mock coverage report of the test set next to its test report.
+func (fe *TestReport) InsertMockCoverageReport(ctx context.Context, testRunID string, testSetID string, report *models.MockCoverageReport) error {
+	reportPath := filepath.Join(fe.Path, testRunID)
+	data, err := yamlLib.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("%s failed to marshal the mock coverage report to yaml. error: %s", utils.Emoji, err.Error())
+	}
+	err = yaml.WriteFile(ctx, fe.Logger, reportPath, testSetID+"-mocks", data, false)
+	if err != nil {
+		utils.LogError(fe.Logger, err, "failed to write the mock coverage report to yaml", zap.Any("session", filepath.Base(reportPath)))
+		return err
+	}
+	return nil
+}
+
+// reportName returns the name of the report file to write for the test run and test set, applying
+// the overwrite policy when a report which was not written by this run already exists.
+func (fe *TestReport) reportName(reportPath, testRunID, testSetID string) (string, error) {
+	fe.m.Lock()
+	defer fe.m.Unlock()
+
+	key := testRunID + "/" + testSetID
+	if name, ok := fe.names[key]; ok {
+		return name, nil
+	}
+
+	name := testSetID + "-report"
+	if _, err := os.Stat(filepath.Join(reportPath, name+".yaml")); err == nil {
+		switch fe.Policy {
+		case models.ReportError:
+			return "", fmt.Errorf("%s report already exists for test set: %s in test run: %s", utils.Emoji, testSetID, testRunID)
+		case models.ReportAppend:
+			latest := latestReportName(reportPath, testSetID)
+			name = fmt.Sprintf("%s-report-%d", testSetID, reportIndex(latest, testSetID)+1)
+			fe.Logger.Info("report already exists, writing a new report", zap.String("existing report", latest), zap.String("new report", name))
+		default:
+			fe.Logger.Warn("report already exists, overwriting it", zap.String("report", name), zap.String("test run", testRunID))
+		}
+	}
+	fe.names[key] = name
+	return name, nil
+}
+
+// latestReportName returns the name of the latest report of the test set in the test run directory.
+func latestReportName(reportPath, testSetID string) string {
+	latest := testSetID + "-report"
+	entries, err := os.ReadDir(reportPath)
+	if err != nil {
+		return latest
+	}
+	maxIndex := 0
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		if idx := reportIndex(name, testSetID); idx > maxIndex {
+			maxIndex = idx
+			latest = name
+		}
+	}
+	return latest
+}
+
+// reportIndex returns the index suffix of an appended report name, 0 for the first report.
+func reportIndex(name, testSetID string) int {
+	suffix, ok := strings.CutPrefix(name, testSetID+"-report-")
+	if !ok {
+		return 0
+	}
+	idx, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0
+	}
+	return idx
+}
@@ -5,12 +5,14 @@ package testdb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"text/template"
 
 	"go.keploy.io/server/v2/pkg/models"
 	"go.keploy.io/server/v2/pkg/platform/yaml"
@@ -21,7 +23,9 @@ import (
 
 type TestYaml struct {
 	TcsPath string
-	logger  *zap.Logger
+	// NameTemplate names the new test cases instead of the auto-incremented test-N names when set
+	NameTemplate *template.Template
+	logger       *zap.Logger
 }
 
 func New(logger *zap.Logger, tcsPath string) *TestYaml {
@@ -105,6 +109,40 @@ func (ts *TestYaml) GetTestCases(ctx context.Context, testSetID string) ([]*mode
 	return tcs, nil
 }
 
+// GetTestCase reads the test case of the test set from its own file, without reading the other test cases.
+func (ts *TestYaml) GetTestCase(ctx context.Context, testSetID, testCaseID string) (*models.TestCase, error) {
+	if testCaseID == "" || strings.ContainsAny(testCaseID, `/\`) {
+		return nil, fmt.Errorf("invalid test case id %q", testCaseID)
+	}
+	TestPath, err := yaml.ValidatePath(filepath.Join(ts.TcsPath, testSetID, "tests"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(TestPath, testCaseID+".yaml")); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s in the test set %s", models.ErrTestCaseNotFound, testCaseID, testSetID)
+		}
+		return nil, err
+	}
+	data, err := yaml.ReadFile(ctx, ts.logger, TestPath, testCaseID)
+	if err != nil {
+		utils.LogError(ts.logger, err, "failed to read the testcase from yaml", zap.String("testcase", testCaseID))
+		return nil, err
+	}
+	var testCase *yaml.NetworkTrafficDoc
+	err = yamlLib.Unmarshal(data, &testCase)
+	if err != nil {
+		utils.LogError(ts.logger, err, "failed to unmarshall YAML data", zap.String("testcase", testCaseID))
+		return nil, err
+	}
+	tc, err := Decode(testCase, ts.logger)
+	if err != nil {
+		utils.LogError(ts.logger, err, "failed to decode the testcase", zap.String("testcase", testCaseID))
+		return nil, err
+	}
+	return tc, nil
+}
+
 func (ts *TestYaml) UpdateTestCase(ctx context.Context, tc *models.TestCase, testSetID string) error {
 
 	tcsInfo, err := ts.upsert(ctx, testSetID, tc)
@@ -120,14 +158,21 @@ func (ts *TestYaml) upsert(ctx context.Context, testSetID string, tc *models.Tes
 	tcsPath := filepath.Join(ts.TcsPath, testSetID, "tests")
 	var tcsName string
 	if tc.Name == "" {
-		lastIndx, err := yaml.FindLastIndex(tcsPath, ts.logger)
-		if err != nil {
-			return tcsInfo{name: "", path: tcsPath}, err
+		if ts.NameTemplate != nil {
+			tcsName = ts.templateName(tcsPath, tc)
+		}
+		if tcsName == "" {
+			lastIndx, err := yaml.FindLastIndex(tcsPath, ts.logger)
+			if err != nil {
+				return tcsInfo{name: "", path: tcsPath}, err
+			}
+			tcsName = fmt.Sprintf("test-%v", lastIndx)
 		}
-		tcsName = fmt.Sprintf("test-%v", lastIndx)
 	} else {
 		tcsName = tc.Name
 	}
+	// the name picked for the unnamed test case is set on it, so that the caller knows the file it is written to
+	tc.Name = tcsName
 	yamlTc, err := EncodeTestcase(*tc, ts.logger)
 	if err != nil {
 		return tcsInfo{name: tcsName, path: tcsPath}, err
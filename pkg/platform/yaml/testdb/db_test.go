@@ -0,0 +1,123 @@
+//go:build linux
+
+package testdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// newTestSet records the test cases test-1 to test-n in the test set test-set-0 of a temporary keploy directory.
+func newTestSet(tb testing.TB, n int) *TestYaml {
+	tb.Helper()
+	ts := New(zap.NewNop(), tb.TempDir())
+	recorded := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		tc := &models.TestCase{
+			Version: models.GetVersion(),
+			Kind:    models.HTTP,
+			HTTPReq: models.HTTPReq{
+				Method:     models.Method("GET"),
+				URL:        fmt.Sprintf("http://localhost:8080/users/%d", i),
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     map[string]string{"Accept": "application/json"},
+				Timestamp:  recorded.Add(time.Duration(i) * time.Second),
+			},
+			HTTPResp: models.HTTPResp{
+				StatusCode: 200,
+				Header:     map[string]string{"Content-Type": "application/json"},
+				Body:       fmt.Sprintf(`{"id":%d,"name":"user %d","roles":["admin","dev"]}`, i, i),
+				Timestamp:  recorded.Add(time.Duration(i)*time.Second + time.Millisecond),
+			},
+		}
+		if err := ts.InsertTestCase(context.Background(), tc, "test-set-0"); err != nil {
+			tb.Fatalf("failed to insert the test case: %v", err)
+		}
+	}
+	return ts
+}
+
+func TestGetTestCase(t *testing.T) {
+	ts := newTestSet(t, 3)
+
+	tests := []struct {
+		name         string
+		testSetID    string
+		testCaseID   string
+		wantURL      string
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{name: "first test case", testSetID: "test-set-0", testCaseID: "test-1", wantURL: "http://localhost:8080/users/0"},
+		{name: "last test case", testSetID: "test-set-0", testCaseID: "test-3", wantURL: "http://localhost:8080/users/2"},
+		{name: "missing test case", testSetID: "test-set-0", testCaseID: "test-4", wantNotFound: true, wantErr: true},
+		{name: "missing test set", testSetID: "test-set-1", testCaseID: "test-1", wantNotFound: true, wantErr: true},
+		{name: "empty test case id", testSetID: "test-set-0", wantErr: true},
+		{name: "test case id outside of the test set", testSetID: "test-set-0", testCaseID: "../../test-set-1/tests/test-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := ts.GetTestCase(context.Background(), tt.testSetID, tt.testCaseID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetTestCase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, models.ErrTestCaseNotFound) != tt.wantNotFound {
+				t.Fatalf("GetTestCase() error = %v, want not found %v", err, tt.wantNotFound)
+			}
+			if err == nil && (tc.Name != tt.testCaseID || tc.HTTPReq.URL != tt.wantURL) {
+				t.Fatalf("GetTestCase() = %s %s, want %s %s", tc.Name, tc.HTTPReq.URL, tt.testCaseID, tt.wantURL)
+			}
+		})
+	}
+}
+
+// BenchmarkGetTestCase reads the last test case of the test sets of various sizes from its own file.
+func BenchmarkGetTestCase(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d test cases", n), func(b *testing.B) {
+			ts := newTestSet(b, n)
+			testCaseID := fmt.Sprintf("test-%d", n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := ts.GetTestCase(context.Background(), "test-set-0", testCaseID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGetTestCasesAndFilter is the baseline of BenchmarkGetTestCase, the lookup reading every test case of the
+// test set and keeping the selected one.
+func BenchmarkGetTestCasesAndFilter(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d test cases", n), func(b *testing.B) {
+			ts := newTestSet(b, n)
+			testCaseID := fmt.Sprintf("test-%d", n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tcs, err := ts.GetTestCases(context.Background(), "test-set-0")
+				if err != nil {
+					b.Fatal(err)
+				}
+				var found *models.TestCase
+				for _, tc := range tcs {
+					if tc.Name == testCaseID {
+						found = tc
+						break
+					}
+				}
+				if found == nil {
+					b.Fatalf("the test case %s is not found", testCaseID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package testdb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// templateName returns the name of the new test case executed from the name template, suffixed with _N when a
+// test case of the test set already has it. The empty name falls back to the auto-incremented test-N name.
+func (ts *TestYaml) templateName(tcsPath string, tc *models.TestCase) string {
+	// the new test case is the last one of the test set
+	name, err := models.TestCaseName(ts.NameTemplate, tc, countTestCases(tcsPath)+1, func(fileName string) bool {
+		_, err := os.Stat(filepath.Join(tcsPath, fileName+".yaml"))
+		return err == nil
+	})
+	if err != nil {
+		ts.logger.Warn("failed to name the test case with the name template, naming it test-N", zap.Error(err))
+		return ""
+	}
+	return models.TestCaseFileName(name)
+}
+
+// countTestCases returns the number of the test case files of the test set.
+func countTestCases(tcsPath string) int {
+	entries, err := os.ReadDir(tcsPath)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".yaml" && !strings.Contains(entry.Name(), "mocks") {
+			count++
+		}
+	}
+	return count
+}
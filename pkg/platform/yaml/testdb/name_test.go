@@ -0,0 +1,76 @@
+//go:build linux
+
+package testdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestInsertTestCaseNamesTheFilesWithTheNameTemplate(t *testing.T) {
+	testCase := func(method models.Method, path string, status int) *models.TestCase {
+		return &models.TestCase{
+			Version:  models.GetVersion(),
+			Kind:     models.HTTP,
+			HTTPReq:  models.HTTPReq{Method: method, URL: "http://localhost:8080" + path, ProtoMajor: 1, ProtoMinor: 1},
+			HTTPResp: models.HTTPResp{StatusCode: status, ProtoMajor: 1, ProtoMinor: 1},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		template  string
+		testCases []*models.TestCase
+		want      []string
+	}{
+		{
+			name:      "url path",
+			template:  "test-{{.Method}}-{{.URL}}-{{.StatusCode}}",
+			testCases: []*models.TestCase{testCase("GET", "/api/users", 200)},
+			want:      []string{"test-GET-_api_users-200"},
+		},
+		{
+			name:     "duplicate names",
+			template: "test-{{.Method}}-{{.URL}}-{{.StatusCode}}",
+			testCases: []*models.TestCase{
+				testCase("GET", "/api/users", 200),
+				testCase("POST", "/api/users", 201),
+				testCase("GET", "/api/users", 200),
+				testCase("GET", "/api/users", 200),
+			},
+			want: []string{"test-GET-_api_users-200", "test-POST-_api_users-201", "test-GET-_api_users-200_1", "test-GET-_api_users-200_2"},
+		},
+		{
+			name:      "index of the test case in the test set",
+			template:  "{{.Method}}-{{.Index}}",
+			testCases: []*models.TestCase{testCase("GET", "/", 200), testCase("GET", "/", 200)},
+			want:      []string{"GET-1", "GET-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := models.ParseNameTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseNameTemplate() error = %v", err)
+			}
+			ts := New(zap.NewNop(), t.TempDir())
+			ts.NameTemplate = tmpl
+			for i, tc := range tt.testCases {
+				if err := ts.InsertTestCase(context.Background(), tc, "test-set-0"); err != nil {
+					t.Fatalf("InsertTestCase() error = %v", err)
+				}
+				if tc.Name != tt.want[i] {
+					t.Fatalf("the name of the test case %d = %q, want %q", i, tc.Name, tt.want[i])
+				}
+				if _, err := os.Stat(filepath.Join(ts.TcsPath, "test-set-0", "tests", tc.Name+".yaml")); err != nil {
+					t.Fatalf("the file of the test case %d is missing: %v", i, err)
+				}
+			}
+		})
+	}
+}
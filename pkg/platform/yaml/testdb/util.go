@@ -63,11 +63,38 @@ func EncodeTestcase(tc models.TestCase, logger *zap.Logger) (*yaml.NetworkTraffi
 			Assertions: map[string]interface{}{
 				"noise": noise,
 			},
+			DBAssertions: tc.DBAssertions,
+			PreScript:    tc.PreTestCaseScript,
+			PostScript:   tc.PostTestCaseScript,
+			DependsOn:    tc.DependsOn,
+			APITimeout:   tc.APITimeout,
 		})
 		if err != nil {
 			utils.LogError(logger, err, "failed to encode testcase into a yaml doc")
 			return nil, err
 		}
+	case models.GRPC_EXPORT:
+		err := doc.Spec.Encode(models.GrpcSpec{
+			GrpcReq:  tc.GrpcReq,
+			GrpcResp: tc.GrpcResp,
+		})
+		if err != nil {
+			utils.LogError(logger, err, "failed to encode the gRPC testcase into a yaml doc")
+			return nil, err
+		}
+	case models.WS:
+		err := doc.Spec.Encode(models.WSSchema{
+			Request:  tc.WSReq,
+			Response: tc.WSResp,
+			Created:  tc.Created,
+			Assertions: map[string]interface{}{
+				"noise": noise,
+			},
+		})
+		if err != nil {
+			utils.LogError(logger, err, "failed to encode the websocket testcase into a yaml doc")
+			return nil, err
+		}
 	default:
 		utils.LogError(logger, nil, "failed to marshal the testcase into yaml due to invalid kind of testcase")
 		return nil, errors.New("type of testcases is invalid")
@@ -244,21 +271,12 @@ func Decode(yamlTestcase *yaml.NetworkTrafficDoc, logger *zap.Logger) (*models.T
 		tc.Created = httpSpec.Created
 		tc.HTTPReq = httpSpec.Request
 		tc.HTTPResp = httpSpec.Response
-		tc.Noise = map[string][]string{}
-		switch reflect.ValueOf(httpSpec.Assertions["noise"]).Kind() {
-		case reflect.Map:
-			for k, v := range httpSpec.Assertions["noise"].(map[string]interface{}) {
-				l := strings.ToLower(k)
-				tc.Noise[l] = []string{}
-				for _, val := range v.([]interface{}) {
-					tc.Noise[l] = append(tc.Noise[l], val.(string))
-				}
-			}
-		case reflect.Slice:
-			for _, v := range httpSpec.Assertions["noise"].([]interface{}) {
-				tc.Noise[v.(string)] = []string{}
-			}
-		}
+		tc.DBAssertions = httpSpec.DBAssertions
+		tc.PreTestCaseScript = httpSpec.PreScript
+		tc.PostTestCaseScript = httpSpec.PostScript
+		tc.DependsOn = httpSpec.DependsOn
+		tc.APITimeout = httpSpec.APITimeout
+		tc.Noise = decodeNoise(httpSpec.Assertions["noise"])
 	// unmarshal its mocks from yaml docs to go struct
 	case models.GRPC_EXPORT:
 		grpcSpec := models.GrpcSpec{}
@@ -269,9 +287,43 @@ func Decode(yamlTestcase *yaml.NetworkTrafficDoc, logger *zap.Logger) (*models.T
 		}
 		tc.GrpcReq = grpcSpec.GrpcReq
 		tc.GrpcResp = grpcSpec.GrpcResp
+	case models.WS:
+		wsSpec := models.WSSchema{}
+		err := yamlTestcase.Spec.Decode(&wsSpec)
+		if err != nil {
+			utils.LogError(logger, err, "failed to unmarshal a yaml doc into the websocket testcase")
+			return nil, err
+		}
+		tc.Created = wsSpec.Created
+		tc.WSReq = wsSpec.Request
+		tc.WSResp = wsSpec.Response
+		// the mocks are filtered by the time window of the http request and response
+		tc.HTTPReq.Timestamp = wsSpec.Request.Timestamp
+		tc.HTTPResp.Timestamp = wsSpec.Response.Timestamp
+		tc.Noise = decodeNoise(wsSpec.Assertions["noise"])
 	default:
 		utils.LogError(logger, nil, "failed to unmarshal yaml doc of unknown type", zap.Any("type of yaml doc", tc.Kind))
 		return nil, errors.New("yaml doc of unknown type")
 	}
 	return &tc, nil
 }
+
+// decodeNoise converts the noise assertion of the yaml doc, a map of the fields to their regexes or a list of the fields.
+func decodeNoise(assertion interface{}) map[string][]string {
+	noise := map[string][]string{}
+	switch reflect.ValueOf(assertion).Kind() {
+	case reflect.Map:
+		for k, v := range assertion.(map[string]interface{}) {
+			l := strings.ToLower(k)
+			noise[l] = []string{}
+			for _, val := range v.([]interface{}) {
+				noise[l] = append(noise[l], val.(string))
+			}
+		}
+	case reflect.Slice:
+		for _, v := range assertion.([]interface{}) {
+			noise[v.(string)] = []string{}
+		}
+	}
+	return noise
+}
@@ -7,7 +7,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
+	"go.keploy.io/server/v2/config"
 	"go.keploy.io/server/v2/pkg/models"
 	"go.keploy.io/server/v2/utils"
 	"go.uber.org/zap"
@@ -22,6 +24,8 @@ type NetworkTrafficDoc struct {
 	Spec         yamlLib.Node   `json:"spec" yaml:"spec"`
 	Curl         string         `json:"curl" yaml:"curl,omitempty"`
 	ConnectionID string         `json:"connectionId" yaml:"connectionId,omitempty"`
+	RecordedAt   time.Time      `json:"recordedAt" yaml:"recordedAt,omitempty"`
+	TTL          time.Duration  `json:"ttl" yaml:"ttl,omitempty"`
 }
 
 // ctxReader wraps an io.Reader with a context for cancellation support
@@ -172,7 +176,7 @@ func ReadSessionIndices(_ context.Context, path string, Logger *zap.Logger) ([]s
 	}
 
 	for _, v := range files {
-		if v.Name() != "reports" && v.Name() != "testReports" && v.IsDir() {
+		if v.Name() != "reports" && v.Name() != "testReports" && v.Name() != config.NamespacesDir && v.IsDir() {
 			indices = append(indices, v.Name())
 		}
 	}
@@ -197,3 +201,40 @@ func DeleteDir(_ context.Context, logger *zap.Logger, path string) error {
 	}
 	return nil
 }
+
+// RenameKey renames the key of the mapping at the path of the keys in the node, the key is not renamed when the
+// mapping already has the new key. It reports whether the key was renamed.
+func RenameKey(node *yamlLib.Node, path []string, from, to string) bool {
+	if node.Kind == yamlLib.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, key := range path {
+		node = mappingValue(node, key)
+		if node == nil {
+			return false
+		}
+	}
+	if node.Kind != yamlLib.MappingNode || mappingValue(node, to) != nil {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == from {
+			node.Content[i].Value = to
+			return true
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value of the key in the mapping node, nil if the node is not a mapping or has no such key.
+func mappingValue(node *yamlLib.Node, key string) *yamlLib.Node {
+	if node.Kind != yamlLib.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package yaml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.keploy.io/server/v2/config"
+	"go.uber.org/zap"
+)
+
+func TestReadSessionIndicesSkipsTheReservedDirectories(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		dirs      []string
+		want      []string
+	}{
+		{
+			name: "namespaces are not test sets",
+			dirs: []string{"test-set-0", "test-set-1", "reports", config.NamespacesDir + "/team-a/test-set-0"},
+			want: []string{"test-set-0", "test-set-1"},
+		},
+		{
+			name:      "test sets of a namespace",
+			namespace: "team-a",
+			dirs:      []string{"test-set-0", config.NamespacesDir + "/team-a/test-set-2", config.NamespacesDir + "/team-a/reports"},
+			want:      []string{"test-set-2"},
+		},
+		{
+			name:      "namespace named like a test set",
+			namespace: "test-set-0",
+			dirs:      []string{"test-set-0/tests", config.NamespacesDir + "/test-set-0/test-set-5"},
+			want:      []string{"test-set-5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir()
+			for _, dir := range tt.dirs {
+				if err := os.MkdirAll(filepath.Join(path, dir), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			storagePath := config.StoragePath(&config.Config{Path: path, Namespace: tt.namespace})
+			got, err := ReadSessionIndices(context.Background(), storagePath, zap.NewNop())
+			if err != nil {
+				t.Fatalf("ReadSessionIndices() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ReadSessionIndices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,7 +10,7 @@ type Instrumentation interface {
 	//Setup prepares the environment for the recording
 	Setup(ctx context.Context, cmd string, opts models.SetupOptions) (uint64, error)
 	//Hook will load hooks and start the proxy server.
-	Hook(ctx context.Context, id uint64, opts models.HookOptions) error
+	Hook(ctx context.Context, id uint64, opts models.HookOptions) (*models.HookMetadata, error)
 	GetIncoming(ctx context.Context, id uint64, opts models.IncomingOptions) (<-chan *models.TestCase, error)
 	GetOutgoing(ctx context.Context, id uint64, opts models.OutgoingOptions) (<-chan *models.Mock, error)
 	// Run is blocking call and will execute until error
@@ -0,0 +1,233 @@
+//go:build linux
+
+package replay
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// NginxAccessLog is the combined log format of nginx, optionally followed by the quoted $request_body
+	NginxAccessLog = "nginx"
+	// ApacheAccessLog is the combined log format of apache, the common log format with the referer and the user agent
+	ApacheAccessLog = "apache"
+	// CommonAccessLog is the common log format
+	CommonAccessLog = "clf"
+)
+
+// accessLogTimeLayout is the layout of the times of the access logs, e.g. 10/Oct/2000:13:55:36 -0700
+const accessLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+var (
+	// host ident authuser [time] "request" status bytes
+	commonLogRe = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\S+)`)
+	// the common log format followed by "referer" "user agent" and, for nginx, optionally "request body"
+	combinedLogRe = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\S+)(?: "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)"(?: "((?:[^"\\]|\\.)*)")?)?`)
+	// the escaped bytes of nginx, e.g. \x22
+	nginxEscapeRe = regexp.MustCompile(`\\x[0-9A-Fa-f]{2}`)
+)
+
+// accessLogEntry is a request of an access log.
+type accessLogEntry struct {
+	time      time.Time
+	method    string
+	target    string
+	proto     string
+	status    int
+	referer   string
+	userAgent string
+	body      string
+}
+
+// GenerateFromAccessLog generates a test case in the test set for each request of the access log. The test cases have
+// the method, the url, the status and the body of the request when the log has it, but no recorded response, which is
+// recorded on their first replay with test.recordMissingTestCases. The access logs have no host, so the urls are on
+// the test.basePath origin, or http://localhost.
+func (r *Replayer) GenerateFromAccessLog(ctx context.Context, logPath string, format string, testSetID string) (int, error) {
+	var parse func(line string) (*accessLogEntry, bool)
+	switch format {
+	case NginxAccessLog:
+		parse = func(line string) (*accessLogEntry, bool) { return parseAccessLogLine(combinedLogRe, line, true) }
+	case ApacheAccessLog:
+		parse = func(line string) (*accessLogEntry, bool) { return parseAccessLogLine(combinedLogRe, line, false) }
+	case CommonAccessLog:
+		parse = func(line string) (*accessLogEntry, bool) { return parseAccessLogLine(commonLogRe, line, false) }
+	default:
+		return 0, fmt.Errorf("invalid access log format %q, it should be nginx, apache or clf", format)
+	}
+
+	origin := "http://localhost"
+	if r.config.Test.BasePath != "" {
+		base, err := url.Parse(r.config.Test.BasePath)
+		if err != nil || base.Scheme == "" || base.Host == "" {
+			return 0, fmt.Errorf("invalid base path %q, it should be an absolute url", r.config.Test.BasePath)
+		}
+		origin = base.Scheme + "://" + base.Host
+	} else {
+		r.logger.Warn("the access logs have no host, the test cases are generated on http://localhost, set the base path to replay them on the app")
+	}
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open the access log: %w", err)
+	}
+	defer file.Close()
+
+	generated, skipped := 0, 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if err := ctx.Err(); err != nil {
+			return generated, err
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		entry, ok := parse(line)
+		if !ok {
+			r.logger.Debug("skipping the access log line which is not a request", zap.Int("line", lineNumber), zap.String("format", format))
+			skipped++
+			continue
+		}
+		tc := accessLogTestCase(origin, entry)
+		// the test db names the test cases without a name after the last one of the test set
+		if err := r.testDB.InsertTestCase(ctx, tc, testSetID); err != nil {
+			return generated, fmt.Errorf("failed to insert the test case of the line %d: %w", lineNumber, err)
+		}
+		generated++
+	}
+	if err := scanner.Err(); err != nil {
+		return generated, fmt.Errorf("failed to read the access log: %w", err)
+	}
+	if skipped > 0 {
+		r.logger.Warn("skipped the access log lines which could not be parsed", zap.Int("skipped lines", skipped), zap.String("format", format))
+	}
+	return generated, nil
+}
+
+// parseAccessLogLine parses the line of an access log, the request body is only read from the nginx logs.
+func parseAccessLogLine(re *regexp.Regexp, line string, withBody bool) (*accessLogEntry, bool) {
+	match := re.FindStringSubmatch(line)
+	if match == nil {
+		return nil, false
+	}
+	parts := strings.Fields(unescapeAccessLogField(match[5]))
+	if len(parts) < 2 {
+		// e.g. "-" for the connections closed without a request
+		return nil, false
+	}
+	t, err := time.Parse(accessLogTimeLayout, match[4])
+	if err != nil {
+		return nil, false
+	}
+	status, err := strconv.Atoi(match[6])
+	if err != nil {
+		return nil, false
+	}
+	entry := &accessLogEntry{
+		time:   t,
+		method: strings.ToUpper(parts[0]),
+		target: parts[1],
+		status: status,
+	}
+	if len(parts) > 2 {
+		entry.proto = parts[2]
+	}
+	if len(match) > 9 {
+		entry.referer = accessLogValue(match[8])
+		entry.userAgent = accessLogValue(match[9])
+	}
+	if withBody && len(match) > 10 {
+		entry.body = accessLogValue(match[10])
+	}
+	return entry, true
+}
+
+// accessLogValue returns the unescaped value of the field of the access log, empty for the missing values logged as -.
+func accessLogValue(field string) string {
+	if field == "-" {
+		return ""
+	}
+	return unescapeAccessLogField(field)
+}
+
+// unescapeAccessLogField unescapes the \" and \\ escapes of apache and the \xHH escapes of nginx.
+func unescapeAccessLogField(field string) string {
+	field = nginxEscapeRe.ReplaceAllStringFunc(field, func(escape string) string {
+		b, err := strconv.ParseUint(escape[2:], 16, 8)
+		if err != nil {
+			return escape
+		}
+		return string([]byte{byte(b)})
+	})
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(field)
+}
+
+func accessLogTestCase(origin string, entry *accessLogEntry) *models.TestCase {
+	target := entry.target
+	if !strings.HasPrefix(target, "/") {
+		// an absolute-form target, e.g. of a forward proxy
+		if u, err := url.Parse(target); err == nil && u.Host != "" {
+			target = u.RequestURI()
+		}
+	}
+	header := map[string]string{}
+	if entry.userAgent != "" {
+		header["User-Agent"] = entry.userAgent
+	}
+	if entry.referer != "" {
+		header["Referer"] = entry.referer
+	}
+	protoMajor, protoMinor := 1, 1
+	if major, minor, ok := http.ParseHTTPVersion(entry.proto); ok {
+		protoMajor, protoMinor = major, minor
+	}
+	reqURL := origin + target
+	urlParams := map[string]string{}
+	if u, err := url.Parse(reqURL); err == nil {
+		for key, values := range u.Query() {
+			urlParams[key] = strings.Join(values, ",")
+		}
+	}
+
+	return &models.TestCase{
+		Version: models.GetVersion(),
+		Kind:    models.HTTP,
+		Created: entry.time.Unix(),
+		HTTPReq: models.HTTPReq{
+			Method:     models.Method(entry.method),
+			ProtoMajor: protoMajor,
+			ProtoMinor: protoMinor,
+			URL:        reqURL,
+			URLParams:  urlParams,
+			Header:     header,
+			Body:       entry.body,
+			Timestamp:  entry.time,
+		},
+		// the response is recorded on the first replay, its zero timestamp marks it as missing
+		HTTPResp: models.HTTPResp{
+			StatusCode: entry.status,
+			Header:     map[string]string{},
+		},
+		Noise: map[string][]string{},
+	}
+}
+
+// missingResponse reports whether the response of the test case was never recorded, the test cases created from the
+// API documentation have no status code and the ones generated from the access logs have no response timestamp.
+func missingResponse(tc *models.TestCase) bool {
+	return tc.HTTPResp.StatusCode == 0 || tc.HTTPResp.Timestamp.IsZero()
+}
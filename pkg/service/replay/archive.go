@@ -0,0 +1,168 @@
+//go:build linux
+
+package replay
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.keploy.io/server/v2/config"
+	"go.uber.org/zap"
+)
+
+// ExportTestSet writes the test set as a zip bundle to the destination path, with the yaml files of its test cases,
+// its mocks and its config under a directory named after the test set, so that it can be shared without the
+// access to the keploy directory.
+func (r *Replayer) ExportTestSet(ctx context.Context, testSetID, destPath string) error {
+	testSetDir := filepath.Join(config.StoragePath(r.config), testSetID)
+	if _, err := os.Stat(testSetDir); err != nil {
+		return fmt.Errorf("failed to find the test set %s: %w", testSetID, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o777); err != nil {
+		return fmt.Errorf("failed to create the directory of the bundle: %w", err)
+	}
+	f, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create the bundle: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			r.logger.Error("failed to close the bundle", zap.String("path", destPath), zap.Error(err))
+		}
+	}()
+
+	zw := zip.NewWriter(f)
+	var files int
+	err = filepath.WalkDir(testSetDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		ext := filepath.Ext(filePath)
+		if d.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+		rel, err := filepath.Rel(testSetDir, filePath)
+		if err != nil {
+			return err
+		}
+		w, err := zw.Create(path.Join(testSetID, filepath.ToSlash(rel)))
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		if _, err := io.Copy(w, src); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add the files of the test set to the bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write the bundle: %w", err)
+	}
+	r.logger.Info("exported the test set", zap.String("test-set", testSetID), zap.String("path", destPath), zap.Int("files", files))
+	return nil
+}
+
+// ImportTestSet extracts the zip bundle written by ExportTestSet into the keploy directory and returns the id of the
+// imported test set. The test set is imported under a suffixed id (e.g. test-set-1-1) when its id is already taken.
+// The test cases are read back and written again to validate them.
+func (r *Replayer) ImportTestSet(ctx context.Context, srcPath string) (string, error) {
+	zr, err := zip.OpenReader(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open the bundle: %w", err)
+	}
+	defer zr.Close()
+
+	var testSetID string
+	for _, file := range zr.File {
+		name := path.Clean(file.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return "", fmt.Errorf("invalid file path in the bundle: %s", file.Name)
+		}
+		dir, _, ok := strings.Cut(name, "/")
+		if !ok || (testSetID != "" && dir != testSetID) {
+			return "", fmt.Errorf("invalid bundle, the files should be under a single test set directory: %s", file.Name)
+		}
+		testSetID = dir
+	}
+	if testSetID == "" {
+		return "", errors.New("the bundle is empty")
+	}
+
+	storagePath := config.StoragePath(r.config)
+	importedID := testSetID
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(storagePath, importedID)); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		importedID = fmt.Sprintf("%s-%d", testSetID, i)
+	}
+
+	dest := filepath.Join(storagePath, importedID)
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		rel := strings.TrimPrefix(path.Clean(file.Name), testSetID+"/")
+		if err := extractZipFile(file, filepath.Join(dest, filepath.FromSlash(rel))); err != nil {
+			return "", fmt.Errorf("failed to extract %s from the bundle: %w", file.Name, err)
+		}
+	}
+
+	testCases, err := r.testDB.GetTestCases(ctx, importedID)
+	if err != nil {
+		if rmErr := os.RemoveAll(dest); rmErr != nil {
+			r.logger.Error("failed to remove the invalid imported test set", zap.String("test-set", importedID), zap.Error(rmErr))
+		}
+		return "", fmt.Errorf("failed to read the test cases of the imported test set: %w", err)
+	}
+	for _, tc := range testCases {
+		if err := r.testDB.UpdateTestCase(ctx, tc, importedID); err != nil {
+			return importedID, fmt.Errorf("failed to write the test case %s: %w", tc.Name, err)
+		}
+	}
+	if importedID != testSetID {
+		r.logger.Info("the test set already exists, imported it under another id", zap.String("test-set", testSetID), zap.String("imported as", importedID))
+	}
+	r.logger.Info("imported the test set", zap.String("test-set", importedID), zap.Int("test cases", len(testCases)))
+	return importedID, nil
+}
+
+func extractZipFile(file *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o777); err != nil {
+		return err
+	}
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o777)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	closeErr := dst.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,166 @@
+//go:build linux
+
+package replay
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/pkg/platform/yaml/testdb"
+	"go.uber.org/zap"
+)
+
+// newArchiveReplayer returns a replayer storing its test sets in the path.
+func newArchiveReplayer(path string) *Replayer {
+	return &Replayer{
+		logger: zap.NewNop(),
+		config: &config.Config{Path: path},
+		testDB: testdb.New(zap.NewNop(), path),
+	}
+}
+
+func TestExportAndImportTestSet(t *testing.T) {
+	ctx := context.Background()
+	recorded := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// sameStorage imports the bundle into the keploy directory it was exported from
+		sameStorage bool
+		imports     int
+		wantIDs     []string
+	}{
+		{name: "into another keploy directory", imports: 1, wantIDs: []string{"test-set-0"}},
+		{name: "name collision", sameStorage: true, imports: 1, wantIDs: []string{"test-set-0-1"}},
+		{name: "repeated name collisions", sameStorage: true, imports: 2, wantIDs: []string{"test-set-0-1", "test-set-0-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newArchiveReplayer(filepath.Join(t.TempDir(), "keploy"))
+			for i, path := range []string{"/users", "/users/1"} {
+				tc := &models.TestCase{
+					Version: models.GetVersion(),
+					Kind:    models.HTTP,
+					HTTPReq: models.HTTPReq{
+						Method:     "GET",
+						URL:        "http://localhost:8080" + path,
+						ProtoMajor: 1,
+						ProtoMinor: 1,
+						Header:     map[string]string{"Accept": "application/json"},
+						Timestamp:  recorded.Add(time.Duration(i) * time.Second),
+					},
+					HTTPResp: models.HTTPResp{
+						StatusCode: 200,
+						Header:     map[string]string{"Content-Type": "application/json"},
+						Body:       `{"id":1}`,
+						Timestamp:  recorded.Add(time.Duration(i)*time.Second + time.Millisecond),
+					},
+					Noise: map[string][]string{"header.date": {}},
+				}
+				if err := src.testDB.InsertTestCase(ctx, tc, "test-set-0"); err != nil {
+					t.Fatalf("failed to insert the test case: %v", err)
+				}
+			}
+			testSetDir := filepath.Join(src.config.Path, "test-set-0")
+			files := map[string]string{
+				"mocks.yaml":  "version: api.keploy.io/v1beta1\nkind: Http\nname: mock-0\n",
+				"config.yaml": "preScript: echo pre\n",
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(testSetDir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := src.testDB.GetTestCases(ctx, "test-set-0")
+			if err != nil || len(want) != 2 {
+				t.Fatalf("failed to read the recorded test cases: %v, %d test cases", err, len(want))
+			}
+
+			bundle := filepath.Join(t.TempDir(), "bundles", "test-set-0.zip")
+			if err := src.ExportTestSet(ctx, "test-set-0", bundle); err != nil {
+				t.Fatalf("ExportTestSet() error = %v", err)
+			}
+
+			dst := src
+			if !tt.sameStorage {
+				dst = newArchiveReplayer(filepath.Join(t.TempDir(), "keploy"))
+			}
+			for i := 0; i < tt.imports; i++ {
+				importedID, err := dst.ImportTestSet(ctx, bundle)
+				if err != nil {
+					t.Fatalf("ImportTestSet() error = %v", err)
+				}
+				if importedID != tt.wantIDs[i] {
+					t.Fatalf("ImportTestSet() = %q, want %q", importedID, tt.wantIDs[i])
+				}
+
+				got, err := dst.testDB.GetTestCases(ctx, importedID)
+				if err != nil {
+					t.Fatalf("failed to read the imported test cases: %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("the imported test cases = %+v, want %+v", got, want)
+				}
+				for name, content := range files {
+					data, err := os.ReadFile(filepath.Join(dst.config.Path, importedID, name))
+					if err != nil || string(data) != content {
+						t.Fatalf("the imported %s = %q, %v, want %q", name, data, err, content)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestImportTestSetRejectsTheInvalidBundles(t *testing.T) {
+	tests := []struct {
+		name string
+		// entries are the files of the zip bundle, no bundle is written when nil
+		entries []string
+	}{
+		{name: "missing bundle"},
+		{name: "empty bundle", entries: []string{}},
+		{name: "entry outside of the keploy directory", entries: []string{"test-set-0/tests/test-1.yaml", "../../etc/passwd"}},
+		{name: "absolute entry", entries: []string{"/test-set-0/tests/test-1.yaml"}},
+		{name: "entry outside of a test set directory", entries: []string{"mocks.yaml"}},
+		{name: "several test sets", entries: []string{"test-set-0/mocks.yaml", "test-set-1/mocks.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			bundle := filepath.Join(dir, "bundle.zip")
+			if tt.entries != nil {
+				f, err := os.Create(bundle)
+				if err != nil {
+					t.Fatal(err)
+				}
+				zw := zip.NewWriter(f)
+				for _, entry := range tt.entries {
+					if _, err := zw.Create(entry); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := zw.Close(); err != nil {
+					t.Fatal(err)
+				}
+				if err := f.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := newArchiveReplayer(filepath.Join(dir, "keploy"))
+			if _, err := r.ImportTestSet(context.Background(), bundle); err == nil {
+				t.Fatalf("ImportTestSet() imported the invalid bundle")
+			}
+			if _, err := os.Stat(filepath.Join(dir, "etc")); err == nil {
+				t.Fatalf("ImportTestSet() extracted an entry outside of the keploy directory")
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// autoDenoiseEditor is the editor of the noise params generated by the auto denoise
+const autoDenoiseEditor = "keploy-auto-denoise"
+
+// AutoDenoise replays the test set sampleRuns times and generates the noise params of the fields of the responses
+// which differ from the recorded ones in more than the Test.DenoiseThreshold fraction of the runs, e.g. the
+// timestamps and the generated ids. The params are returned without being applied, DenoiseTestCases applies them.
+func (r *Replayer) AutoDenoise(ctx context.Context, testSetID string, sampleRuns int) ([]*models.NoiseParams, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	ctx = context.WithValue(ctx, models.ErrGroupKey, g)
+
+	inst, err := r.Instrument(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instrument: %w", err)
+	}
+	defer func() {
+		if inst.HookCancel != nil {
+			inst.HookCancel()
+		}
+		if err := g.Wait(); err != nil {
+			utils.LogError(r.logger, err, "failed to stop the auto denoise")
+		}
+	}()
+	return r.autoDenoise(ctx, testSetID, sampleRuns, inst.AppID)
+}
+
+// autoDenoiseTestSets generates the noise params of the selected test sets with the instrumented app and applies them.
+func (r *Replayer) autoDenoiseTestSets(ctx context.Context, testSetIDs []string, appID uint64) error {
+	for _, testSetID := range testSetIDs {
+		if _, ok := r.config.Test.SelectedTests[testSetID]; !ok && len(r.config.Test.SelectedTests) != 0 {
+			continue
+		}
+		noiseParams, err := r.autoDenoise(ctx, testSetID, r.config.Test.DenoiseRuns, appID)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			utils.LogError(r.logger, err, "failed to auto denoise the test set", zap.String("test-set", testSetID))
+			continue
+		}
+		if len(noiseParams) == 0 {
+			r.logger.Info("no noisy fields found in the test set", zap.String("test-set", testSetID), zap.Int("runs", r.config.Test.DenoiseRuns))
+			continue
+		}
+		noiseParams, err = r.DenoiseTestCases(ctx, testSetID, noiseParams)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to apply the generated noise", zap.String("test-set", testSetID))
+			continue
+		}
+		for _, noiseParam := range noiseParams {
+			r.logger.Info("added the noisy fields to the test case", zap.String("test-set", testSetID), zap.String("testcase", noiseParam.TestCaseID), zap.Strings("fields", sortedNoiseFields(noiseParam.Assertion)))
+		}
+	}
+	return nil
+}
+
+// autoDenoise runs the test set sampleRuns times and counts, for each test case, the runs in which each field of
+// its response did not match.
+func (r *Replayer) autoDenoise(ctx context.Context, testSetID string, sampleRuns int, appID uint64) ([]*models.NoiseParams, error) {
+	if sampleRuns < 1 {
+		return nil, fmt.Errorf("invalid number of the denoise runs %d, it should be at least 1", sampleRuns)
+	}
+
+	runs := map[string]int{}
+	diffRuns := map[string]map[string]int{}
+	for i := 0; i < sampleRuns; i++ {
+		testRunID, err := r.GetNextTestRunID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next test run id: %w", err)
+		}
+		r.logger.Info("replaying the test set to detect the noisy fields", zap.String("test-set", testSetID), zap.Int("run", i+1), zap.Int("runs", sampleRuns))
+		status, _, err := r.RunTestSet(ctx, testSetID, testRunID, appID, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run the test set: %w", err)
+		}
+		if status == models.TestSetStatusUserAbort {
+			return nil, context.Canceled
+		}
+		results, err := r.reportDB.GetTestCaseResults(ctx, testRunID, testSetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the test case results: %w", err)
+		}
+		for _, result := range results {
+			runs[result.TestCaseID]++
+			for _, field := range noisyFields(result.Result) {
+				if diffRuns[result.TestCaseID] == nil {
+					diffRuns[result.TestCaseID] = map[string]int{}
+				}
+				diffRuns[result.TestCaseID][field]++
+			}
+		}
+	}
+
+	testCaseIDs := make([]string, 0, len(diffRuns))
+	for testCaseID := range diffRuns {
+		testCaseIDs = append(testCaseIDs, testCaseID)
+	}
+	sort.Strings(testCaseIDs)
+
+	var noiseParams []*models.NoiseParams
+	for _, testCaseID := range testCaseIDs {
+		assertion := map[string][]string{}
+		for field, count := range diffRuns[testCaseID] {
+			if float64(count)/float64(runs[testCaseID]) > r.config.Test.DenoiseThreshold {
+				assertion[field] = []string{}
+			}
+		}
+		if len(assertion) == 0 {
+			continue
+		}
+		noiseParams = append(noiseParams, &models.NoiseParams{
+			TestCaseID: testCaseID,
+			EditedBy:   autoDenoiseEditor,
+			Assertion:  assertion,
+			Ops:        string(models.OpsAdd),
+		})
+	}
+	return noiseParams, nil
+}
+
+// noisyFields returns the noise keys of the mismatching headers and body fields of the result, e.g. header.date
+// and body.data.id, or body for the bodies which are not json.
+func noisyFields(result models.Result) []string {
+	fields := map[string]bool{}
+	for _, header := range result.HeadersResult {
+		if header.Normal {
+			continue
+		}
+		key := header.Expected.Key
+		if key == "" {
+			key = header.Actual.Key
+		}
+		fields["header."+strings.ToLower(key)] = true
+	}
+	for _, body := range result.BodyResult {
+		if body.Normal {
+			continue
+		}
+		diffs := body.Diffs
+		if len(diffs) == 0 {
+			// the exact comparison of the responses does not keep the mismatching fields
+			_, diffs = ExactComparator{}.Compare(body.Expected, body.Actual, nil)
+		}
+		for _, diff := range diffs {
+			if diff.Path == "" {
+				fields["body"] = true
+				continue
+			}
+			fields["body."+diff.Path] = true
+		}
+	}
+	return sortedNoiseFields(fields)
+}
+
+func sortedNoiseFields[V any](fields map[string]V) []string {
+	keys := make([]string, 0, len(fields))
+	for field := range fields {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,249 @@
+//go:build linux
+
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// BodyComparator compares the recorded and the actual bodies of a response, the noise are the dot separated
+// fields of the body not compared, optionally only when their value matches one of the regular expressions.
+type BodyComparator interface {
+	Compare(recorded, actual string, noise map[string][]string) (bool, []models.BodyDiff)
+}
+
+const (
+	// ExactBodyComparator compares the json bodies for equality, it is the comparison of the test cases by default
+	ExactBodyComparator = "exact"
+	// SubsetBodyComparator passes when the actual json body contains all the fields of the recorded one
+	SubsetBodyComparator = "subset"
+	// RegexBodyComparator matches the actual string fields against the recorded ones as regular expressions
+	RegexBodyComparator = "regex"
+)
+
+var (
+	bodyComparatorsMu sync.RWMutex
+	bodyComparators   = map[string]BodyComparator{
+		ExactBodyComparator:  ExactComparator{},
+		SubsetBodyComparator: SubsetComparator{},
+		RegexBodyComparator:  RegexComparator{},
+	}
+)
+
+// RegisterBodyComparator registers the body comparator under the name of the test.bodyComparator config,
+// replacing the comparator registered under the same name.
+func RegisterBodyComparator(name string, comparator BodyComparator) {
+	bodyComparatorsMu.Lock()
+	defer bodyComparatorsMu.Unlock()
+	bodyComparators[name] = comparator
+}
+
+// bodyComparator returns the body comparator of the config. The exact comparison is built in the matching of
+// the responses with the value tolerances and the ordering options, so false is returned for it.
+func (r *Replayer) bodyComparator() (BodyComparator, bool) {
+	name := r.config.Test.BodyComparator
+	if name == "" || name == ExactBodyComparator {
+		return nil, false
+	}
+	bodyComparatorsMu.RLock()
+	comparator, ok := bodyComparators[name]
+	bodyComparatorsMu.RUnlock()
+	if !ok {
+		r.logger.Warn("unknown body comparator, comparing the bodies exactly", zap.String("body comparator", name))
+		return nil, false
+	}
+	return comparator, true
+}
+
+// compareRespWith compares the status code and the headers of the response as usual and its body with the comparator.
+func (r *Replayer) compareRespWith(comparator BodyComparator, tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig config.GlobalNoise) (bool, *models.Result) {
+	bodyless := *tc
+	bodyless.HTTPResp.Body = ""
+	actualBodyless := *actualResponse
+	actualBodyless.Body = ""
+	pass, res := match(&bodyless, &actualBodyless, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+
+	bodyNoise := map[string][]string{}
+	for field, regexArr := range noiseConfig["body"] {
+		bodyNoise[field] = regexArr
+	}
+	bodyNoisy := false
+	for field, regexArr := range tc.Noise {
+		if field == "body" {
+			bodyNoisy = true
+		}
+		if path, ok := strings.CutPrefix(field, "body."); ok {
+			bodyNoise[path] = regexArr
+		}
+	}
+
+	bodyType := models.BodyTypePlain
+	if json.Valid([]byte(actualResponse.Body)) {
+		bodyType = models.BodyTypeJSON
+	}
+	bodyResult := models.BodyResult{
+		Normal:   true,
+		Type:     bodyType,
+		Expected: tc.HTTPResp.Body,
+		Actual:   actualResponse.Body,
+	}
+	if !bodyNoisy {
+		bodyResult.Normal, bodyResult.Diffs = comparator.Compare(tc.HTTPResp.Body, actualResponse.Body, bodyNoise)
+	}
+	res.BodyResult = []models.BodyResult{bodyResult}
+	if !bodyResult.Normal {
+		r.logger.Warn("the response body does not match the recorded one", zap.String("testcase", tc.Name), zap.String("body comparator", r.config.Test.BodyComparator), zap.Any("diffs", bodyResult.Diffs))
+	}
+	return pass && bodyResult.Normal, res
+}
+
+// ExactComparator passes when the bodies are equal, the json bodies whatever the order of their keys.
+type ExactComparator struct{}
+
+func (ExactComparator) Compare(recorded, actual string, noise map[string][]string) (bool, []models.BodyDiff) {
+	return compareBodies(recorded, actual, noise, jsonEqual, false)
+}
+
+// SubsetComparator passes when the actual json body contains all the fields of the recorded one with the
+// same values, the fields added to the actual body are ignored. The recorded array elements are compared
+// with the actual ones at the same index.
+type SubsetComparator struct{}
+
+func (SubsetComparator) Compare(recorded, actual string, noise map[string][]string) (bool, []models.BodyDiff) {
+	return compareBodies(recorded, actual, noise, jsonEqual, true)
+}
+
+// RegexComparator passes when the actual string fields match the recorded ones as regular expressions anchored
+// at both ends, the other fields are compared for equality. The recorded values which are not valid regular
+// expressions are compared for equality as well.
+type RegexComparator struct{}
+
+func (RegexComparator) Compare(recorded, actual string, noise map[string][]string) (bool, []models.BodyDiff) {
+	return compareBodies(recorded, actual, noise, regexEqual, false)
+}
+
+// compareBodies compares the json bodies field by field with the equality of the leaf values, or the bodies
+// as a whole when one of them is not json.
+func compareBodies(recorded, actual string, noise map[string][]string, equal func(recorded, actual interface{}) bool, subset bool) (bool, []models.BodyDiff) {
+	var rec, act interface{}
+	if json.Unmarshal([]byte(recorded), &rec) != nil || json.Unmarshal([]byte(actual), &act) != nil {
+		if equal(recorded, actual) {
+			return true, nil
+		}
+		return false, []models.BodyDiff{{Expected: recorded, Actual: actual}}
+	}
+	var diffs []models.BodyDiff
+	compareJSONValues("", rec, act, noise, equal, subset, &diffs)
+	return len(diffs) == 0, diffs
+}
+
+func compareJSONValues(path string, rec, act interface{}, noise map[string][]string, equal func(recorded, actual interface{}) bool, subset bool, diffs *[]models.BodyDiff) {
+	if path != "" {
+		if regexArr, noisy := CheckStringExist(strings.ToLower(path), noise); noisy {
+			if len(regexArr) == 0 {
+				return
+			}
+			if ok, _ := MatchesAnyRegex(fmt.Sprint(act), regexArr); ok {
+				return
+			}
+		}
+	}
+
+	switch recValue := rec.(type) {
+	case map[string]interface{}:
+		actValue, ok := act.(map[string]interface{})
+		if !ok {
+			*diffs = append(*diffs, bodyDiff(path, rec, act))
+			return
+		}
+		keys := make([]string, 0, len(recValue))
+		for key := range recValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if _, ok := actValue[key]; !ok {
+				*diffs = append(*diffs, bodyDiff(joinPath(path, key), recValue[key], nil))
+				continue
+			}
+			compareJSONValues(joinPath(path, key), recValue[key], actValue[key], noise, equal, subset, diffs)
+		}
+		if subset {
+			return
+		}
+		extra := []string{}
+		for key := range actValue {
+			if _, ok := recValue[key]; !ok {
+				extra = append(extra, key)
+			}
+		}
+		sort.Strings(extra)
+		for _, key := range extra {
+			*diffs = append(*diffs, bodyDiff(joinPath(path, key), nil, actValue[key]))
+		}
+	case []interface{}:
+		actValue, ok := act.([]interface{})
+		if !ok || len(actValue) < len(recValue) || (!subset && len(actValue) != len(recValue)) {
+			*diffs = append(*diffs, bodyDiff(path, rec, act))
+			return
+		}
+		// the elements of the arrays share the noise of the array
+		for i := range recValue {
+			compareJSONValues(path, recValue[i], actValue[i], noise, equal, subset, diffs)
+		}
+	default:
+		if !equal(rec, act) {
+			*diffs = append(*diffs, bodyDiff(path, rec, act))
+		}
+	}
+}
+
+func jsonEqual(recorded, actual interface{}) bool {
+	return reflect.DeepEqual(recorded, actual)
+}
+
+func regexEqual(recorded, actual interface{}) bool {
+	pattern, ok := recorded.(string)
+	value, isString := actual.(string)
+	if !ok || !isString {
+		return reflect.DeepEqual(recorded, actual)
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return pattern == value
+	}
+	return re.MatchString(value)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func bodyDiff(path string, recorded, actual interface{}) models.BodyDiff {
+	return models.BodyDiff{Path: path, Expected: jsonString(recorded), Actual: jsonString(actual)}
+}
+
+// jsonString returns the json of the value, empty for a missing value.
+func jsonString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
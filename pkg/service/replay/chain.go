@@ -0,0 +1,175 @@
+//go:build linux
+
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// chainPlaceholder matches the {{.Chain.NAME}} placeholders of the chained variables
+var chainPlaceholder = regexp.MustCompile(`\{\{\s*\.Chain\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// requestChain holds the variables extracted from the responses of the test cases of a test set, it lives as long
+// as the run of the test set so that the variables don't leak into the other test sets.
+type requestChain struct {
+	rules []config.ChainRule
+	vars  map[string]string
+	// errs are the reasons the variables could not be extracted, to tell why a dependent test case fails
+	errs map[string]error
+}
+
+// keepsRecordedOrder reports whether the test cases of the test set are run serially in the recorded order whatever
+// the ordering and parallelism options, as the chained test cases depend on the values of the earlier ones.
+func (r *Replayer) keepsRecordedOrder(testSetID string) bool {
+	return len(r.config.Test.Chaining[testSetID]) > 0
+}
+
+func newRequestChain(rules []config.ChainRule) *requestChain {
+	return &requestChain{rules: rules, vars: map[string]string{}, errs: map[string]error{}}
+}
+
+// extract extracts the variables of the rules of the test case from its actual response.
+func (c *requestChain) extract(testCaseName string, resp *models.HTTPResp) {
+	if resp == nil {
+		return
+	}
+	for _, rule := range c.rules {
+		if rule.TestCase != testCaseName {
+			continue
+		}
+		value, err := jsonPathValue(resp.Body, rule.Path)
+		if err != nil {
+			delete(c.vars, rule.Var)
+			c.errs[rule.Var] = fmt.Errorf("failed to extract %s from the response of the test case %s: %w", rule.Path, testCaseName, err)
+			continue
+		}
+		delete(c.errs, rule.Var)
+		c.vars[rule.Var] = value
+	}
+}
+
+// substitute replaces the chained variables of the url, the header values and the body of the request of the test
+// case. The headers are replaced with a copy, the recorded ones are left as is to be persisted with their placeholders.
+func (c *requestChain) substitute(tc *models.TestCase) error {
+	var err error
+	if tc.HTTPReq.URL, err = c.expand(tc.Name, tc.HTTPReq.URL); err != nil {
+		return err
+	}
+	if tc.HTTPReq.Body, err = c.expand(tc.Name, tc.HTTPReq.Body); err != nil {
+		return err
+	}
+	var header map[string]string
+	for key, value := range tc.HTTPReq.Header {
+		if !chainPlaceholder.MatchString(value) {
+			continue
+		}
+		if header == nil {
+			header = make(map[string]string, len(tc.HTTPReq.Header))
+			for k, v := range tc.HTTPReq.Header {
+				header[k] = v
+			}
+		}
+		if header[key], err = c.expand(tc.Name, value); err != nil {
+			return err
+		}
+	}
+	if header != nil {
+		tc.HTTPReq.Header = header
+	}
+	return nil
+}
+
+func (c *requestChain) expand(testCaseName, value string) (string, error) {
+	var missing error
+	expanded := chainPlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := chainPlaceholder.FindStringSubmatch(placeholder)[1]
+		if v, ok := c.vars[name]; ok {
+			return v
+		}
+		if missing == nil {
+			if err, ok := c.errs[name]; ok {
+				missing = fmt.Errorf("the chained variable %s of the test case %s is not set: %w", name, testCaseName, err)
+			} else {
+				missing = fmt.Errorf("the chained variable %s of the test case %s is not set, no test case run before it extracts it", name, testCaseName)
+			}
+		}
+		return placeholder
+	})
+	if missing != nil {
+		return "", missing
+	}
+	return expanded, nil
+}
+
+// jsonPathValue returns the value at the JSONPath of the json body, e.g. $.data.items[0].id, the strings are
+// returned as is and the other values json encoded.
+func jsonPathValue(body, path string) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return "", fmt.Errorf("the response body is not json: %w", err)
+	}
+	segments, err := jsonPathSegments(path)
+	if err != nil {
+		return "", err
+	}
+	for _, segment := range segments {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			field, ok := v[segment]
+			if !ok {
+				return "", fmt.Errorf("the field %q is missing", segment)
+			}
+			value = field
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return "", fmt.Errorf("the index %q is out of the %d elements of the array", segment, len(v))
+			}
+			value = v[index]
+		default:
+			return "", fmt.Errorf("the field %q is not in an object or an array", segment)
+		}
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the value: %w", err)
+	}
+	return string(data), nil
+}
+
+// jsonPathSegments splits the JSONPath into its field names and array indices, the root $ is optional.
+func jsonPathSegments(path string) ([]string, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var segments []string
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath %q, unclosed bracket", path)
+			}
+			segments = append(segments, strings.Trim(rest[1:end], `'"`))
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,121 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	// the database/sql drivers of the models.DBAssertionDrivers
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// runDBAssertions runs the database assertions of the test case and returns the ones which did not match.
+// The rows are compared like the json bodies, so the noise of the test case under "db." is applied to them.
+func (r *Replayer) runDBAssertions(ctx context.Context, tc *models.TestCase) []models.DBAssertionFailure {
+	var failures []models.DBAssertionFailure
+	for _, assertion := range tc.DBAssertions {
+		expected, err := json.Marshal(assertion.ExpectedRows)
+		if err != nil {
+			failures = append(failures, models.DBAssertionFailure{Query: assertion.Query, Message: fmt.Sprintf("failed to marshal the expected rows: %v", err)})
+			continue
+		}
+		rows, err := queryRows(ctx, assertion, time.Duration(r.config.Test.APITimeout)*time.Second)
+		if err != nil {
+			failures = append(failures, models.DBAssertionFailure{Query: assertion.Query, Expected: string(expected), Message: err.Error()})
+			continue
+		}
+		actual, err := json.Marshal(rows)
+		if err != nil {
+			failures = append(failures, models.DBAssertionFailure{Query: assertion.Query, Expected: string(expected), Message: fmt.Sprintf("failed to marshal the actual rows: %v", err)})
+			continue
+		}
+
+		exp, act := string(expected), string(actual)
+		validatedJSON, err := ValidateAndMarshalJSON(r.logger, &exp, &act)
+		if err != nil {
+			failures = append(failures, models.DBAssertionFailure{Query: assertion.Query, Expected: exp, Actual: act, Message: fmt.Sprintf("failed to validate the rows: %v", err)})
+			continue
+		}
+		if validatedJSON.isIdentical {
+			result, err := JSONDiffWithNoiseControl(validatedJSON, dbNoise(tc.Noise), r.config.Test.IgnoreOrdering, r.valueTolerance())
+			if err == nil && result.matches {
+				continue
+			}
+		}
+		r.logger.Debug("database assertion failed", zap.String("testcase", tc.Name), zap.String("query", assertion.Query), zap.String("expected", exp), zap.String("actual", act))
+		failures = append(failures, models.DBAssertionFailure{Query: assertion.Query, Expected: exp, Actual: act, Message: "rows returned by the query do not match the expected rows"})
+	}
+	return failures
+}
+
+// queryRows runs the query of the assertion and returns the rows as column name to value maps.
+func queryRows(ctx context.Context, assertion models.DBAssertion, timeout time.Duration) ([]map[string]interface{}, error) {
+	if err := assertion.Validate(); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(assertion.Driver, assertion.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the %s database: %w", assertion.Driver, err)
+	}
+	defer db.Close()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	rows, err := db.QueryContext(ctx, assertion.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the columns: %w", err)
+	}
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan the row: %w", err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			// most of the drivers return the text and numeric columns as bytes
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+				continue
+			}
+			row[column] = values[i]
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the rows: %w", err)
+	}
+	return result, nil
+}
+
+// dbNoise returns the noise of the test case under the "db." prefix, e.g. db.updated_at.
+func dbNoise(noise map[string][]string) map[string][]string {
+	m := map[string][]string{}
+	for field, regexArr := range noise {
+		if key, ok := strings.CutPrefix(field, "db."); ok {
+			m[key] = regexArr
+		}
+	}
+	return m
+}
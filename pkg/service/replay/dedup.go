@@ -0,0 +1,75 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DeduplicateTestCases deletes the http test cases of the test set with the same method, url and body as an
+// earlier test case of the test set, and returns the number of deleted test cases.
+func (r *Replayer) DeduplicateTestCases(ctx context.Context, testSetID string, strategy models.DedupStrategy) (int, error) {
+	testCases, err := r.testDB.GetTestCases(ctx, testSetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the test cases: %w", err)
+	}
+
+	seen := map[string]string{}
+	var duplicates []string
+	for _, testCase := range testCases {
+		// the grpc and the websocket test cases don't have a http request to compare
+		if testCase.Kind != models.HTTP && testCase.Kind != "" {
+			continue
+		}
+		fingerprint := testCaseFingerprint(testCase, strategy)
+		if original, ok := seen[fingerprint]; ok {
+			r.logger.Debug("found a duplicate test case", zap.String("testcase", testCase.Name), zap.String("duplicate of", original), zap.String("test-set", testSetID))
+			duplicates = append(duplicates, testCase.Name)
+			continue
+		}
+		seen[fingerprint] = testCase.Name
+	}
+	if len(duplicates) == 0 {
+		return 0, nil
+	}
+
+	err = r.testDB.DeleteTests(ctx, testSetID, duplicates)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete the duplicate test cases: %w", err)
+	}
+	return len(duplicates), nil
+}
+
+// testCaseFingerprint hashes the method, the url and the body of the request of the test case. The semantic
+// strategy normalizes the json bodies and the order of the query parameters before hashing.
+func testCaseFingerprint(testCase *models.TestCase, strategy models.DedupStrategy) string {
+	method := string(testCase.HTTPReq.Method)
+	reqURL := testCase.HTTPReq.URL
+	body := testCase.HTTPReq.Body
+	if strategy == models.DedupSemantic {
+		method = strings.ToUpper(method)
+		if u, err := url.Parse(reqURL); err == nil {
+			// the encoded query is sorted by the key
+			u.RawQuery = u.Query().Encode()
+			reqURL = u.String()
+		}
+		var v interface{}
+		if json.Unmarshal([]byte(body), &v) == nil {
+			// the keys of the json objects are marshalled in the sorted order
+			if normalized, err := json.Marshal(v); err == nil {
+				body = string(normalized)
+			}
+		}
+	}
+	sum := sha256.Sum256([]byte(method + "\n" + reqURL + "\n" + body))
+	return hex.EncodeToString(sum[:])
+}
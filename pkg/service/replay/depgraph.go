@@ -0,0 +1,177 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DotGraphFormat writes the dependency graph as a graphviz digraph
+	DotGraphFormat = "dot"
+	// MermaidGraphFormat writes the dependency graph as a mermaid flowchart, fenced in a markdown code block for the .md files
+	MermaidGraphFormat = "mermaid"
+	// JSONGraphFormat writes the nodes and the edges of the dependency graph as json
+	JSONGraphFormat = "json"
+)
+
+type dependencyGraph struct {
+	TestSetID string      `json:"testSetID"`
+	Nodes     []graphNode `json:"nodes"`
+	Edges     []graphEdge `json:"edges"`
+}
+
+type graphNode struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"` // testcase or mock
+	Label string `json:"label"`
+}
+
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"` // dependsOn or consumes
+}
+
+// ExportDependencyGraph writes the graph of the test cases of the test set, linked to the test cases they depend on
+// and to the mocks they consumed in the latest test run of the test set, in the format to the output path.
+func (r *Replayer) ExportDependencyGraph(ctx context.Context, testSetID string, format string, outputPath string) error {
+	graph, err := r.dependencyGraph(ctx, testSetID)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case DotGraphFormat:
+		data = []byte(graph.dot())
+	case MermaidGraphFormat:
+		mermaid := graph.mermaid()
+		if strings.EqualFold(filepath.Ext(outputPath), ".md") {
+			mermaid = "```mermaid\n" + mermaid + "```\n"
+		}
+		data = []byte(mermaid)
+	case JSONGraphFormat:
+		data, err = json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the dependency graph: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid graph format %q, it should be dot, mermaid or json", format)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o777); err != nil {
+		return fmt.Errorf("failed to create the directory of the dependency graph: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0o777); err != nil {
+		return fmt.Errorf("failed to write the dependency graph: %w", err)
+	}
+	return nil
+}
+
+func (r *Replayer) dependencyGraph(ctx context.Context, testSetID string) (*dependencyGraph, error) {
+	testCases, err := r.testDB.GetTestCases(ctx, testSetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the test cases: %w", err)
+	}
+	// the zero times select all the mocks of the test set
+	filtered, err := r.mockDB.GetFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the filtered mocks: %w", err)
+	}
+	unfiltered, err := r.mockDB.GetUnFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the unfiltered mocks: %w", err)
+	}
+	// the consumed mocks are known from the latest report of the test set, if it ran
+	reports, err := r.lastReports(ctx, testSetID, 1)
+	if err != nil {
+		return nil, err
+	}
+	var consumedMocks map[string][]string
+	if len(reports) > 0 {
+		consumedMocks = reports[0].ConsumedMocks
+	}
+
+	graph := &dependencyGraph{TestSetID: testSetID, Nodes: []graphNode{}, Edges: []graphEdge{}}
+	testCaseIDs := map[string]string{}
+	for i, tc := range testCases {
+		id := fmt.Sprintf("tc%d", i)
+		testCaseIDs[tc.Name] = id
+		graph.Nodes = append(graph.Nodes, graphNode{ID: id, Kind: "testcase", Label: tc.Name})
+	}
+	for _, tc := range testCases {
+		for _, dependency := range tc.DependsOn {
+			to, ok := testCaseIDs[dependency]
+			if !ok {
+				r.logger.Warn("the test case depends on a test case which is not in the test set", zap.String("testcase", tc.Name), zap.String("depends on", dependency), zap.String("test-set", testSetID))
+				continue
+			}
+			graph.Edges = append(graph.Edges, graphEdge{From: testCaseIDs[tc.Name], To: to, Type: "dependsOn"})
+		}
+	}
+	for i, mock := range append(filtered, unfiltered...) {
+		id := fmt.Sprintf("mock%d", i)
+		graph.Nodes = append(graph.Nodes, graphNode{ID: id, Kind: "mock", Label: mock.Name + " (" + string(mock.Kind) + ")"})
+		consumedBy := append([]string{}, consumedMocks[mock.Name]...)
+		sort.Strings(consumedBy)
+		for _, testCaseName := range consumedBy {
+			if from, ok := testCaseIDs[testCaseName]; ok {
+				graph.Edges = append(graph.Edges, graphEdge{From: from, To: id, Type: "consumes"})
+			}
+		}
+	}
+	return graph, nil
+}
+
+func (g *dependencyGraph) dot() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", g.TestSetID)
+	for _, node := range g.Nodes {
+		shape := "box"
+		if node.Kind == "mock" {
+			shape = "diamond"
+		}
+		fmt.Fprintf(&b, "  %s [label=%q, shape=%s];\n", node.ID, node.Label, shape)
+	}
+	for _, edge := range g.Edges {
+		style := "solid"
+		if edge.Type == "consumes" {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [style=%s];\n", edge.From, edge.To, style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (g *dependencyGraph) mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, node := range g.Nodes {
+		// the quotes are not allowed in the labels of mermaid
+		label := strings.ReplaceAll(node.Label, `"`, "'")
+		if node.Kind == "mock" {
+			fmt.Fprintf(&b, "  %s{\"%s\"}\n", node.ID, label)
+		} else {
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", node.ID, label)
+		}
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Type == "consumes" {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", edge.From, arrow, edge.To)
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// dryRun lists the test sets and the test cases which would run with the selected tests and the base path,
+// without instrumenting or starting the application.
+func (r *Replayer) dryRun(ctx context.Context, testSetIDs []string) error {
+	var totalTestSets, totalTestCases int
+	for _, testSetID := range testSetIDs {
+		selected, isSelected := r.config.Test.SelectedTests[testSetID]
+		if !isSelected && len(r.config.Test.SelectedTests) != 0 {
+			r.logger.Debug("test set is not selected", zap.String("test-set", testSetID))
+			continue
+		}
+		testCases, err := r.testDB.GetTestCases(ctx, testSetID)
+		if err != nil {
+			return fmt.Errorf("failed to get the test cases of the test set %s: %w", testSetID, err)
+		}
+
+		selectedTests := ArrayToMap(selected)
+		found := map[string]bool{}
+		var runnable []*models.TestCase
+		for _, testCase := range testCases {
+			if _, ok := selectedTests[testCase.Name]; !ok && len(selectedTests) != 0 {
+				continue
+			}
+			found[testCase.Name] = true
+			runnable = append(runnable, testCase)
+		}
+		if r.config.Test.TestNameFilter != "" {
+			runnable, err = r.filterTestCasesByName(runnable, nil)
+			if err != nil {
+				return err
+			}
+		}
+		// the selected names that don't match any test case are the usual reason for an unexpected selection
+		for _, name := range selected {
+			if !found[name] {
+				r.logger.Warn("selected test case not found in the test set", zap.String("test-set", testSetID), zap.String("testcase", name))
+			}
+		}
+
+		fmt.Printf("\n%s (%d of %d test cases)\n", testSetID, len(runnable), len(testCases))
+		for _, testCase := range runnable {
+			fmt.Printf("\t%s\t%s\n", testCase.Name, r.dryRunTarget(testCase))
+		}
+		totalTestSets++
+		totalTestCases += len(runnable)
+	}
+	r.logger.Info("dry run completed, nothing was executed", zap.Int("test sets", totalTestSets), zap.Int("test cases", totalTestCases))
+	return nil
+}
+
+// dryRunTarget describes the request the test case would send, with the base path applied.
+func (r *Replayer) dryRunTarget(testCase *models.TestCase) string {
+	switch testCase.Kind {
+	case models.GRPC_EXPORT:
+		return fmt.Sprintf("gRPC %s", testCase.GrpcReq.Headers.PseudoHeaders[":path"])
+	case models.WS:
+		return fmt.Sprintf("WebSocket %s", testCase.WSReq.URL)
+	}
+	target := testCase.HTTPReq.URL
+	if r.config.Test.BasePath != "" {
+		newURL, err := ReplaceBaseURL(r.config.Test.BasePath, target)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to replace the request basePath", zap.String("testcase", testCase.Name))
+		} else {
+			target = newURL
+		}
+	}
+	return fmt.Sprintf("%s %s", testCase.HTTPReq.Method, target)
+}
@@ -0,0 +1,71 @@
+//go:build linux
+
+package replay
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// envPlaceholder matches the ${VAR} and the {{.Env.VAR}} placeholders of the environment variables
+var envPlaceholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\{\{\s*\.Env\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// expandEnv expands the environment variable placeholders of the url and the header values of the request of the
+// test case, so that the same test set runs against the environments, e.g. ${API_HOST} or {{.Env.TOKEN}}.
+// The headers are replaced with a copy, the recorded ones are left as is to be persisted with their placeholders.
+func expandEnv(tc *models.TestCase) error {
+	url, err := expandEnvPlaceholders(tc.HTTPReq.URL)
+	if err != nil {
+		return fmt.Errorf("failed to expand the url of the test case %s: %w", tc.Name, err)
+	}
+	tc.HTTPReq.URL = url
+
+	var header map[string]string
+	for key, value := range tc.HTTPReq.Header {
+		if !envPlaceholder.MatchString(value) {
+			continue
+		}
+		if header == nil {
+			header = make(map[string]string, len(tc.HTTPReq.Header))
+			for k, v := range tc.HTTPReq.Header {
+				header[k] = v
+			}
+		}
+		header[key], err = expandEnvPlaceholders(value)
+		if err != nil {
+			return fmt.Errorf("failed to expand the %s header of the test case %s: %w", key, tc.Name, err)
+		}
+	}
+	if header != nil {
+		tc.HTTPReq.Header = header
+	}
+	return nil
+}
+
+// expandEnvPlaceholders replaces the placeholders of the value with the environment variables, a variable which is
+// not set is an error rather than an empty string, which would silently target the wrong host or send no token.
+func expandEnvPlaceholders(value string) (string, error) {
+	var missing string
+	expanded := envPlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		match := envPlaceholder.FindStringSubmatch(placeholder)
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return placeholder
+		}
+		return env
+	})
+	if missing != "" {
+		return "", fmt.Errorf("the environment variable %s is not set", missing)
+	}
+	return expanded, nil
+}
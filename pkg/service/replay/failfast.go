@@ -0,0 +1,47 @@
+//go:build linux
+
+package replay
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// FailedTestCase identifies the first failing test case of the test run in the fail fast mode.
+type FailedTestCase struct {
+	TestSetID  string `json:"testSetId"`
+	TestCaseID string `json:"testCaseId"`
+}
+
+// recordFirstFailure remembers the failing test case if it is the first one of the test run.
+func (r *Replayer) recordFirstFailure(testSetID, testCaseID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.firstFailure == nil {
+		r.firstFailure = &FailedTestCase{TestSetID: testSetID, TestCaseID: testCaseID}
+	}
+}
+
+// getFirstFailure returns the first failing test case of the test run if the fail fast mode stopped it.
+func (r *Replayer) getFirstFailure() *FailedTestCase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.firstFailure
+}
+
+// failedFast reports whether the test run should stop because a test case failed in the fail fast mode.
+func (r *Replayer) failedFast() bool {
+	return r.config.Test.FailFast && r.getFirstFailure() != nil
+}
+
+// maxFailuresReached reports whether the remaining test cases of the test set should be aborted, as the number
+// of the failed test cases of the test set reached the max failures.
+func (r *Replayer) maxFailuresReached(testSetID string, failures int) bool {
+	maxFailures := r.config.Test.MaxFailures
+	if maxFailures <= 0 || failures < maxFailures {
+		return false
+	}
+	r.logger.Warn(fmt.Sprintf("Aborting test set after %d failures (max-failures=%d)", failures, maxFailures), zap.String("test-set", testSetID))
+	return true
+}
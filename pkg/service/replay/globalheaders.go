@@ -0,0 +1,43 @@
+//go:build linux
+
+package replay
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithGlobalHeaders adds the headers to every http request sent to the application, e.g. the environment or the
+// bearer token of a staging cluster. The headers of the test case take precedence unless override is set.
+func WithGlobalHeaders(headers map[string]string, override bool) RequestMockUtilOption {
+	return func(t *requestMockUtil) {
+		t.headers = headers
+		t.overrideHeaders = override
+	}
+}
+
+// mergeHeaders returns a copy of the headers of the request with the global headers, the header names are
+// compared case insensitively. The keys of the config maps are lower cased, so the global headers are canonicalized.
+func mergeHeaders(header map[string]string, global map[string]string, override bool) map[string]string {
+	merged := make(map[string]string, len(header)+len(global))
+	for key, value := range header {
+		merged[key] = value
+	}
+	for name, value := range global {
+		existing := ""
+		for key := range merged {
+			if strings.EqualFold(key, name) {
+				existing = key
+				break
+			}
+		}
+		if existing != "" {
+			if !override {
+				continue
+			}
+			delete(merged, existing)
+		}
+		merged[http.CanonicalHeaderKey(name)] = value
+	}
+	return merged
+}
@@ -0,0 +1,174 @@
+//go:build linux
+
+package replay
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// coveragePercentRe matches the coverage of a package in the output of go tool covdata percent,
+// e.g. "	go.keploy.io/app/handlers	coverage: 75.3% of statements".
+var coveragePercentRe = regexp.MustCompile(`coverage:\s*([0-9]+(?:\.[0-9]+)?)%`)
+
+// parseCoveragePercent returns the coverage of the output of go tool covdata percent. The output has a
+// line per package without their number of statements, so the coverage of several packages is their mean.
+func parseCoveragePercent(output string) (float64, error) {
+	var total float64
+	var packages int
+	for _, line := range strings.Split(output, "\n") {
+		match := coveragePercentRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		percent, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse the coverage %q: %w", match[1], err)
+		}
+		total += percent
+		packages++
+	}
+	if packages == 0 {
+		return 0, fmt.Errorf("no coverage found in the output %q", strings.TrimSpace(output))
+	}
+	return total / float64(packages), nil
+}
+
+// profileCoverage returns the percentage of the statements covered in the text coverage profile of
+// go tool covdata textfmt, the blocks are counted once whatever the number of their lines.
+func profileCoverage(path string) (float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open the coverage profile: %w", err)
+	}
+	defer file.Close()
+
+	type block struct {
+		statements int
+		covered    bool
+	}
+	blocks := map[string]*block{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// file.go:startLine.startCol,endLine.endCol numStatements count
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return 0, fmt.Errorf("malformed coverage profile line %q", line)
+		}
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0, fmt.Errorf("malformed number of statements in the coverage profile line %q: %w", line, err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return 0, fmt.Errorf("malformed count in the coverage profile line %q: %w", line, err)
+		}
+		b, ok := blocks[fields[0]]
+		if !ok {
+			b = &block{statements: statements}
+			blocks[fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read the coverage profile: %w", err)
+	}
+
+	var statements, covered int
+	for _, b := range blocks {
+		statements += b.statements
+		if b.covered {
+			covered += b.statements
+		}
+	}
+	if statements == 0 {
+		return 0, errors.New("no statements found in the coverage profile")
+	}
+	return float64(covered) * 100 / float64(statements), nil
+}
+
+// checkMinCoverage compares the go coverage of the test run with test.minCoverage. The coverage is read from the
+// text coverage profile, or from the output of go tool covdata percent when the profile can't be read.
+func (r *Replayer) checkMinCoverage(percentOutput, profilePath string) error {
+	minCoverage := r.config.Test.MinCoverage
+	coverage, err := profileCoverage(profilePath)
+	if err != nil {
+		r.logger.Debug("failed to read the coverage profile, reading the coverage of the packages", zap.String("path", profilePath), zap.Error(err))
+		coverage, err = parseCoveragePercent(percentOutput)
+	}
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to measure the coverage of the test run", zap.Float64("minimum coverage", minCoverage))
+		return fmt.Errorf("%w: failed to measure the coverage: %v", ErrCoverageBelowThreshold, err)
+	}
+	if coverage < minCoverage {
+		utils.LogError(r.logger, nil, "the coverage of the test run is below the minimum coverage", zap.String("coverage", fmt.Sprintf("%.2f%%", coverage)), zap.String("minimum coverage", fmt.Sprintf("%.2f%%", minCoverage)))
+		return fmt.Errorf("%w: %.2f%% < %.2f%%", ErrCoverageBelowThreshold, coverage, minCoverage)
+	}
+	r.logger.Info("the coverage of the test run meets the minimum coverage", zap.String("coverage", fmt.Sprintf("%.2f%%", coverage)), zap.String("minimum coverage", fmt.Sprintf("%.2f%%", minCoverage)))
+	return nil
+}
+
+// mergeGoCoverage merges the go coverage of the test run in GOCOVERDIR with the cumulative coverage of the previous
+// runs in the coverage directory of the keploy directory, so that it tells the code paths any of the runs exercised.
+// The merge is written to a temporary directory first, the cumulative coverage is not lost if it fails.
+func (r *Replayer) mergeGoCoverage(ctx context.Context) error {
+	runDir := os.Getenv("GOCOVERDIR")
+	if runDir == "" {
+		return errors.New("GOCOVERDIR is not set")
+	}
+	storagePath := config.StoragePath(r.config)
+	mergedDir := filepath.Join(storagePath, "coverage")
+	inputs := []string{runDir}
+	if hasCoverageData(mergedDir) {
+		inputs = append(inputs, mergedDir)
+	}
+
+	tmpDir, err := os.MkdirTemp(storagePath, ".coverage-merge-")
+	if err != nil {
+		return fmt.Errorf("failed to create the directory of the merged coverage: %w", err)
+	}
+	mergeCmd := exec.CommandContext(ctx, "go", "tool", "covdata", "merge", "-i="+strings.Join(inputs, ","), "-o="+tmpDir)
+	if output, err := mergeCmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return fmt.Errorf("failed to merge the coverage, %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	if err := os.RemoveAll(mergedDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return fmt.Errorf("failed to remove the previous merged coverage: %w", err)
+	}
+	if err := os.Rename(tmpDir, mergedDir); err != nil {
+		return fmt.Errorf("failed to move the merged coverage: %w", err)
+	}
+
+	percentCmd := exec.CommandContext(ctx, "go", "tool", "covdata", "percent", "-i="+mergedDir)
+	output, err := percentCmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to get the merged coverage: %w", err)
+	}
+	r.logger.Info("merged the go coverage with the coverage of the previous runs", zap.String("path", mergedDir), zap.Int("merged runs", len(inputs)))
+	r.logger.Sugar().Infoln("\n", models.HighlightPassingString(string(output)))
+	return nil
+}
+
+// hasCoverageData reports whether the directory holds the meta-data files of a go coverage.
+func hasCoverageData(dir string) bool {
+	matches, err := filepath.Glob(filepath.Join(dir, "covmeta.*"))
+	return err == nil && len(matches) > 0
+}
@@ -0,0 +1,86 @@
+//go:build linux
+
+package replay
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// graphQLResponse is the body of a graphql response, the errors are reported with a 200 OK status code.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []interface{}   `json:"errors"`
+}
+
+// jsonPathIndex matches the array indices and wildcards of a JSONPath, e.g. [0] or [*].
+var jsonPathIndex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// graphQLNoiseKey converts a JSONPath-style field of the graphql data (e.g. $.data.users[*].id or $.users[*].id)
+// to the dot separated key of the body noise, the elements of the arrays share the key of the array.
+func graphQLNoiseKey(path string) string {
+	key := jsonPathIndex.ReplaceAllString(path, "")
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "$"), ".")
+	key = strings.TrimPrefix(key, "data.")
+	return strings.ToLower("data." + key)
+}
+
+// compareGraphQLResp compares the data of the recorded and the actual graphql responses with the noise
+// and the graphql noise. The errors of the actual response fail the test case whatever its status code.
+func (r *Replayer) compareGraphQLResp(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig config.GlobalNoise) (bool, *models.Result) {
+	var expected, actual graphQLResponse
+	if json.Unmarshal([]byte(tc.HTTPResp.Body), &expected) != nil || json.Unmarshal([]byte(actualResponse.Body), &actual) != nil {
+		r.logger.Debug("the response is not a graphql response, comparing it as a http response", zap.String("testcase", tc.Name))
+		return match(tc, actualResponse, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+	}
+
+	// the errors are kept in the compared actual body so that they show up in the diff
+	expBody, err := json.Marshal(map[string]interface{}{"data": expected.Data})
+	if err != nil {
+		r.logger.Warn("failed to marshal the data of the recorded graphql response", zap.String("testcase", tc.Name), zap.Error(err))
+		return match(tc, actualResponse, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+	}
+	actBodyMap := map[string]interface{}{"data": actual.Data}
+	if len(actual.Errors) > 0 {
+		actBodyMap["errors"] = actual.Errors
+	}
+	actBody, err := json.Marshal(actBodyMap)
+	if err != nil {
+		r.logger.Warn("failed to marshal the data of the actual graphql response", zap.String("testcase", tc.Name), zap.Error(err))
+		return match(tc, actualResponse, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+	}
+
+	bodyNoise := map[string][]string{}
+	for field, regexArr := range noiseConfig["body"] {
+		bodyNoise[field] = regexArr
+	}
+	for path, regexArr := range r.config.Test.GlobalNoise.GraphQL {
+		bodyNoise[graphQLNoiseKey(path)] = regexArr
+	}
+	graphQLNoise := config.GlobalNoise{}
+	for part, fields := range noiseConfig {
+		graphQLNoise[part] = fields
+	}
+	graphQLNoise["body"] = bodyNoise
+
+	graphQLCase := *tc
+	graphQLCase.HTTPResp.Body = string(expBody)
+	graphQLActual := *actualResponse
+	graphQLActual.Body = string(actBody)
+	pass, res := match(&graphQLCase, &graphQLActual, graphQLNoise, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+
+	// report the responses as they were received
+	res.BodyResult[0].Expected = tc.HTTPResp.Body
+	res.BodyResult[0].Actual = actualResponse.Body
+	if len(actual.Errors) > 0 {
+		r.logger.Warn("the graphql response has errors", zap.String("testcase", tc.Name), zap.Int("status code", actualResponse.StatusCode), zap.Any("errors", actual.Errors))
+		res.BodyResult[0].Normal = false
+		pass = false
+	}
+	return pass, res
+}
@@ -0,0 +1,110 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.keploy.io/server/v2/pkg"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// grpcRequestEmulator simulates the grpc test cases, the rest of the hooks are delegated to the http request emulator.
+type grpcRequestEmulator struct {
+	RequestMockHandler
+	logger     *zap.Logger
+	apiTimeout uint64
+}
+
+func newGrpcRequestEmulator(logger *zap.Logger, apiTimeout uint64, handler RequestMockHandler) RequestMockHandler {
+	return &grpcRequestEmulator{
+		RequestMockHandler: handler,
+		logger:             logger,
+		apiTimeout:         apiTimeout,
+	}
+}
+
+// SimulateRequest replays the grpc request and returns the grpc response wrapped in a http response,
+// with the grpc-status as the status code and the protoscope decoded message as the body.
+func (g *grpcRequestEmulator) SimulateRequest(ctx context.Context, _ uint64, tc *models.TestCase, testSetID string) (*models.HTTPResp, error) {
+	if tc.Kind != models.GRPC_EXPORT {
+		return nil, fmt.Errorf("grpc emulator can't simulate the test case of kind %s", tc.Kind)
+	}
+	g.logger.Debug("Before simulating the grpc request", zap.Any("Test case", tc))
+	resp, err := pkg.SimulateGRPC(ctx, *tc, testSetID, g.logger, g.apiTimeout)
+	g.logger.Debug("After simulating the grpc request", zap.Any("test case id", tc.Name))
+	if err != nil {
+		return nil, err
+	}
+	httpResp := grpcToHTTPResp(*resp)
+	return &httpResp, nil
+}
+
+// emulatorFor picks the request emulator for the kind of the test case.
+func (r *Replayer) emulatorFor(tc *models.TestCase) RequestMockHandler {
+	switch tc.Kind {
+	case models.GRPC_EXPORT:
+		return r.grpcEmulator
+	case models.WS:
+		return r.wsEmulator
+	}
+	return requestMockemulator
+}
+
+// grpcToHTTPResp wraps the grpc response in a http response so that it is compared like the http responses.
+// The grpc-status is sent in the trailers, or in the headers for the trailers-only responses.
+func grpcToHTTPResp(resp models.GrpcResp) models.HTTPResp {
+	header := map[string]string{}
+	for key, value := range resp.Headers.OrdinaryHeaders {
+		header[key] = value
+	}
+	for key, value := range resp.Trailers.OrdinaryHeaders {
+		header[key] = value
+	}
+
+	grpcStatus, err := strconv.Atoi(header["grpc-status"])
+	if err != nil {
+		// unknown status code as per the grpc spec
+		grpcStatus = 2
+	}
+	return models.HTTPResp{
+		StatusCode: grpcStatus,
+		Header:     header,
+		Body:       resp.Body.DecodedData,
+	}
+}
+
+// rewriteGrpcAuthority replaces the recorded authority of the grpc request with the host of the base path,
+// or with the ip of the application container in the docker environment.
+func (r *Replayer) rewriteGrpcAuthority(tc *models.TestCase, userIP string) error {
+	if tc.GrpcReq.Headers.PseudoHeaders == nil {
+		tc.GrpcReq.Headers.PseudoHeaders = map[string]string{}
+	}
+	authority := tc.GrpcReq.Headers.PseudoHeaders[":authority"]
+	if r.config.Test.BasePath != "" {
+		base, err := url.Parse(r.config.Test.BasePath)
+		if err != nil {
+			return fmt.Errorf("failed to parse the base path: %w", err)
+		}
+		tc.GrpcReq.Headers.PseudoHeaders[":authority"] = base.Host
+		return nil
+	}
+	if !utils.IsDockerKind(utils.CmdType(r.config.CommandType)) {
+		return nil
+	}
+	replaced, err := utils.ReplaceHostToIP("http://"+authority, userIP)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(replaced)
+	if err != nil {
+		return fmt.Errorf("failed to parse the replaced authority: %w", err)
+	}
+	tc.GrpcReq.Headers.PseudoHeaders[":authority"] = u.Host
+	return nil
+}
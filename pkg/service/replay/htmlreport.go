@@ -0,0 +1,379 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// htmlTestSet is the data of a test set in the html report.
+type htmlTestSet struct {
+	Name    string
+	Status  string
+	Passed  bool
+	Total   int
+	Success int
+	Failure int
+	Page    string
+	Tests   []htmlTestCase
+}
+
+// htmlTestCase is a collapsible section of the test set page with the request, the expected and the actual
+// response and the noise applied to the comparison of the test case.
+type htmlTestCase struct {
+	ID         string
+	Status     string
+	Passed     bool
+	Confidence float64
+	Flaky      bool
+	Detail     htmlCaseDetail
+	Noise      []htmlField
+}
+
+// htmlCaseDetail is the side-by-side diff of a test case.
+type htmlCaseDetail struct {
+	Method              string
+	URL                 string
+	ReqHeaders          []htmlField
+	ReqBody             string
+	Fields              []htmlDiffRow
+	DBAssertionFailures []models.DBAssertionFailure
+}
+
+type htmlField struct {
+	Key   string
+	Value string
+}
+
+// htmlDiffRow is a field of the response, Differs highlights it when the expected and the actual values are not equal.
+type htmlDiffRow struct {
+	Field    string
+	Expected string
+	Actual   string
+	Differs  bool
+}
+
+// the failed test cases are highlighted in red and the passed ones in green, as by the failing and the passing
+// color schemes of the terminal output. The pages have no external assets so that they render offline.
+const htmlReportStyle = `<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ddd; padding: 6px 8px; text-align: left; vertical-align: top; }
+td.value { font-family: monospace; white-space: pre-wrap; word-break: break-all; }
+.badge { padding: 2px 8px; border-radius: 4px; color: #fff; font-size: 0.85em; }
+.pass { background: #2da44e; }
+.fail { background: #cf222e; }
+.flaky { background: #bf8700; }
+tr.differs td.expected { background: #ffebe9; }
+tr.differs td.actual { background: #dafbe1; }
+details.testcase { border-left: 4px solid #2da44e; margin: 0 0 0.5em 0; padding: 4px 8px; }
+details.testcase.failed { border-left-color: #cf222e; background: #fff8f8; }
+details.testcase summary { cursor: pointer; font-family: monospace; }
+</style>`
+
+var htmlIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Keploy test report {{.TestRunID}}</title>
+` + htmlReportStyle + `
+</head>
+<body>
+<h1>Test run {{.TestRunID}}</h1>
+<table>
+<tr><th>Test set</th><th>Status</th><th>Total</th><th>Passed</th><th>Failed</th></tr>
+{{range .TestSets}}<tr>
+<td><a href="{{.Page}}">{{.Name}}</a></td>
+<td><span class="badge {{if .Passed}}pass{{else}}fail{{end}}">{{.Status}}</span></td>
+<td>{{.Total}}</td><td>{{.Success}}</td><td>{{.Failure}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+var htmlTestSetTemplate = template.Must(template.New("testset").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+` + htmlReportStyle + `
+</head>
+<body>
+<p><a href="index.html">&larr; all test sets</a></p>
+<h1>{{.Name}} <span class="badge {{if .Passed}}pass{{else}}fail{{end}}">{{.Status}}</span></h1>
+<table>
+<tr><th>Test case</th><th>Status</th><th>Confidence</th></tr>
+{{range .Tests}}<tr>
+<td><a href="#{{.ID}}">{{.ID}}</a></td>
+<td><span class="badge {{if .Passed}}pass{{else}}fail{{end}}">{{.Status}}</span>{{if .Flaky}} <span class="badge flaky">FLAKY</span>{{end}}</td>
+<td><meter min="0" max="1" low="0.5" high="0.8" optimum="1" value="{{.Confidence}}">{{printf "%.2f" .Confidence}}</meter> {{printf "%.2f" .Confidence}}</td>
+</tr>
+{{end}}</table>
+{{range .Tests}}<details id="{{.ID}}" class="testcase{{if not .Passed}} failed{{end}}"{{if not .Passed}} open{{end}}>
+<summary>{{.ID}} <span class="badge {{if .Passed}}pass{{else}}fail{{end}}">{{.Status}}</span>{{if .Flaky}} <span class="badge flaky">FLAKY</span>{{end}}</summary>
+<h3>Request</h3>
+<table>
+<tr><th>Method</th><td class="value">{{.Detail.Method}}</td></tr>
+<tr><th>URL</th><td class="value">{{.Detail.URL}}</td></tr>
+{{range .Detail.ReqHeaders}}<tr><th>{{.Key}}</th><td class="value">{{.Value}}</td></tr>
+{{end}}{{if .Detail.ReqBody}}<tr><th>Body</th><td class="value">{{.Detail.ReqBody}}</td></tr>
+{{end}}</table>
+<h3>Response <meter min="0" max="1" low="0.5" high="0.8" optimum="1" value="{{.Confidence}}">{{printf "%.2f" .Confidence}}</meter></h3>
+<table>
+<tr><th>Field</th><th>Expected</th><th>Actual</th></tr>
+{{range .Detail.Fields}}<tr{{if .Differs}} class="differs"{{end}}>
+<td>{{.Field}}</td><td class="value expected">{{.Expected}}</td><td class="value actual">{{.Actual}}</td>
+</tr>
+{{end}}</table>
+{{if .Detail.DBAssertionFailures}}<h3>Database assertions</h3>
+<table>
+<tr><th>Query</th><th>Expected</th><th>Actual</th><th>Message</th></tr>
+{{range .Detail.DBAssertionFailures}}<tr class="differs">
+<td class="value">{{.Query}}</td><td class="value expected">{{.Expected}}</td><td class="value actual">{{.Actual}}</td><td>{{.Message}}</td>
+</tr>
+{{end}}</table>
+{{end}}{{if .Noise}}<h3>Noise</h3>
+<table>
+<tr><th>Field</th><th>Regular expressions</th></tr>
+{{range .Noise}}<tr><td class="value">{{.Key}}</td><td class="value">{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}</details>
+{{end}}</body>
+</html>
+`))
+
+// appliedNoise lists the noise of the test case along with the global and the test set noise of the comparison.
+func appliedNoise(testSetNoise map[string]map[string][]string, tcNoise models.Noise) []htmlField {
+	noise := map[string][]string{}
+	for part, fields := range testSetNoise {
+		for field, regexArr := range fields {
+			noise[part+"."+field] = regexArr
+		}
+	}
+	for field, regexArr := range tcNoise {
+		noise[field] = regexArr
+	}
+	fields := make([]htmlField, 0, len(noise))
+	for field, regexArr := range noise {
+		fields = append(fields, htmlField{Key: field, Value: fmt.Sprint(regexArr)})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+	return fields
+}
+
+// GenerateHTMLReport writes an html report of the test run to the output directory, an index.html with all the
+// test sets of the test run and a page per test set with a collapsible side-by-side diff of each of its test cases,
+// the failed ones are expanded.
+func (r *Replayer) GenerateHTMLReport(ctx context.Context, testRunID string, outputDir string) error {
+	testSetIDs, err := r.testDB.GetAllTestSetIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get all test set ids: %w", err)
+	}
+	sort.Strings(testSetIDs)
+
+	err = os.MkdirAll(outputDir, 0o777)
+	if err != nil {
+		return fmt.Errorf("failed to create the html report directory: %w", err)
+	}
+
+	var testSets []htmlTestSet
+	for _, testSetID := range testSetIDs {
+		report, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+		if err != nil {
+			r.logger.Debug("no report found for the test set in the test run", zap.String("test-set", testSetID), zap.String("test-run", testRunID), zap.Error(err))
+			continue
+		}
+		set := htmlTestSet{
+			Name:    testSetID,
+			Status:  report.Status,
+			Passed:  report.Status == string(models.TestSetStatusPassed),
+			Total:   report.Total,
+			Success: report.Success,
+			Failure: report.Failure,
+			Page:    filepath.Base(testSetID) + ".html",
+		}
+		for _, result := range report.Tests {
+			set.Tests = append(set.Tests, htmlTestCase{
+				ID:         result.TestCaseID,
+				Status:     string(result.Status),
+				Passed:     result.Status == models.TestStatusPassed,
+				Confidence: result.Result.ConfidenceScore,
+				Flaky:      result.Flaky,
+				Detail:     newHTMLCaseDetail(result),
+				Noise:      appliedNoise(report.AppliedNoise, result.Noise),
+			})
+		}
+
+		err = writeHTML(filepath.Join(outputDir, set.Page), htmlTestSetTemplate, set)
+		if err != nil {
+			return err
+		}
+		testSets = append(testSets, set)
+	}
+
+	err = writeHTML(filepath.Join(outputDir, "index.html"), htmlIndexTemplate, struct {
+		TestRunID string
+		TestSets  []htmlTestSet
+	}{TestRunID: testRunID, TestSets: testSets})
+	if err != nil {
+		return err
+	}
+	r.logger.Info("html report is generated", zap.String("path", filepath.Join(outputDir, "index.html")))
+	return nil
+}
+
+func writeHTML(path string, tmpl *template.Template, data interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create the html report file %s: %w", path, err)
+	}
+	defer f.Close()
+	err = tmpl.Execute(f, data)
+	if err != nil {
+		return fmt.Errorf("failed to render the html report file %s: %w", path, err)
+	}
+	return nil
+}
+
+// newHTMLCaseDetail builds the side-by-side diff of the test case, the json bodies are compared field by field.
+func newHTMLCaseDetail(result models.TestResult) htmlCaseDetail {
+	detail := htmlCaseDetail{
+		Method:              string(result.Req.Method),
+		URL:                 result.Req.URL,
+		ReqBody:             result.Req.Body,
+		DBAssertionFailures: result.DBAssertionFailures,
+	}
+	for key, value := range result.Req.Header {
+		detail.ReqHeaders = append(detail.ReqHeaders, htmlField{Key: key, Value: value})
+	}
+	sort.Slice(detail.ReqHeaders, func(i, j int) bool {
+		return detail.ReqHeaders[i].Key < detail.ReqHeaders[j].Key
+	})
+
+	status := result.Result.StatusCode
+	detail.Fields = append(detail.Fields, htmlDiffRow{
+		Field:    "status code",
+		Expected: strconv.Itoa(status.Expected),
+		Actual:   strconv.Itoa(status.Actual),
+		Differs:  !status.Normal,
+	})
+	for _, header := range result.Result.HeadersResult {
+		key := header.Expected.Key
+		if key == "" {
+			key = header.Actual.Key
+		}
+		detail.Fields = append(detail.Fields, htmlDiffRow{
+			Field:    "header." + key + headerChange(result.Result.HeadersDiff, key),
+			Expected: fmt.Sprint(header.Expected.Value),
+			Actual:   fmt.Sprint(header.Actual.Value),
+			Differs:  !header.Normal,
+		})
+	}
+	for _, body := range result.Result.BodyResult {
+		detail.Fields = append(detail.Fields, bodyDiffRows(body)...)
+	}
+	if latency := result.Result.LatencyResult; latency != nil {
+		detail.Fields = append(detail.Fields, htmlDiffRow{
+			Field:    "latency",
+			Expected: fmt.Sprintf("<= %dms", latency.BudgetMs),
+			Actual:   fmt.Sprintf("%dms", latency.ActualMs),
+			Differs:  !latency.Normal,
+		})
+	}
+	for _, file := range result.Result.FormFilesResult {
+		detail.Fields = append(detail.Fields, htmlDiffRow{
+			Field:    "form." + file.Name,
+			Expected: fmt.Sprintf("%s (%s) %s", file.Expected.Filename, file.Expected.ContentType, file.Expected.SHA256),
+			Actual:   fmt.Sprintf("%s (%s) %s", file.Actual.Filename, file.Actual.ContentType, file.Actual.SHA256),
+			Differs:  !file.Normal,
+		})
+	}
+	return detail
+}
+
+// headerChange returns how the actual response changed the header, to be appended to the name of its row.
+func headerChange(diff *models.HeadersDiff, key string) string {
+	if diff == nil {
+		return ""
+	}
+	if _, ok := diff.Added[key]; ok {
+		return " (added)"
+	}
+	if _, ok := diff.Removed[key]; ok {
+		return " (removed)"
+	}
+	if _, ok := diff.Modified[key]; ok {
+		return " (modified)"
+	}
+	return ""
+}
+
+// bodyDiffRows returns a row per field of the json bodies, or a single row for the other bodies.
+func bodyDiffRows(body models.BodyResult) []htmlDiffRow {
+	var expected, actual interface{}
+	if body.Type != models.BodyTypeJSON || json.Unmarshal([]byte(body.Expected), &expected) != nil || json.Unmarshal([]byte(body.Actual), &actual) != nil {
+		return []htmlDiffRow{{Field: "body", Expected: body.Expected, Actual: body.Actual, Differs: !body.Normal}}
+	}
+	expFields, actFields := map[string]string{}, map[string]string{}
+	flattenJSON("body", expected, expFields)
+	flattenJSON("body", actual, actFields)
+
+	keys := make([]string, 0, len(expFields))
+	for key := range expFields {
+		keys = append(keys, key)
+	}
+	for key := range actFields {
+		if _, ok := expFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	rows := make([]htmlDiffRow, 0, len(keys))
+	for _, key := range keys {
+		exp, expOk := expFields[key]
+		act, actOk := actFields[key]
+		rows = append(rows, htmlDiffRow{Field: key, Expected: exp, Actual: act, Differs: expOk != actOk || exp != act})
+	}
+	return rows
+}
+
+// flattenJSON flattens the json value into the dot separated paths of its leaves, the array items are indexed.
+func flattenJSON(prefix string, v interface{}, out map[string]string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			out[prefix] = "{}"
+		}
+		for key, item := range val {
+			flattenJSON(prefix+"."+key, item, out)
+		}
+	case []interface{}:
+		if len(val) == 0 {
+			out[prefix] = "[]"
+		}
+		for i, item := range val {
+			flattenJSON(fmt.Sprintf("%s[%d]", prefix, i), item, out)
+		}
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			out[prefix] = fmt.Sprint(val)
+			return
+		}
+		out[prefix] = string(data)
+	}
+}
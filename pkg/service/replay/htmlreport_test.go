@@ -0,0 +1,145 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+)
+
+// fakeTestDB lists the test sets, the other methods of TestDB are not used by the tests.
+type fakeTestDB struct {
+	TestDB
+	testSetIDs []string
+}
+
+func (db *fakeTestDB) GetAllTestSetIDs(_ context.Context) ([]string, error) {
+	return db.testSetIDs, nil
+}
+
+// fakeReportDB returns the reports of the test sets, the other methods of ReportDB are not used by the tests.
+type fakeReportDB struct {
+	ReportDB
+	reports map[string]*models.TestReport
+}
+
+func (db *fakeReportDB) GetReport(_ context.Context, _ string, testSetID string) (*models.TestReport, error) {
+	report, ok := db.reports[testSetID]
+	if !ok {
+		return nil, errors.New("report not found")
+	}
+	return report, nil
+}
+
+// voidElements are the html elements without an end tag.
+var voidElements = map[string]bool{"meta": true, "br": true, "hr": true, "img": true, "input": true, "link": true}
+
+// checkWellFormed fails the test if the elements of the html document are not properly nested and closed.
+func checkWellFormed(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if !strings.HasPrefix(string(data), "<!DOCTYPE html>") {
+		t.Fatalf("%s does not start with the html doctype", path)
+	}
+	var open []string
+	tokenizer := html.NewTokenizer(strings.NewReader(string(data)))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if tokenizer.Err() != io.EOF {
+				t.Fatalf("failed to tokenize %s: %v", path, tokenizer.Err())
+			}
+			if len(open) != 0 {
+				t.Fatalf("%s has unclosed elements %v", path, open)
+			}
+			return string(data)
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			if !voidElements[string(name)] {
+				open = append(open, string(name))
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			if len(open) == 0 || open[len(open)-1] != string(name) {
+				t.Fatalf("%s closes </%s> while %v are open", path, name, open)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+}
+
+func TestGenerateHTMLReportIsWellFormed(t *testing.T) {
+	failed := models.TestResult{
+		TestCaseID: "test-2",
+		Status:     models.TestStatusFailed,
+		Req:        models.HTTPReq{Method: "POST", URL: "http://localhost:8080/users?name=<script>", Header: map[string]string{"Content-Type": "application/json"}, Body: `{"name":"a & b"}`},
+		Noise:      models.Noise{"body.updated_at": {}},
+		Result: models.Result{
+			StatusCode: models.IntResult{Normal: false, Expected: 201, Actual: 500},
+			BodyResult: []models.BodyResult{{Normal: false, Type: models.BodyTypeJSON, Expected: `{"id":1,"tags":["a"]}`, Actual: `{"id":2,"tags":[]}`}},
+		},
+		DBAssertionFailures: []models.DBAssertionFailure{{Query: "SELECT count(*) FROM users", Expected: "1", Actual: "0", Message: "row count differs"}},
+	}
+	passed := models.TestResult{
+		TestCaseID: "test-1",
+		Status:     models.TestStatusPassed,
+		Req:        models.HTTPReq{Method: "GET", URL: "http://localhost:8080/users"},
+		Result:     models.Result{StatusCode: models.IntResult{Normal: true, Expected: 200, Actual: 200}},
+	}
+
+	tests := []struct {
+		name    string
+		reports map[string]*models.TestReport
+		want    []string
+	}{
+		{
+			name:    "no reports",
+			reports: map[string]*models.TestReport{},
+		},
+		{
+			name: "passed and failed test cases",
+			reports: map[string]*models.TestReport{
+				"test-set-0": {Status: string(models.TestSetStatusFailed), Total: 2, Success: 1, Failure: 1, Tests: []models.TestResult{passed, failed}, AppliedNoise: map[string]map[string][]string{"header": {"date": {}}}},
+				"test-set-1": {Status: string(models.TestSetStatusPassed), Total: 1, Success: 1, Tests: []models.TestResult{passed}},
+			},
+			want: []string{`id="test-2" class="testcase failed" open`, "body.updated_at", "header.date", "&lt;script&gt;", "SELECT count(*) FROM users"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Replayer{
+				logger:   zap.NewNop(),
+				config:   &config.Config{},
+				testDB:   &fakeTestDB{testSetIDs: []string{"test-set-1", "test-set-0"}},
+				reportDB: &fakeReportDB{reports: tt.reports},
+			}
+			outputDir := t.TempDir()
+			if err := r.GenerateHTMLReport(context.Background(), "test-run-0", outputDir); err != nil {
+				t.Fatalf("GenerateHTMLReport() error = %v", err)
+			}
+			checkWellFormed(t, filepath.Join(outputDir, "index.html"))
+			var pages string
+			for testSetID := range tt.reports {
+				pages += checkWellFormed(t, filepath.Join(outputDir, testSetID+".html"))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(pages, want) {
+					t.Errorf("the test set pages do not contain %q", want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// IstanbulCoverageDriver reads the istanbul/nyc coverage of the js apps
+	IstanbulCoverageDriver = "istanbul"
+	// istanbulCoverageEndpoint is the endpoint of the app serving its __coverage__ global
+	istanbulCoverageEndpoint = "/__coverage__"
+)
+
+// istanbulCoverage is an istanbul coverage map, the file coverages by their path.
+type istanbulCoverage map[string]map[string]interface{}
+
+// istanbulCollector reads the coverage of the js app after each test case. The coverage of the app is cumulative,
+// so only the latest coverage of each test set is kept and the test sets, which run their own app, are merged.
+type istanbulCollector struct {
+	mu       sync.Mutex
+	client   *http.Client
+	testSets map[string]istanbulCoverage
+}
+
+func newIstanbulCollector(apiTimeout uint64) *istanbulCollector {
+	return &istanbulCollector{
+		client:   &http.Client{Timeout: time.Duration(apiTimeout) * time.Second},
+		testSets: map[string]istanbulCoverage{},
+	}
+}
+
+// collectCoverage reads the coverage of the app serving the test case, if the istanbul coverage driver is used.
+func (r *Replayer) collectCoverage(ctx context.Context, testSetID string, testCase *models.TestCase) {
+	if r.istanbul == nil || testCase.Kind == models.GRPC_EXPORT || testCase.Kind == models.WS {
+		return
+	}
+	err := r.istanbul.collect(ctx, testSetID, testCase.HTTPReq.URL)
+	if err != nil {
+		r.logger.Warn("failed to read the istanbul coverage of the app", zap.String("testcase", testCase.Name), zap.String("test-set", testSetID), zap.Error(err))
+	}
+}
+
+// collect reads the __coverage__ global of the app at the host of the url of the test case.
+func (c *istanbulCollector) collect(ctx context.Context, testSetID, tcURL string) error {
+	u, err := url.Parse(tcURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse the url of the test case: %w", err)
+	}
+	u.Path = istanbulCoverageEndpoint
+	u.RawPath = ""
+	u.RawQuery = ""
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create the coverage request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request the coverage: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d of the coverage endpoint %s", resp.StatusCode, istanbulCoverageEndpoint)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the coverage: %w", err)
+	}
+	var coverage istanbulCoverage
+	if err := json.Unmarshal(data, &coverage); err != nil {
+		return fmt.Errorf("failed to unmarshal the istanbul coverage map: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.testSets[testSetID] = coverage
+	return nil
+}
+
+// write merges the coverage of the test sets and writes it as the coverage-final.json of nyc to the path.
+func (c *istanbulCollector) write(path string) error {
+	c.mu.Lock()
+	merged := istanbulCoverage{}
+	for _, coverage := range c.testSets {
+		mergeIstanbulCoverage(merged, coverage)
+	}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the istanbul coverage map: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return fmt.Errorf("failed to create the coverage directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o777); err != nil {
+		return fmt.Errorf("failed to write the istanbul coverage map: %w", err)
+	}
+	return nil
+}
+
+// mergeIstanbulCoverage merges the coverage into the merged coverage as istanbul-lib-coverage does, the hit counts
+// of the statements, the functions and the branches are added and their locations are united.
+func mergeIstanbulCoverage(merged, coverage istanbulCoverage) {
+	for path, file := range coverage {
+		into, ok := merged[path]
+		if !ok {
+			merged[path] = file
+			continue
+		}
+		for _, locations := range []string{"statementMap", "fnMap", "branchMap"} {
+			from, _ := file[locations].(map[string]interface{})
+			to, _ := into[locations].(map[string]interface{})
+			if to == nil {
+				to = map[string]interface{}{}
+				into[locations] = to
+			}
+			for id, location := range from {
+				if _, ok := to[id]; !ok {
+					to[id] = location
+				}
+			}
+		}
+		for _, counts := range []string{"s", "f"} {
+			from, _ := file[counts].(map[string]interface{})
+			to, _ := into[counts].(map[string]interface{})
+			if to == nil {
+				to = map[string]interface{}{}
+				into[counts] = to
+			}
+			for id, hits := range from {
+				to[id] = istanbulHits(to[id]) + istanbulHits(hits)
+			}
+		}
+		from, _ := file["b"].(map[string]interface{})
+		to, _ := into["b"].(map[string]interface{})
+		if to == nil {
+			to = map[string]interface{}{}
+			into["b"] = to
+		}
+		for id, hits := range from {
+			fromHits, _ := hits.([]interface{})
+			toHits, _ := to[id].([]interface{})
+			sum := make([]interface{}, max(len(fromHits), len(toHits)))
+			for i := range sum {
+				var a, b interface{}
+				if i < len(fromHits) {
+					a = fromHits[i]
+				}
+				if i < len(toHits) {
+					b = toHits[i]
+				}
+				sum[i] = istanbulHits(a) + istanbulHits(b)
+			}
+			to[id] = sum
+		}
+	}
+}
+
+func istanbulHits(v interface{}) float64 {
+	hits, _ := v.(float64)
+	return hits
+}
@@ -0,0 +1,135 @@
+//go:build linux
+
+package replay
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// junitTestSuites is the root element of the JUnit XML report, aggregating all the test sets of the test run.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	seconds   int64
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+// junitReporter collects a JUnit test suite per test set and writes them all at the end of the test run.
+type junitReporter struct {
+	mu     sync.Mutex
+	suites []junitTestSuite
+}
+
+// addTestSet adds the test suite of the finalized test set.
+func (j *junitReporter) addTestSet(testSetID string, results []models.TestResult) {
+	suite := junitTestSuite{Name: testSetID, Tests: len(results)}
+	var suiteTime int64
+	for _, result := range results {
+		duration := result.Completed - result.Started
+		suiteTime += duration
+		tc := junitTestCase{
+			Name:      result.TestCaseID,
+			ClassName: testSetID,
+			Time:      fmt.Sprintf("%d", duration),
+		}
+		if result.Status == models.TestStatusFailed {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: "response of the testcase did not match the recorded response",
+				Type:    "ResponseMismatch",
+				Content: resultDiff(result.Result),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Time = fmt.Sprintf("%d", suiteTime)
+	suite.seconds = suiteTime
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.suites = append(j.suites, suite)
+}
+
+// write writes the collected test suites to the file at the path. The xml encoder escapes the response
+// bodies in the failure messages, including the characters which are not allowed in xml.
+func (j *junitReporter) write(path, testRunID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	report := junitTestSuites{Name: testRunID, Suites: j.suites}
+	var totalTime int64
+	for _, suite := range j.suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		totalTime += suite.seconds
+	}
+	report.Time = fmt.Sprintf("%d", totalTime)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the junit report: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o777); err != nil {
+			return fmt.Errorf("failed to create the junit report directory: %w", err)
+		}
+	}
+	err = os.WriteFile(path, append([]byte(xml.Header), data...), 0o777)
+	if err != nil {
+		return fmt.Errorf("failed to write the junit report: %w", err)
+	}
+	return nil
+}
+
+// resultDiff describes the mismatched status code, headers and body of the test result.
+func resultDiff(result models.Result) string {
+	var sb strings.Builder
+	if !result.StatusCode.Normal {
+		sb.WriteString(fmt.Sprintf("status code: expected %d, actual %d\n", result.StatusCode.Expected, result.StatusCode.Actual))
+	}
+	for _, header := range result.HeadersResult {
+		if !header.Normal {
+			sb.WriteString(fmt.Sprintf("header %s: expected %v, actual %v\n", header.Expected.Key, header.Expected.Value, header.Actual.Value))
+		}
+	}
+	for _, body := range result.BodyResult {
+		if !body.Normal {
+			sb.WriteString(fmt.Sprintf("body:\nexpected: %s\nactual: %s\n", body.Expected, body.Actual))
+		}
+	}
+	if latency := result.LatencyResult; latency != nil && !latency.Normal {
+		sb.WriteString(fmt.Sprintf("latency: budget %dms, actual %dms\n", latency.BudgetMs, latency.ActualMs))
+	}
+	return sb.String()
+}
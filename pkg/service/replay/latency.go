@@ -0,0 +1,45 @@
+//go:build linux
+
+package replay
+
+import (
+	"strings"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// latencyBudget returns the latency budget of the test case, the budget of the test case overrides the budget
+// of its test set which overrides the default budget. Zero means the latency of the test case is not asserted.
+func (r *Replayer) latencyBudget(testSetID, testCaseID string) time.Duration {
+	budgets := r.config.Test.LatencyBudgets
+	// the keys of the config maps are lower cased
+	for _, key := range []string{testSetID + "/" + testCaseID, testSetID} {
+		if budget, ok := budgets[key]; ok {
+			return budget
+		}
+		if budget, ok := budgets[strings.ToLower(key)]; ok {
+			return budget
+		}
+	}
+	return r.config.Test.LatencyBudget
+}
+
+// checkLatency records the latency of the response against the budget of the test case in the result,
+// it returns false when the budget is exceeded.
+func (r *Replayer) checkLatency(testSetID string, testCase *models.TestCase, latency time.Duration, result *models.Result) bool {
+	budget := r.latencyBudget(testSetID, testCase.Name)
+	if budget <= 0 || result == nil {
+		return true
+	}
+	result.LatencyResult = &models.LatencyResult{
+		Normal:   latency <= budget,
+		BudgetMs: budget.Milliseconds(),
+		ActualMs: latency.Milliseconds(),
+	}
+	if !result.LatencyResult.Normal {
+		r.logger.Warn("the response of the test case exceeded its latency budget", zap.String("testcase", testCase.Name), zap.String("test-set", testSetID), zap.Duration("latency", latency), zap.Duration("budget", budget))
+	}
+	return result.LatencyResult.Normal
+}
@@ -4,14 +4,20 @@
 package replay
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"bytes"
 	"os"
@@ -24,6 +30,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	"github.com/yudai/gojsondiff"
 	"github.com/yudai/gojsondiff/formatter"
+	"go.keploy.io/server/v2/config"
 	"go.keploy.io/server/v2/pkg"
 	"go.keploy.io/server/v2/pkg/models"
 	"go.keploy.io/server/v2/utils"
@@ -41,7 +48,7 @@ type JSONComparisonResult struct {
 	differences []string // Lists the keys or indices of values that are not the same
 }
 
-func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map[string]map[string][]string, ignoreOrdering bool, logger *zap.Logger) (bool, *models.Result) {
+func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map[string]map[string][]string, ignoreOrdering bool, tolerance ValueTolerance, base64JSONFields []string, logger *zap.Logger) (bool, *models.Result) {
 	bodyType := models.BodyTypePlain
 	if json.Valid([]byte(actualResponse.Body)) {
 		bodyType = models.BodyTypeJSON
@@ -95,8 +102,20 @@ func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map
 		if err != nil {
 			return false, res
 		}
+		if len(base64JSONFields) != 0 && validatedJSON.isIdentical {
+			err = DecodeBase64JSONFields(validatedJSON.expected, base64JSONFields)
+			if err != nil {
+				utils.LogError(logger, err, "failed to decode the base64 encoded json field of the expected response", zap.String("testcase", tc.Name))
+				return false, res
+			}
+			err = DecodeBase64JSONFields(validatedJSON.actual, base64JSONFields)
+			if err != nil {
+				utils.LogError(logger, err, "failed to decode the base64 encoded json field of the actual response", zap.String("testcase", tc.Name))
+				return false, res
+			}
+		}
 		if validatedJSON.isIdentical {
-			jsonComparisonResult, err = JSONDiffWithNoiseControl(validatedJSON, bodyNoise, ignoreOrdering)
+			jsonComparisonResult, err = JSONDiffWithNoiseControl(validatedJSON, bodyNoise, ignoreOrdering, tolerance)
 			pass = jsonComparisonResult.isExact
 			if err != nil {
 				return false, res
@@ -108,6 +127,16 @@ func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map
 		// debug log for cleanExp and cleanAct
 		logger.Debug("cleanExp", zap.Any("", cleanExp))
 		logger.Debug("cleanAct", zap.Any("", cleanAct))
+	} else if !Contains(MapToArray(noise), "body") && isXMLContentType(tc.HTTPResp.Header) {
+		res.BodyResult[0].Type = models.BodyTypeXML
+		equal, err := XMLEqual(tc.HTTPResp.Body, actualResponse.Body, bodyNoise, ignoreOrdering)
+		if err != nil {
+			logger.Debug("failed to compare the xml bodies structurally, comparing them as text", zap.String("testcase", tc.Name), zap.Error(err))
+			equal = tc.HTTPResp.Body == actualResponse.Body
+		}
+		if !equal {
+			pass = false
+		}
 	} else {
 		if !Contains(MapToArray(noise), "body") && tc.HTTPResp.Body != actualResponse.Body {
 			pass = false
@@ -122,6 +151,7 @@ func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map
 	}
 
 	res.HeadersResult = *hRes
+	res.HeadersDiff = DiffHeaders(res.HeadersResult)
 	if tc.HTTPResp.StatusCode == actualResponse.StatusCode {
 		res.StatusCode.Normal = true
 	} else {
@@ -129,6 +159,11 @@ func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map
 		pass = false
 	}
 
+	res.ConfidenceScore = confidenceScore(res, Contains(MapToArray(noise), "body"), bodyNoise, headerNoise)
+	if res.ConfidenceScore < LowConfidenceScore {
+		logger.Warn("low confidence in the test result, most of the response fields are noised out. Please review the noise configuration", zap.String("testcase", tc.Name), zap.Float64("confidence score", res.ConfidenceScore))
+	}
+
 	if !pass {
 		logDiffs := NewDiffsPrinter(tc.Name)
 
@@ -144,23 +179,11 @@ func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map
 			logDiffs.PushStatusDiff(fmt.Sprint(res.StatusCode.Expected), fmt.Sprint(res.StatusCode.Actual))
 		}
 
-		var (
-			actualHeader   = map[string][]string{}
-			expectedHeader = map[string][]string{}
-			unmatched      = true
-		)
-
-		for _, j := range res.HeadersResult {
-			if !j.Normal {
-				unmatched = false
-				actualHeader[j.Actual.Key] = j.Actual.Value
-				expectedHeader[j.Expected.Key] = j.Expected.Value
-			}
-		}
-
-		if !unmatched {
-			for i, j := range expectedHeader {
-				logDiffs.PushHeaderDiff(fmt.Sprint(j), fmt.Sprint(actualHeader[i]), i, headerNoise)
+		if diff := res.HeadersDiff; diff != nil {
+			for _, headers := range []map[string][2]string{diff.Added, diff.Removed, diff.Modified} {
+				for key, values := range headers {
+					logDiffs.PushHeaderDiff(values[0], values[1], key, headerNoise)
+				}
 			}
 		}
 
@@ -205,6 +228,105 @@ func match(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig map
 	return pass, res
 }
 
+// LowConfidenceScore is the confidence score below which a test result is reported as unreliable.
+const LowConfidenceScore = 0.5
+
+// confidenceScore computes the ratio of the matched fields (status code, headers and body fields)
+// to all the fields of the expected response. Noisy fields are counted as compared but not matched.
+func confidenceScore(res *models.Result, bodyNoisy bool, bodyNoise, headerNoise map[string][]string) float64 {
+	total, matched := 1, 0
+	if res.StatusCode.Normal {
+		matched++
+	}
+
+	for _, h := range res.HeadersResult {
+		total++
+		if _, noisy := CheckStringExist(strings.ToLower(h.Expected.Key), headerNoise); h.Normal && !noisy {
+			matched++
+		}
+	}
+
+	for _, b := range res.BodyResult {
+		if bodyNoisy || b.Type != models.BodyTypeJSON {
+			total++
+			if !bodyNoisy && b.Expected == b.Actual {
+				matched++
+			}
+			continue
+		}
+		var exp, act interface{}
+		if json.Unmarshal([]byte(b.Expected), &exp) != nil || json.Unmarshal([]byte(b.Actual), &act) != nil {
+			total++
+			continue
+		}
+		expFields, actFields := Flatten(exp), Flatten(act)
+		for key, expVal := range expFields {
+			total++
+			if _, noisy := CheckStringExist(strings.ToLower(key), bodyNoise); noisy {
+				continue
+			}
+			if actVal, ok := actFields[key]; ok && sameValues(expVal, actVal) {
+				matched++
+			}
+		}
+	}
+	return float64(matched) / float64(total)
+}
+
+// sameValues compares the flattened values irrespective of their order in the arrays.
+func sameValues(exp, act []string) bool {
+	if len(exp) != len(act) {
+		return false
+	}
+	e, a := append([]string{}, exp...), append([]string{}, act...)
+	sort.Strings(e)
+	sort.Strings(a)
+	return reflect.DeepEqual(e, a)
+}
+
+// CompareFormFiles compares the multipart files by their field name, content type and the sha256 hash of their data.
+// Files whose field name is noisy are not compared.
+func CompareFormFiles(expected, actual []models.FormFile, noise map[string][]string) (bool, []models.FormFileResult) {
+	pass := true
+	actualFiles := make(map[string]models.FormFile, len(actual))
+	for _, file := range actual {
+		actualFiles[file.Name] = file
+	}
+
+	var results []models.FormFileResult
+	for _, exp := range expected {
+		result := models.FormFileResult{
+			Name:     exp.Name,
+			Expected: formFileDigest(exp),
+		}
+		act, found := actualFiles[exp.Name]
+		if found {
+			result.Actual = formFileDigest(act)
+		}
+		if _, noisy := CheckStringExist(strings.ToLower(exp.Name), noise); noisy {
+			result.Normal = true
+		} else {
+			result.Normal = found && result.Expected.ContentType == result.Actual.ContentType && result.Expected.SHA256 == result.Actual.SHA256
+		}
+		pass = pass && result.Normal
+		results = append(results, result)
+	}
+	return pass, results
+}
+
+func formFileDigest(file models.FormFile) models.FormFileDigest {
+	sum := sha256.Sum256(file.Data)
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return models.FormFileDigest{
+		Filename:    file.Filename,
+		ContentType: contentType,
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+}
+
 func FlattenHTTPResponse(h http.Header, body string) (map[string][]string, error) {
 	m := map[string][]string{}
 	for k, v := range h {
@@ -250,9 +372,12 @@ func InterfaceToString(val interface{}) string {
 	}
 }
 
-func JSONDiffWithNoiseControl(validatedJSON ValidatedJSON, noise map[string][]string, ignoreOrdering bool) (JSONComparisonResult, error) {
+// JSONDiffWithNoiseControl compares the validated json with the noise, the non-integral numbers are considered
+// equal when they are within the absolute or the relative float tolerance, the tolerances of 0 compare them exactly.
+// The semver fields are considered equal when their versions differ only in the tolerated part.
+func JSONDiffWithNoiseControl(validatedJSON ValidatedJSON, noise map[string][]string, ignoreOrdering bool, tolerance ValueTolerance) (JSONComparisonResult, error) {
 	var matchJSONComparisonResult JSONComparisonResult
-	matchJSONComparisonResult, err := matchJSONWithNoiseHandling("", validatedJSON.expected, validatedJSON.actual, noise, ignoreOrdering)
+	matchJSONComparisonResult, err := matchJSONWithNoiseHandling("", validatedJSON.expected, validatedJSON.actual, noise, ignoreOrdering, tolerance)
 	if err != nil {
 		return matchJSONComparisonResult, err
 	}
@@ -260,6 +385,77 @@ func JSONDiffWithNoiseControl(validatedJSON ValidatedJSON, noise map[string][]st
 	return matchJSONComparisonResult, nil
 }
 
+// DecodeBase64JSONFields replaces the string values found at the given dot separated
+// paths of the json object with their base64 decoded json value, so that they can be
+// compared structurally (with noise) instead of as opaque strings.
+// Paths which are not present in the object are ignored.
+func DecodeBase64JSONFields(obj interface{}, fields []string) error {
+	for _, field := range fields {
+		keys := strings.Split(field, ".")
+		parent, ok := obj.(map[string]interface{})
+		for i := 0; ok && i < len(keys)-1; i++ {
+			parent, ok = lookupKey(parent, keys[i]).(map[string]interface{})
+		}
+		if !ok {
+			continue
+		}
+		key, found := findKey(parent, keys[len(keys)-1])
+		if !found {
+			continue
+		}
+		encoded, ok := parent[key].(string)
+		if !ok {
+			return fmt.Errorf("field %q is not a string, expected base64 encoded json", field)
+		}
+		decoded, err := decodeBase64(encoded)
+		if err != nil {
+			return fmt.Errorf("failed to base64 decode field %q: %w", field, err)
+		}
+		var value interface{}
+		if err := json.Unmarshal(decoded, &value); err != nil {
+			return fmt.Errorf("decoded value of field %q is not a valid json: %w", field, err)
+		}
+		parent[key] = value
+	}
+	return nil
+}
+
+// decodeBase64 decodes both the standard and the url-safe (JWT style) base64 encodings, with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	encodings := []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding}
+	var err error
+	for _, enc := range encodings {
+		var decoded []byte
+		decoded, err = enc.DecodeString(s)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+	return nil, err
+}
+
+// findKey returns the actual key of the map matching the given key case-insensitively,
+// as the noise and field configurations are case-insensitive.
+func findKey(m map[string]interface{}, key string) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func lookupKey(m map[string]interface{}, key string) interface{} {
+	k, ok := findKey(m, key)
+	if !ok {
+		return nil
+	}
+	return m[k]
+}
+
 func ValidateAndMarshalJSON(log *zap.Logger, exp, act *string) (ValidatedJSON, error) {
 	var validatedJSON ValidatedJSON
 	expected, err := UnmarshallJSON(*exp, log)
@@ -291,8 +487,21 @@ func ValidateAndMarshalJSON(log *zap.Logger, exp, act *string) (ValidatedJSON, e
 }
 
 // matchJSONWithNoiseHandling returns strcut if expected and actual JSON objects matches(are equal) and in exact order(isExact).
-func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseMap map[string][]string, ignoreOrdering bool) (JSONComparisonResult, error) {
+func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseMap map[string][]string, ignoreOrdering bool, tolerance ValueTolerance) (JSONComparisonResult, error) {
 	var matchJSONComparisonResult JSONComparisonResult
+	// the expected value can be a type placeholder (e.g. {{type:string}}) asserting only the json type of the actual value
+	if expectedType, ok := typePlaceholder(expected); ok {
+		if expectedType == "any" || expectedType == jsonType(actual) {
+			matchJSONComparisonResult.matches = true
+			matchJSONComparisonResult.isExact = true
+		}
+		return matchJSONComparisonResult, nil
+	}
+	if typeCoercedEqual(key, expected, actual, tolerance) {
+		matchJSONComparisonResult.matches = true
+		matchJSONComparisonResult.isExact = true
+		return matchJSONComparisonResult, nil
+	}
 	if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
 		return matchJSONComparisonResult, errors.New("type not matched")
 	}
@@ -312,7 +521,7 @@ func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseM
 		if isNoisy && len(regexArr) != 0 {
 			isNoisy, _ = MatchesAnyRegex(InterfaceToString(expected), regexArr)
 		}
-		if expected != actual && !isNoisy {
+		if expected != actual && !isNoisy && !withinTolerance(expected, actual, tolerance) && !semverCompatible(key, expected, actual, tolerance) {
 			return matchJSONComparisonResult, nil
 		}
 
@@ -338,7 +547,7 @@ func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseM
 			if !ok {
 				return matchJSONComparisonResult, nil
 			}
-			if valueMatchJSONComparisonResult, er := matchJSONWithNoiseHandling(strings.ToLower(prefix+k), v, val, noiseMap, ignoreOrdering); !valueMatchJSONComparisonResult.matches || er != nil {
+			if valueMatchJSONComparisonResult, er := matchJSONWithNoiseHandling(strings.ToLower(prefix+k), v, val, noiseMap, ignoreOrdering, tolerance); !valueMatchJSONComparisonResult.matches || er != nil {
 				return valueMatchJSONComparisonResult, nil
 			} else if !valueMatchJSONComparisonResult.isExact {
 				isExact = false
@@ -356,7 +565,7 @@ func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseM
 		// checks if there is a key which is not present in expMap but present in actMap.
 		for k := range actMap {
 			_, ok := expMap[k]
-			if !ok {
+			if !ok && !tolerance.IgnoreExtraFields {
 				return matchJSONComparisonResult, nil
 			}
 		}
@@ -378,7 +587,7 @@ func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseM
 		for i := 0; i < expSlice.Len(); i++ {
 			matched := false
 			for j := 0; j < actSlice.Len(); j++ {
-				if valMatchJSONComparisonResult, err := matchJSONWithNoiseHandling(key, expSlice.Index(i).Interface(), actSlice.Index(j).Interface(), noiseMap, ignoreOrdering); err == nil && valMatchJSONComparisonResult.matches {
+				if valMatchJSONComparisonResult, err := matchJSONWithNoiseHandling(key, expSlice.Index(i).Interface(), actSlice.Index(j).Interface(), noiseMap, ignoreOrdering, tolerance); err == nil && valMatchJSONComparisonResult.matches {
 					if !valMatchJSONComparisonResult.isExact {
 						for _, val := range valMatchJSONComparisonResult.differences {
 							prefixedVal := key + "[" + fmt.Sprint(j) + "]." + val // Prefix the value
@@ -403,7 +612,7 @@ func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseM
 		}
 		if !ignoreOrdering {
 			for i := 0; i < expSlice.Len(); i++ {
-				if valMatchJSONComparisonResult, er := matchJSONWithNoiseHandling(key, expSlice.Index(i).Interface(), actSlice.Index(i).Interface(), noiseMap, ignoreOrdering); er != nil || !valMatchJSONComparisonResult.isExact {
+				if valMatchJSONComparisonResult, er := matchJSONWithNoiseHandling(key, expSlice.Index(i).Interface(), actSlice.Index(i).Interface(), noiseMap, ignoreOrdering, tolerance); er != nil || !valMatchJSONComparisonResult.isExact {
 					isExact = false
 					break
 				}
@@ -421,6 +630,56 @@ func matchJSONWithNoiseHandling(key string, expected, actual interface{}, noiseM
 	return matchJSONComparisonResult, nil
 }
 
+// withinTolerance checks whether the numbers differ by at most the absolute tolerance, or by at most the relative
+// tolerance times the larger of them. Integral numbers (e.g. ids) are always compared exactly.
+func withinTolerance(expected, actual interface{}, tolerance ValueTolerance) bool {
+	exp, ok := expected.(float64)
+	if !ok || (tolerance.FloatAbs <= 0 && tolerance.FloatRel <= 0) {
+		return false
+	}
+	act, ok := actual.(float64)
+	if !ok || (exp == math.Trunc(exp) && act == math.Trunc(act)) {
+		return false
+	}
+	diff := math.Abs(exp - act)
+	return diff <= tolerance.FloatAbs || diff <= tolerance.FloatRel*math.Max(math.Abs(exp), math.Abs(act))
+}
+
+var typePlaceholderRegex = regexp.MustCompile(`^\{\{\s*type:\s*(string|number|boolean|array|object|null|any)\s*\}\}$`)
+
+// typePlaceholder returns the json type asserted by the value if it is a type placeholder like {{type:number}}.
+func typePlaceholder(val interface{}) (string, bool) {
+	s, ok := val.(string)
+	if !ok {
+		return "", false
+	}
+	m := typePlaceholderRegex.FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// jsonType returns the json type name of the unmarshalled value.
+func jsonType(val interface{}) string {
+	switch val.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	default:
+		return reflect.TypeOf(val).String()
+	}
+}
+
 // MAX_LINE_LENGTH is chars PER expected/actual string. Can be changed no problem
 const MAX_LINE_LENGTH = 50
 
@@ -486,7 +745,9 @@ func (d *DiffsPrinter) Render() error {
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetAutoWrapText(false)
 	table.SetHeader([]string{fmt.Sprintf("Diffs %v", d.testCase)})
-	table.SetHeaderColor(tablewriter.Colors{tablewriter.FgHiRedColor})
+	if !models.IsColorDisabled() {
+		table.SetHeaderColor(tablewriter.Colors{tablewriter.FgHiRedColor})
+	}
 	table.SetAlignment(tablewriter.ALIGN_CENTER)
 
 	for _, e := range diffs {
@@ -765,6 +1026,42 @@ func Contains(elems []string, v string) bool {
 	return false
 }
 
+// DiffHeaders groups the unmatched headers of the header results into the headers added to, removed from or
+// modified in the actual response, it returns nil when all the headers match.
+func DiffHeaders(results []models.HeaderResult) *models.HeadersDiff {
+	var diff *models.HeadersDiff
+	for _, header := range results {
+		if header.Normal {
+			continue
+		}
+		if diff == nil {
+			diff = &models.HeadersDiff{}
+		}
+		key := header.Expected.Key
+		if key == "" {
+			key = header.Actual.Key
+		}
+		values := [2]string{strings.Join(header.Expected.Value, ", "), strings.Join(header.Actual.Value, ", ")}
+		switch {
+		case header.Expected.Value == nil:
+			diff.Added = setHeaderDiff(diff.Added, key, values)
+		case header.Actual.Value == nil:
+			diff.Removed = setHeaderDiff(diff.Removed, key, values)
+		default:
+			diff.Modified = setHeaderDiff(diff.Modified, key, values)
+		}
+	}
+	return diff
+}
+
+func setHeaderDiff(headers map[string][2]string, key string, values [2]string) map[string][2]string {
+	if headers == nil {
+		headers = map[string][2]string{}
+	}
+	headers[key] = values
+	return headers
+}
+
 func checkKey(res *[]models.HeaderResult, key string) bool {
 	for _, v := range *res {
 		if key == v.Expected.Key {
@@ -911,6 +1208,17 @@ func CheckStringExist(s string, mp map[string][]string) ([]string, bool) {
 	if val, ok := mp[s]; ok {
 		return val, ok
 	}
+	// the regex noise fields match any field whose path matches the regular expression
+	for field, val := range mp {
+		pattern, ok := strings.CutPrefix(field, config.NoiseRegexPrefix)
+		if !ok {
+			continue
+		}
+		re, err := noiseRegex(pattern)
+		if err == nil && re.MatchString(s) {
+			return val, true
+		}
+	}
 	ok, val := MatchesAnyRegex(s, MapToArray(mp))
 	if ok {
 		return mp[val], ok
@@ -918,6 +1226,21 @@ func CheckStringExist(s string, mp map[string][]string) ([]string, bool) {
 	return []string{}, false
 }
 
+var noiseRegexCache sync.Map
+
+// noiseRegex compiles the regular expression of the regex noise field once, as it is matched against every field.
+func noiseRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := noiseRegexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	noiseRegexCache.Store(pattern, re)
+	return re, nil
+}
+
 func MatchesAnyRegex(str string, regexArray []string) (bool, string) {
 	for _, pattern := range regexArray {
 		re := regexp.MustCompile(pattern)
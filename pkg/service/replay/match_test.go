@@ -0,0 +1,239 @@
+//go:build linux
+
+package replay
+
+import (
+	"reflect"
+	"testing"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// jsonResp returns a 200 response with the json body.
+func jsonResp(body string) models.HTTPResp {
+	return models.HTTPResp{
+		StatusCode: 200,
+		Header:     map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+	}
+}
+
+func TestMatchComparesTheFloatsWithinTheTolerances(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  string
+		actual    string
+		tolerance ValueTolerance
+		want      bool
+	}{
+		{
+			name:     "no tolerance",
+			expected: `{"price":19.99}`,
+			actual:   `{"price":19.990001}`,
+			want:     false,
+		},
+		{
+			name:      "within the absolute tolerance",
+			expected:  `{"price":19.99}`,
+			actual:    `{"price":19.990001}`,
+			tolerance: ValueTolerance{FloatAbs: 0.001},
+			want:      true,
+		},
+		{
+			name:      "the absolute tolerance is not relative",
+			expected:  `{"price":1000.5}`,
+			actual:    `{"price":1009}`,
+			tolerance: ValueTolerance{FloatAbs: 0.01},
+			want:      false,
+		},
+		{
+			name:      "within the relative tolerance",
+			expected:  `{"price":1000.5}`,
+			actual:    `{"price":1009}`,
+			tolerance: ValueTolerance{FloatRel: 0.01},
+			want:      true,
+		},
+		{
+			name:      "the relative tolerance is not absolute",
+			expected:  `{"lat":0.0001}`,
+			actual:    `{"lat":0.0002}`,
+			tolerance: ValueTolerance{FloatRel: 0.01},
+			want:      false,
+		},
+		{
+			name:      "integral ids are compared exactly",
+			expected:  `{"id":1000}`,
+			actual:    `{"id":1001}`,
+			tolerance: ValueTolerance{FloatAbs: 10, FloatRel: 0.1},
+			want:      false,
+		},
+		{
+			name:      "nested arrays of floats within the tolerance",
+			expected:  `{"route":{"points":[[52.520008,13.404954],[48.856613,2.352222]]}}`,
+			actual:    `{"route":{"points":[[52.520009,13.404953],[48.856614,2.352221]]}}`,
+			tolerance: ValueTolerance{FloatAbs: 0.00001},
+			want:      true,
+		},
+		{
+			name:      "nested arrays of floats with one float out of the tolerance",
+			expected:  `{"route":{"points":[[52.520008,13.404954],[48.856613,2.352222]]}}`,
+			actual:    `{"route":{"points":[[52.520008,13.404954],[48.856613,2.362222]]}}`,
+			tolerance: ValueTolerance{FloatAbs: 0.00001},
+			want:      false,
+		},
+		{
+			name:      "nested arrays of objects with floats",
+			expected:  `{"items":[{"id":1,"prices":[9.99,19.99]},{"id":2,"prices":[4.5]}]}`,
+			actual:    `{"items":[{"id":1,"prices":[9.990000001,19.99]},{"id":2,"prices":[4.500000001]}]}`,
+			tolerance: ValueTolerance{FloatRel: 1e-6},
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &models.TestCase{Name: "test-1", HTTPResp: jsonResp(tt.expected)}
+			actual := jsonResp(tt.actual)
+			pass, res := match(tc, &actual, map[string]map[string][]string{}, false, tt.tolerance, nil, zap.NewNop())
+			if pass != tt.want {
+				t.Fatalf("match() = %v, want %v, body result: %+v", pass, tt.want, res.BodyResult)
+			}
+		})
+	}
+}
+
+func TestMatchIgnoresTheExtraFields(t *testing.T) {
+	tests := []struct {
+		name              string
+		expected          string
+		actual            string
+		ignoreExtraFields bool
+		want              bool
+	}{
+		{
+			name:     "extra field without the option",
+			expected: `{"id":1}`,
+			actual:   `{"id":1,"name":"keploy"}`,
+			want:     false,
+		},
+		{
+			name:              "extra top-level field",
+			expected:          `{"id":1}`,
+			actual:            `{"id":1,"name":"keploy"}`,
+			ignoreExtraFields: true,
+			want:              true,
+		},
+		{
+			name:              "deeply nested extra fields",
+			expected:          `{"user":{"profile":{"address":{"city":"Berlin"}}}}`,
+			actual:            `{"user":{"profile":{"address":{"city":"Berlin","zip":"10115","geo":{"lat":52.52}},"avatar":"a.png"},"roles":["admin"]},"meta":{"version":2}}`,
+			ignoreExtraFields: true,
+			want:              true,
+		},
+		{
+			name:              "extra fields of the objects of nested arrays",
+			expected:          `{"orders":[{"id":1,"items":[{"sku":"a"},{"sku":"b"}]}]}`,
+			actual:            `{"orders":[{"id":1,"status":"paid","items":[{"sku":"a","qty":1},{"sku":"b","qty":2,"discount":{"code":"x"}}]}]}`,
+			ignoreExtraFields: true,
+			want:              true,
+		},
+		{
+			name:              "changed deeply nested field next to the extra fields",
+			expected:          `{"user":{"profile":{"address":{"city":"Berlin"}}}}`,
+			actual:            `{"user":{"profile":{"address":{"city":"Munich","zip":"80331"}}}}`,
+			ignoreExtraFields: true,
+			want:              false,
+		},
+		{
+			name:              "changed field of the objects of nested arrays",
+			expected:          `{"orders":[{"id":1,"items":[{"sku":"a"},{"sku":"b"}]}]}`,
+			actual:            `{"orders":[{"id":1,"items":[{"sku":"a","qty":1},{"sku":"c","qty":2}]}]}`,
+			ignoreExtraFields: true,
+			want:              false,
+		},
+		{
+			name:              "missing nested field",
+			expected:          `{"user":{"profile":{"address":{"city":"Berlin","zip":"10115"}}}}`,
+			actual:            `{"user":{"profile":{"address":{"city":"Berlin"},"avatar":"a.png"}}}`,
+			ignoreExtraFields: true,
+			want:              false,
+		},
+		{
+			name:              "extra array element",
+			expected:          `{"items":[{"sku":"a"}]}`,
+			actual:            `{"items":[{"sku":"a"},{"sku":"b"}]}`,
+			ignoreExtraFields: true,
+			want:              false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &models.TestCase{Name: "test-1", HTTPResp: jsonResp(tt.expected)}
+			actual := jsonResp(tt.actual)
+			tolerance := ValueTolerance{IgnoreExtraFields: tt.ignoreExtraFields}
+			pass, res := match(tc, &actual, map[string]map[string][]string{}, false, tolerance, nil, zap.NewNop())
+			if pass != tt.want {
+				t.Fatalf("match() = %v, want %v, body result: %+v", pass, tt.want, res.BodyResult)
+			}
+		})
+	}
+}
+
+func TestMatchDiffsTheHeaders(t *testing.T) {
+	expected := map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc"}
+
+	tests := []struct {
+		name   string
+		actual map[string]string
+		want   *models.HeadersDiff
+	}{
+		{
+			name:   "matching headers",
+			actual: map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc"},
+			want:   nil,
+		},
+		{
+			name:   "changed content type",
+			actual: map[string]string{"Content-Type": "text/plain", "X-Request-Id": "abc"},
+			want: &models.HeadersDiff{
+				Modified: map[string][2]string{"Content-Type": {"application/json", "text/plain"}},
+			},
+		},
+		{
+			name:   "added header",
+			actual: map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc", "X-Cache": "HIT"},
+			want: &models.HeadersDiff{
+				Added: map[string][2]string{"X-Cache": {"", "HIT"}},
+			},
+		},
+		{
+			name:   "removed header",
+			actual: map[string]string{"Content-Type": "application/json"},
+			want: &models.HeadersDiff{
+				Removed: map[string][2]string{"X-Request-Id": {"abc", ""}},
+			},
+		},
+		{
+			name:   "added, removed and modified headers",
+			actual: map[string]string{"Content-Type": "text/html", "X-Cache": "MISS"},
+			want: &models.HeadersDiff{
+				Added:    map[string][2]string{"X-Cache": {"", "MISS"}},
+				Removed:  map[string][2]string{"X-Request-Id": {"abc", ""}},
+				Modified: map[string][2]string{"Content-Type": {"application/json", "text/html"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &models.TestCase{Name: "test-1", HTTPResp: models.HTTPResp{StatusCode: 200, Header: expected, Body: "ok"}}
+			actual := models.HTTPResp{StatusCode: 200, Header: tt.actual, Body: "ok"}
+			pass, res := match(tc, &actual, map[string]map[string][]string{}, false, ValueTolerance{}, nil, zap.NewNop())
+			if pass != (tt.want == nil) {
+				t.Fatalf("match() = %v, want %v", pass, tt.want == nil)
+			}
+			if !reflect.DeepEqual(res.HeadersDiff, tt.want) {
+				t.Fatalf("match() headers diff = %+v, want %+v", res.HeadersDiff, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// MergedTestDB is a merged view of the test cases of several directories, e.g. the test cases of the project
+// with the ones shared from a common library. A test case of a test set is read from the first test db having
+// a test case with its name. The test cases are written to the first test db, the other ones are read only.
+type MergedTestDB struct {
+	testDBs []TestDB
+}
+
+// NewMergedTestDB returns the merged view of the test dbs, the first one is the test db of the project.
+func NewMergedTestDB(testDBs []TestDB) *MergedTestDB {
+	return &MergedTestDB{testDBs: testDBs}
+}
+
+func (m *MergedTestDB) GetAllTestSetIDs(ctx context.Context) ([]string, error) {
+	var testSetIDs []string
+	seen := map[string]bool{}
+	for _, testDB := range m.testDBs {
+		ids, err := testDB.GetAllTestSetIDs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the test set ids: %w", err)
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				testSetIDs = append(testSetIDs, id)
+			}
+		}
+	}
+	return testSetIDs, nil
+}
+
+func (m *MergedTestDB) GetTestCases(ctx context.Context, testSetID string) ([]*models.TestCase, error) {
+	var testCases []*models.TestCase
+	seen := map[string]bool{}
+	for _, testDB := range m.testDBs {
+		tcs, err := testDB.GetTestCases(ctx, testSetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the test cases: %w", err)
+		}
+		for _, tc := range tcs {
+			if !seen[tc.Name] {
+				seen[tc.Name] = true
+				testCases = append(testCases, tc)
+			}
+		}
+	}
+	return testCases, nil
+}
+
+// GetTestCase returns the test case from the first test db having it.
+func (m *MergedTestDB) GetTestCase(ctx context.Context, testSetID, testCaseID string) (*models.TestCase, error) {
+	for _, testDB := range m.testDBs {
+		tc, err := testDB.GetTestCase(ctx, testSetID, testCaseID)
+		if errors.Is(err, models.ErrTestCaseNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the test case: %w", err)
+		}
+		return tc, nil
+	}
+	return nil, fmt.Errorf("%w: %s in the test set %s", models.ErrTestCaseNotFound, testCaseID, testSetID)
+}
+
+func (m *MergedTestDB) UpdateTestCase(ctx context.Context, testCase *models.TestCase, testSetID string) error {
+	return m.testDBs[0].UpdateTestCase(ctx, testCase, testSetID)
+}
+
+func (m *MergedTestDB) InsertTestCase(ctx context.Context, testCase *models.TestCase, testSetID string) error {
+	return m.testDBs[0].InsertTestCase(ctx, testCase, testSetID)
+}
+
+func (m *MergedTestDB) DeleteTests(ctx context.Context, testSetID string, testCaseIDs []string) error {
+	return m.testDBs[0].DeleteTests(ctx, testSetID, testCaseIDs)
+}
+
+func (m *MergedTestDB) DeleteTestSet(ctx context.Context, testSetID string) error {
+	return m.testDBs[0].DeleteTestSet(ctx, testSetID)
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// MockCoverageReport lists every mock of the test set with whether it was consumed in the test run and by which
+// test cases, from the consumed mocks of the report of the test set. It reads all the mocks of the test set, so it
+// should be called before the unused mocks are removed.
+func (r *Replayer) MockCoverageReport(ctx context.Context, testRunID, testSetID string) (*models.MockCoverageReport, error) {
+	testReport, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the report of the test set: %w", err)
+	}
+	// the zero times select all the mocks of the test set
+	filtered, err := r.mockDB.GetFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the filtered mocks: %w", err)
+	}
+	unfiltered, err := r.mockDB.GetUnFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the unfiltered mocks: %w", err)
+	}
+
+	report := &models.MockCoverageReport{
+		TestRunID: testRunID,
+		TestSet:   testSetID,
+		Mocks:     []models.MockCoverage{},
+	}
+	for _, mock := range append(filtered, unfiltered...) {
+		consumedBy, consumed := testReport.ConsumedMocks[mock.Name]
+		report.Mocks = append(report.Mocks, models.MockCoverage{
+			Name:       mock.Name,
+			Kind:       mock.Kind,
+			Consumed:   consumed,
+			ConsumedBy: consumedBy,
+		})
+		if consumed {
+			report.Consumed++
+		}
+	}
+	report.Total = len(report.Mocks)
+	return report, nil
+}
+
+// GetMocksGroupedByEndpoint groups the mocks of the test set by the endpoint they mock, to find the endpoints
+// with the most recorded mocks.
+func (r *Replayer) GetMocksGroupedByEndpoint(ctx context.Context, testSetID string) (map[string][]*models.Mock, error) {
+	groups, err := r.mockDB.GetMocksGroupedByEndpoint(ctx, testSetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to group the mocks by endpoint: %w", err)
+	}
+	return groups, nil
+}
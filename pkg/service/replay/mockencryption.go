@@ -0,0 +1,40 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+)
+
+// EncryptMocks encrypts the mock files of the test sets, of all the test sets when none is given.
+func (r *Replayer) EncryptMocks(ctx context.Context, testSetIDs []string) (int, error) {
+	return r.transformMocks(ctx, testSetIDs, "encrypt", r.mockDB.EncryptMocks)
+}
+
+// DecryptMocks decrypts the mock files of the test sets, of all the test sets when none is given.
+func (r *Replayer) DecryptMocks(ctx context.Context, testSetIDs []string) (int, error) {
+	return r.transformMocks(ctx, testSetIDs, "decrypt", r.mockDB.DecryptMocks)
+}
+
+// transformMocks applies the transform to the mock file of each test set and returns the number of the changed files.
+func (r *Replayer) transformMocks(ctx context.Context, testSetIDs []string, action string, transform func(ctx context.Context, testSetID string) (bool, error)) (int, error) {
+	if len(testSetIDs) == 0 {
+		var err error
+		testSetIDs, err = r.testDB.GetAllTestSetIDs(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get all test set ids: %w", err)
+		}
+	}
+	changed := 0
+	for _, testSetID := range testSetIDs {
+		ok, err := transform(ctx, testSetID)
+		if err != nil {
+			return changed, fmt.Errorf("failed to %s the mocks of the test set %s: %w", action, testSetID, err)
+		}
+		if ok {
+			changed++
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,107 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// GetMockFidelityReport counts the mocks of the test sets of the test run matched exactly, matched fuzzily and not
+// matched at all, from the mock consumption events of the test case results. The mocks consumed by the test cases
+// run in parallel have no events, they are counted from the consumed and the fuzzy matched mocks of the report.
+// The latest test run is reported when the test run id is empty.
+func (r *Replayer) GetMockFidelityReport(ctx context.Context, testRunID string) (*models.MockFidelityReport, error) {
+	if testRunID == "" {
+		testRunIDs, err := r.reportDB.GetAllTestRunIDs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get all test run ids: %w", err)
+		}
+		for _, id := range testRunIDs {
+			if testRunID == "" || testRunIndex(id) > testRunIndex(testRunID) {
+				testRunID = id
+			}
+		}
+		if testRunID == "" {
+			return nil, errors.New("no test runs found")
+		}
+	}
+	testSetIDs, err := r.testDB.GetAllTestSetIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all test set ids: %w", err)
+	}
+
+	report := &models.MockFidelityReport{TestRunID: testRunID}
+	for _, testSetID := range testSetIDs {
+		testReport, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+		if err != nil || testReport == nil {
+			// the test set was not run in the test run
+			continue
+		}
+
+		matched := map[string]bool{}
+		fuzzy := map[string]bool{}
+		for _, result := range testReport.Tests {
+			for _, event := range result.MockConsumptionEvents {
+				matched[event.Name] = true
+				if event.Fuzzy {
+					fuzzy[event.Name] = true
+				}
+			}
+		}
+		for mockName := range testReport.ConsumedMocks {
+			matched[mockName] = true
+		}
+		for _, mockName := range testReport.FuzzyMatchedMocks {
+			if matched[mockName] {
+				fuzzy[mockName] = true
+			}
+		}
+
+		// the zero times select all the mocks of the test set, the unused mocks may have been removed since
+		total := len(matched)
+		filtered, err := r.mockDB.GetFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+		if err != nil {
+			r.logger.Debug("failed to get the filtered mocks of the test set", zap.String("test-set", testSetID), zap.Error(err))
+		}
+		unfiltered, err := r.mockDB.GetUnFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+		if err != nil {
+			r.logger.Debug("failed to get the unfiltered mocks of the test set", zap.String("test-set", testSetID), zap.Error(err))
+		}
+		total = max(total, len(filtered)+len(unfiltered))
+
+		report.TotalMocks += total
+		report.FuzzilyMatchedMocks += len(fuzzy)
+		report.PerfectlyMatchedMocks += len(matched) - len(fuzzy)
+		report.UnmatchedMocks += total - len(matched)
+	}
+	return report, nil
+}
+
+// mockConsumptionEvents returns the consumption events of the mocks consumed by a test case.
+func mockConsumptionEvents(consumedMocks, fuzzyMocks []string) []models.MockConsumptionEvent {
+	fuzzy := make(map[string]bool, len(fuzzyMocks))
+	for _, mockName := range fuzzyMocks {
+		fuzzy[mockName] = true
+	}
+	var events []models.MockConsumptionEvent
+	for _, mockName := range consumedMocks {
+		events = append(events, models.MockConsumptionEvent{Name: mockName, Fuzzy: fuzzy[mockName]})
+	}
+	return events
+}
+
+func sortedMockNames(mockNames map[string]bool) []string {
+	names := make([]string, 0, len(mockNames))
+	for mockName := range mockNames {
+		names = append(names, mockName)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,78 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/pkg/platform/yaml"
+	yamlLib "gopkg.in/yaml.v3"
+)
+
+// mockMigration is a migration of the schema of the mocks between two versions.
+type mockMigration struct {
+	from, to int
+}
+
+var (
+	mockMigratorsMu sync.RWMutex
+	mockMigrators   = map[mockMigration]models.MockMigrator{
+		{from: 1, to: 2}: HTTPHeadersMigrator{},
+	}
+)
+
+// RegisterMockMigrator registers the migrator of the mocks from a schema version to another,
+// replacing the migrator registered for the same versions.
+func RegisterMockMigrator(fromVersion, toVersion int, migrator models.MockMigrator) {
+	mockMigratorsMu.Lock()
+	defer mockMigratorsMu.Unlock()
+	mockMigrators[mockMigration{from: fromVersion, to: toVersion}] = migrator
+}
+
+// HTTPHeadersMigrator migrates the http mocks from the v1 to the v2 schema, which renames the req.header key to req.headers.
+type HTTPHeadersMigrator struct{}
+
+func (HTTPHeadersMigrator) Migrate(kind models.Kind, spec *yamlLib.Node) (bool, error) {
+	if kind != models.HTTP {
+		return false, nil
+	}
+	return yaml.RenameKey(spec, []string{"req"}, "header", "headers"), nil
+}
+
+func (r *Replayer) MigrateMocks(ctx context.Context, testSetID string, fromVersion, toVersion int) (int, error) {
+	mockMigratorsMu.RLock()
+	migrator, ok := mockMigrators[mockMigration{from: fromVersion, to: toVersion}]
+	mockMigratorsMu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("no migration of the mocks from the version %d to the version %d", fromVersion, toVersion)
+	}
+
+	// the zero times select all the mocks of the test set
+	filteredMocks, err := r.mockDB.GetFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the filtered mocks: %w", err)
+	}
+	unfilteredMocks, err := r.mockDB.GetUnFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the unfiltered mocks: %w", err)
+	}
+	mocks := append(filteredMocks, unfilteredMocks...)
+	if len(mocks) == 0 {
+		return 0, nil
+	}
+	// the mocks are written back in the order they were recorded
+	sort.SliceStable(mocks, func(i, j int) bool {
+		return mocks[i].Spec.ReqTimestampMock.Before(mocks[j].Spec.ReqTimestampMock)
+	})
+
+	migrated, err := r.mockDB.ReplaceMocks(ctx, testSetID, mocks, migrator)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write the migrated mocks: %w", err)
+	}
+	return migrated, nil
+}
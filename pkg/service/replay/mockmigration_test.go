@@ -0,0 +1,136 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/pkg/platform/yaml/mockdb"
+	"go.uber.org/zap"
+)
+
+// v1HTTPMock is a http mock of the v1 schema, the headers of the request are under req.header.
+const v1HTTPMock = `version: api.keploy.io/v1beta1
+kind: Http
+name: mock-0
+spec:
+    metadata: {}
+    req:
+        method: GET
+        proto_major: 1
+        proto_minor: 1
+        url: http://users.internal/users/1
+        header:
+            Accept: application/json
+        body: ""
+        timestamp: 2024-01-01T00:00:00Z
+    resp:
+        status_code: 200
+        header:
+            Content-Type: application/json
+        body: '{"id":1}'
+        status_message: OK
+        proto_major: 0
+        proto_minor: 0
+        timestamp: 2024-01-01T00:00:01Z
+    objects: []
+    created: 1704067201
+`
+
+// v2HTTPMock is the v1HTTPMock migrated to the v2 schema.
+var v2HTTPMock = strings.Replace(strings.Replace(v1HTTPMock, "mock-0", "mock-1", 1), "        header:\n            Accept", "        headers:\n            Accept", 1)
+
+const genericMock = `version: api.keploy.io/v1beta1
+kind: Generic
+name: mock-2
+spec:
+    metadata: {}
+    genericRequests:
+        - origin: client
+          message:
+            - type: binary
+              data: cGluZw==
+    genericResponses:
+        - origin: server
+          message:
+            - type: binary
+              data: cG9uZw==
+    reqTimestampMock: 2024-01-01T00:00:02Z
+    resTimestampMock: 2024-01-01T00:00:03Z
+`
+
+func TestMigrateMocks(t *testing.T) {
+	tests := []struct {
+		name         string
+		mocks        []string
+		from, to     int
+		wantMigrated int
+		wantErr      bool
+	}{
+		{name: "v1 http mock", mocks: []string{v1HTTPMock}, from: 1, to: 2, wantMigrated: 1},
+		{name: "already migrated http mock", mocks: []string{v2HTTPMock}, from: 1, to: 2, wantMigrated: 0},
+		{name: "mocks of the other kinds are kept", mocks: []string{v1HTTPMock, v2HTTPMock, genericMock}, from: 1, to: 2, wantMigrated: 1},
+		{name: "no mocks", from: 1, to: 2, wantMigrated: 0},
+		{name: "unknown migration", mocks: []string{v1HTTPMock}, from: 2, to: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir()
+			mockFile := filepath.Join(path, "test-set-0", "mocks.yaml")
+			if err := os.MkdirAll(filepath.Dir(mockFile), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(mockFile, []byte(strings.Join(tt.mocks, "---\n")), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			r := &Replayer{logger: zap.NewNop(), mockDB: mockdb.New(zap.NewNop(), path, "mocks")}
+			migrated, err := r.MigrateMocks(context.Background(), "test-set-0", tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MigrateMocks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if migrated != tt.wantMigrated {
+				t.Fatalf("MigrateMocks() = %d, want %d", migrated, tt.wantMigrated)
+			}
+			if tt.wantErr || len(tt.mocks) == 0 {
+				return
+			}
+
+			data, err := os.ReadFile(mockFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			migratedMocks := string(data)
+			if got := strings.Count(migratedMocks, "kind: "); got != len(tt.mocks) {
+				t.Fatalf("the migrated mock file has %d mocks, want %d:\n%s", got, len(tt.mocks), migratedMocks)
+			}
+			// the request headers are renamed, the response headers keep their key
+			if strings.Contains(migratedMocks, "        header:\n            Accept") {
+				t.Fatalf("the request headers of the migrated mocks are still under req.header:\n%s", migratedMocks)
+			}
+			if !strings.Contains(migratedMocks, "        headers:\n            Accept: application/json") {
+				t.Fatalf("the request headers of the migrated mocks are not under req.headers:\n%s", migratedMocks)
+			}
+			if !strings.Contains(migratedMocks, "        header:\n            Content-Type: application/json") {
+				t.Fatalf("the response headers of the migrated mocks are not under resp.header:\n%s", migratedMocks)
+			}
+
+			// the migrated mocks are still decoded with their request headers
+			mocks, err := r.mockDB.GetUnFilteredMocks(context.Background(), "test-set-0", time.Time{}, time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatalf("failed to read the migrated mocks: %v", err)
+			}
+			for _, mock := range mocks {
+				if mock.Kind == models.HTTP && mock.Spec.HTTPReq.Header["Accept"] != "application/json" {
+					t.Fatalf("the request headers of the migrated mock %s = %v", mock.Name, mock.Spec.HTTPReq.Header)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+//go:build linux
+
+package replay
+
+import (
+	"fmt"
+	"sort"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// ValidateMockOrdering checks that the response of every mock is captured after its request and that the
+// mocks are in the order of their requests, as the proxy matches them in that order. It returns the violations.
+// The mocks without the timestamps (e.g. the config mocks) are skipped.
+func ValidateMockOrdering(mocks []*models.Mock) []string {
+	var violations []string
+	var prev *models.Mock
+	for _, mock := range mocks {
+		if mock == nil || mock.Spec.ReqTimestampMock.IsZero() {
+			continue
+		}
+		req, res := mock.Spec.ReqTimestampMock, mock.Spec.ResTimestampMock
+		if !res.IsZero() && res.Before(req) {
+			violations = append(violations, fmt.Sprintf("response of the mock %s is captured at %s, before its request at %s", mock.Name, res, req))
+		}
+		if prev != nil && req.Before(prev.Spec.ReqTimestampMock) {
+			violations = append(violations, fmt.Sprintf("mock %s with request at %s is placed after the mock %s with request at %s", mock.Name, req, prev.Name, prev.Spec.ReqTimestampMock))
+		}
+		prev = mock
+	}
+	return violations
+}
+
+// mockWindows splits the mocks into the runs of the mocks in the window of the test case and out of it. The mock
+// db returns the mocks in the window first, then the others, each run in the order of its requests, so the runs are
+// validated and sorted on their own to keep the priority of the mocks in the window. The runs share the array.
+func mockWindows(mocks []*models.Mock) [][]*models.Mock {
+	var windows [][]*models.Mock
+	start := 0
+	for i := 1; i <= len(mocks); i++ {
+		if i == len(mocks) || mocks[i].TestModeInfo.IsFiltered != mocks[start].TestModeInfo.IsFiltered {
+			windows = append(windows, mocks[start:i])
+			start = i
+		}
+	}
+	return windows
+}
+
+// sortMocks sorts the mocks in place by the timestamp of their requests. The mocks without one (e.g. the config
+// mocks) have the zero timestamp, so they are moved first, in their order.
+func sortMocks(mocks []*models.Mock) {
+	sort.SliceStable(mocks, func(i, j int) bool {
+		return mocks[i].Spec.ReqTimestampMock.Before(mocks[j].Spec.ReqTimestampMock)
+	})
+}
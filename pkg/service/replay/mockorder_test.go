@@ -0,0 +1,76 @@
+//go:build linux
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+func TestMockWindowsKeepTheWindowPriority(t *testing.T) {
+	base := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	mock := func(name string, offset time.Duration, inWindow bool) *models.Mock {
+		m := &models.Mock{Name: name}
+		m.Spec.ReqTimestampMock = base.Add(offset)
+		m.Spec.ResTimestampMock = base.Add(offset + time.Millisecond)
+		m.TestModeInfo.IsFiltered = inWindow
+		return m
+	}
+
+	tests := []struct {
+		name           string
+		mocks          []*models.Mock
+		wantWindows    int
+		wantViolations int
+	}{
+		{
+			name:        "no mocks",
+			wantWindows: 0,
+		},
+		{
+			name: "the window mocks recorded after the others are in order",
+			mocks: []*models.Mock{
+				mock("mock-2", 2*time.Second, true),
+				mock("mock-3", 3*time.Second, true),
+				mock("mock-0", 0, false),
+				mock("mock-1", time.Second, false),
+			},
+			wantWindows: 2,
+		},
+		{
+			name: "a window mock out of order is a violation",
+			mocks: []*models.Mock{
+				mock("mock-3", 3*time.Second, true),
+				mock("mock-2", 2*time.Second, true),
+				mock("mock-0", 0, false),
+			},
+			wantWindows:    2,
+			wantViolations: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows := mockWindows(tt.mocks)
+			if len(windows) != tt.wantWindows {
+				t.Fatalf("got %d windows, want %d", len(windows), tt.wantWindows)
+			}
+			violations := 0
+			for _, window := range windows {
+				violations += len(ValidateMockOrdering(window))
+				sortMocks(window)
+			}
+			if violations != tt.wantViolations {
+				t.Errorf("got %d violations, want %d", violations, tt.wantViolations)
+			}
+			// sorting the windows keeps the mocks of the window first
+			for i := 1; i < len(tt.mocks); i++ {
+				if !tt.mocks[i-1].TestModeInfo.IsFiltered && tt.mocks[i].TestModeInfo.IsFiltered {
+					t.Errorf("mock %s out of the window is sorted before the window mock %s", tt.mocks[i-1].Name, tt.mocks[i].Name)
+				}
+			}
+		})
+	}
+}
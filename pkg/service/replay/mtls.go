@@ -0,0 +1,22 @@
+//go:build linux
+
+package replay
+
+import (
+	"crypto/tls"
+
+	"go.keploy.io/server/v2/config"
+)
+
+// WithClientTLS sends the https requests to the application with the tls config, presenting its client certificate
+// to the applications requiring mutual tls.
+func WithClientTLS(tlsConfig *tls.Config) RequestMockUtilOption {
+	return func(t *requestMockUtil) {
+		t.tlsConfig = tlsConfig
+	}
+}
+
+// hasClientTLS reports whether the test config sets up the tls of the requests sent to the application.
+func hasClientTLS(test config.Test) bool {
+	return test.ClientCert != "" || test.ClientKey != "" || test.CACert != "" || test.InsecureSkipVerify
+}
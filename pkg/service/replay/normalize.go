@@ -0,0 +1,43 @@
+//go:build linux
+
+package replay
+
+import (
+	"strings"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// mergeResponse updates only the status code, the headers and the body of the recorded response which did not
+// match the actual response, so that the fields of the recorded response edited by hand and still matching are kept.
+func mergeResponse(recorded *models.HTTPResp, actual models.HTTPResp, result models.Result) {
+	if !result.StatusCode.Normal {
+		recorded.StatusCode = actual.StatusCode
+		recorded.StatusMessage = actual.StatusMessage
+	}
+
+	for _, header := range result.HeadersResult {
+		if header.Normal {
+			continue
+		}
+		key := header.Expected.Key
+		if key == "" {
+			key = header.Actual.Key
+		}
+		if recorded.Header == nil {
+			recorded.Header = map[string]string{}
+		}
+		if len(header.Actual.Value) == 0 {
+			delete(recorded.Header, key)
+			continue
+		}
+		recorded.Header[key] = strings.Join(header.Actual.Value, ",")
+	}
+
+	for _, body := range result.BodyResult {
+		if !body.Normal {
+			recorded.Body = actual.Body
+			break
+		}
+	}
+}
@@ -0,0 +1,89 @@
+//go:build linux
+
+package replay
+
+import (
+	"reflect"
+	"testing"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestMergeResponseKeepsTheMatchingFields(t *testing.T) {
+	// the content type of the recorded response was corrected by hand, it matches the actual response
+	recorded := models.HTTPResp{
+		StatusCode: 200,
+		Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc", "X-Deprecated": "true"},
+		Body:       `{"id":1,"name":"keploy"}`,
+	}
+
+	tests := []struct {
+		name   string
+		actual models.HTTPResp
+		want   models.HTTPResp
+	}{
+		{
+			name: "matching response",
+			actual: models.HTTPResp{
+				StatusCode: 200,
+				Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc", "X-Deprecated": "true"},
+				Body:       `{"id":1,"name":"keploy"}`,
+			},
+			want: recorded,
+		},
+		{
+			name: "changed header keeps the corrected header",
+			actual: models.HTTPResp{
+				StatusCode: 200,
+				Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "def", "X-Deprecated": "true"},
+				Body:       `{"id":1,"name":"keploy"}`,
+			},
+			want: models.HTTPResp{
+				StatusCode: 200,
+				Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "def", "X-Deprecated": "true"},
+				Body:       `{"id":1,"name":"keploy"}`,
+			},
+		},
+		{
+			name: "removed header",
+			actual: models.HTTPResp{
+				StatusCode: 200,
+				Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc"},
+				Body:       `{"id":1,"name":"keploy"}`,
+			},
+			want: models.HTTPResp{
+				StatusCode: 200,
+				Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc"},
+				Body:       `{"id":1,"name":"keploy"}`,
+			},
+		},
+		{
+			name: "changed status code and body",
+			actual: models.HTTPResp{
+				StatusCode: 201,
+				Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc", "X-Deprecated": "true"},
+				Body:       `{"id":2,"name":"keploy"}`,
+			},
+			want: models.HTTPResp{
+				StatusCode: 201,
+				Header:     map[string]string{"Content-Type": "application/json", "X-Request-Id": "abc", "X-Deprecated": "true"},
+				Body:       `{"id":2,"name":"keploy"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &models.TestCase{Name: "test-1", HTTPResp: recorded}
+			tc.HTTPResp.Header = make(map[string]string, len(recorded.Header))
+			for key, value := range recorded.Header {
+				tc.HTTPResp.Header[key] = value
+			}
+			_, result := match(tc, &tt.actual, map[string]map[string][]string{}, false, ValueTolerance{}, nil, zap.NewNop())
+			mergeResponse(&tc.HTTPResp, tt.actual, *result)
+			if !reflect.DeepEqual(tc.HTTPResp, tt.want) {
+				t.Fatalf("mergeResponse() = %+v, want %+v", tc.HTTPResp, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// orderByLastFailure moves the test cases which failed in the latest prior run of the test set first, the test cases
+// keep their order otherwise. Combined with fail fast, the test run stops early on the regressions not fixed yet.
+func (r *Replayer) orderByLastFailure(ctx context.Context, testRunID, testSetID string, testCases []*models.TestCase) []*models.TestCase {
+	testRunIDs, err := r.reportDB.GetAllTestRunIDs(ctx)
+	if err != nil {
+		r.logger.Warn("failed to get the test runs, running the test cases in their recorded order", zap.String("test-set", testSetID), zap.Error(err))
+		return testCases
+	}
+	sort.SliceStable(testRunIDs, func(i, j int) bool {
+		return testRunIndex(testRunIDs[i]) > testRunIndex(testRunIDs[j])
+	})
+
+	var lastReport *models.TestReport
+	for _, id := range testRunIDs {
+		if id == testRunID {
+			continue
+		}
+		report, err := r.reportDB.GetReport(ctx, id, testSetID)
+		if err != nil || report == nil {
+			continue
+		}
+		lastReport = report
+		break
+	}
+	if lastReport == nil {
+		return testCases
+	}
+
+	failed := map[string]bool{}
+	for _, result := range lastReport.Tests {
+		if result.Status == models.TestStatusFailed {
+			failed[result.TestCaseID] = true
+		}
+	}
+	if len(failed) == 0 {
+		return testCases
+	}
+	ordered := append([]*models.TestCase{}, testCases...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return failed[ordered[i].Name] && !failed[ordered[j].Name]
+	})
+	r.logger.Info("running the test cases which failed in the last test run first", zap.String("test-set", testSetID), zap.Int("failed test cases", len(failed)))
+	return ordered
+}
+
+// shuffleTestCases shuffles the test cases with the seed of the test run, the seed is logged to reproduce the order.
+func (r *Replayer) shuffleTestCases(testRunID, testSetID string, testCases []*models.TestCase) []*models.TestCase {
+	seed := r.shuffleSeed(testRunID)
+	shuffled := append([]*models.TestCase{}, testCases...)
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	r.logger.Info("running the test cases in a random order", zap.String("test-set", testSetID), zap.Int64("seed", seed))
+	return shuffled
+}
+
+// shuffleSeed returns the seed of the test run, the configured seed or a random one picked for the test run.
+func (r *Replayer) shuffleSeed(testRunID string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if seed, ok := r.shuffleSeeds[testRunID]; ok {
+		return seed
+	}
+	seed := r.config.Test.RandomSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	r.setShuffleSeedLocked(testRunID, seed)
+	return seed
+}
+
+func (r *Replayer) setShuffleSeedLocked(testRunID string, seed int64) {
+	if r.shuffleSeeds == nil {
+		r.shuffleSeeds = map[string]int64{}
+	}
+	r.shuffleSeeds[testRunID] = seed
+}
+
+// checkOrderSensitivity detects the order sensitive test cases of the completed test set when its test cases run in
+// random orders. Only the cancellation of the test run is returned, the other errors of the detection are logged.
+func (r *Replayer) checkOrderSensitivity(ctx context.Context, testRunID, testSetID string, appID uint64, status models.TestSetStatus) error {
+	if !r.config.Test.RandomizeOrder || r.config.Test.RandomRuns <= 1 || r.keepsRecordedOrder(testSetID) {
+		return nil
+	}
+	if status != models.TestSetStatusPassed && status != models.TestSetStatusFailed {
+		return nil
+	}
+	err := r.detectOrderSensitivity(ctx, testRunID, testSetID, appID)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		utils.LogError(r.logger, err, "failed to detect the order sensitive test cases", zap.String("test-set", testSetID))
+	}
+	return nil
+}
+
+// detectOrderSensitivity runs the test set RandomRuns-1 more times, each in the order of another seed, and flags the
+// test cases of the report of the test run which passed in some of the orders and failed in others.
+func (r *Replayer) detectOrderSensitivity(ctx context.Context, testRunID, testSetID string, appID uint64) error {
+	passed := map[string]bool{}
+	failed := map[string]bool{}
+	collect := func(runID string) error {
+		results, err := r.reportDB.GetTestCaseResults(ctx, runID, testSetID)
+		if err != nil {
+			return fmt.Errorf("failed to get the test case results: %w", err)
+		}
+		for _, result := range results {
+			switch result.Status {
+			case models.TestStatusPassed:
+				passed[result.TestCaseID] = true
+			case models.TestStatusFailed:
+				failed[result.TestCaseID] = true
+			}
+		}
+		return nil
+	}
+	if err := collect(testRunID); err != nil {
+		return err
+	}
+
+	seed := r.shuffleSeed(testRunID)
+	for i := 1; i < r.config.Test.RandomRuns; i++ {
+		runID, err := r.GetNextTestRunID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get next test run id: %w", err)
+		}
+		r.mu.Lock()
+		r.setShuffleSeedLocked(runID, seed+int64(i))
+		r.mu.Unlock()
+		status, _, err := r.RunTestSet(ctx, testSetID, runID, appID, false)
+		if err != nil {
+			return fmt.Errorf("failed to run the test set in another order: %w", err)
+		}
+		if status == models.TestSetStatusUserAbort {
+			return context.Canceled
+		}
+		if err := collect(runID); err != nil {
+			return err
+		}
+	}
+
+	report, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+	if err != nil {
+		return fmt.Errorf("failed to get the report of the test set: %w", err)
+	}
+	var sensitive []string
+	for i, result := range report.Tests {
+		if passed[result.TestCaseID] && failed[result.TestCaseID] {
+			report.Tests[i].OrderSensitive = true
+			sensitive = append(sensitive, result.TestCaseID)
+		}
+	}
+	if len(sensitive) == 0 {
+		r.logger.Info("no order sensitive test cases found", zap.String("test-set", testSetID), zap.Int("runs", r.config.Test.RandomRuns))
+		return nil
+	}
+	// the report keeps its name, it is written over
+	if err := r.reportDB.InsertReport(ctx, testRunID, testSetID, report); err != nil {
+		return fmt.Errorf("failed to update the report of the test set: %w", err)
+	}
+	r.logger.Warn("test cases passing only in some orders of the test set, they depend on the state left by the other test cases", zap.String("test-set", testSetID), zap.Strings("order sensitive test cases", sensitive))
+	return nil
+}
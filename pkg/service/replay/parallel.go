@@ -0,0 +1,204 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// parallelRun is the outcome of running the test cases of a test set in parallel.
+type parallelRun struct {
+	success  int64
+	failure  int64
+	recorded int64
+	// failed is set if any of the test cases failed
+	failed bool
+	// aborted is set if the run is stopped because of the application or the mocking errors
+	aborted bool
+}
+
+// testCaseWorkers returns the number of test cases of the test set run concurrently. The parallelism only applies
+// with the base path, it is reset to 1 when the flags are validated otherwise.
+func (r *Replayer) testCaseWorkers(testSetID string) int {
+	workers := r.config.Test.Parallelism
+	if workers > 1 && r.keepsRecordedOrder(testSetID) {
+		r.logger.Warn("running the test cases serially, the chained requests depend on the responses of the test cases run before them", zap.String("test-set", testSetID))
+		return 1
+	}
+	return workers
+}
+
+// runTestCasesInParallel fans out the test cases across the workers.
+// The test cases only run concurrently against the base path, so no mocks are set up for them.
+// The results are inserted in the report sorted by the test case name, so that the report is deterministic.
+func (r *Replayer) runTestCasesInParallel(ctx context.Context, appID uint64, testRunID, testSetID string, testCases []*models.TestCase, userIP string, chain *requestChain, exitLoopChan chan bool, workers int, progress *testSetProgress) (*parallelRun, error) {
+	run := &parallelRun{}
+	if len(testCases) == 0 {
+		return run, nil
+	}
+
+	caseRun := testCaseRun{testRunID: testRunID, testSetID: testSetID, userIP: userIP, chain: chain}
+	jobs := make(chan *models.TestCase)
+	results := make(chan *models.TestResult, len(testCases))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer utils.Recover(r.logger)
+			for tc := range jobs {
+				progress.start(tc.Name)
+				outcome := r.replayTestCase(ctx, appID, caseRun, tc)
+				switch {
+				case outcome.recorded:
+					atomic.AddInt64(&run.recorded, 1)
+					progress.end(tc.Name, models.TestStatusRecorded)
+				case outcome.result == nil || outcome.result.Status != models.TestStatusPassed:
+					atomic.AddInt64(&run.failure, 1)
+					progress.end(tc.Name, models.TestStatusFailed)
+					if r.config.Test.FailFast {
+						r.recordFirstFailure(testSetID, tc.Name)
+					}
+				default:
+					atomic.AddInt64(&run.success, 1)
+					progress.end(tc.Name, models.TestStatusPassed)
+				}
+				if outcome.result != nil {
+					results <- outcome.result
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, tc := range testCases {
+		if r.failedFast() {
+			break
+		}
+		if r.maxFailuresReached(testSetID, int(atomic.LoadInt64(&run.failure))) {
+			break
+		}
+		select {
+		case <-exitLoopChan:
+			run.aborted = true
+			break dispatch
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- tc:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	// the test cases failing without a result (e.g. their request failed) fail the test set as well
+	run.failed = run.failure > 0
+
+	var testCaseResults []*models.TestResult
+	for result := range results {
+		testCaseResults = append(testCaseResults, result)
+	}
+	sort.Slice(testCaseResults, func(i, j int) bool {
+		return testCaseResults[i].TestCaseID < testCaseResults[j].TestCaseID
+	})
+	for _, result := range testCaseResults {
+		err := r.reportDB.InsertTestCaseResult(ctx, testRunID, testSetID, result)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to insert test case result")
+			return run, err
+		}
+		r.sendResult(result)
+		// the results only have a precision of seconds
+		duration := time.Duration(result.Completed-result.Started) * time.Second
+		if err := r.summaryWriter.TestCaseResult(testSetID, result, duration); err != nil {
+			utils.LogError(r.logger, err, "failed to write the test case result")
+		}
+	}
+	return run, nil
+}
+
+// runTestSetsInParallel runs up to Test.MaxParallel test sets concurrently against the application at the base path.
+// Once a test set aborts the test run (or fails to run), the in-flight test sets finish but no new ones start.
+func (r *Replayer) runTestSetsInParallel(ctx context.Context, state *runState, testSetIDs []string, testRunID string, inst *InstrumentState) (testRunResult, abortTestRun, userAbort bool, err error) {
+	var mu sync.Mutex
+	// the hooks of the request mock emulator and the reruns of the order sensitivity detection are not safe to run
+	// concurrently, they run one test set at a time
+	var hookMu sync.Mutex
+	var wg sync.WaitGroup
+	testRunResult = true
+	stopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return abortTestRun || userAbort || err != nil
+	}
+
+	slots := make(chan struct{}, r.config.Test.MaxParallel)
+	for _, testSetID := range testSetIDs {
+		if _, ok := r.config.Test.SelectedTests[testSetID]; !ok && len(r.config.Test.SelectedTests) != 0 {
+			continue
+		}
+		// wait for a free slot before deciding whether the run should go on
+		slots <- struct{}{}
+		if stopped() || r.failedFast() || ctx.Err() != nil {
+			<-slots
+			break
+		}
+
+		wg.Add(1)
+		go func(testSetID string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			defer utils.Recover(r.logger)
+
+			hookMu.Lock()
+			requestMockemulator.ProcessMockFile(ctx, testSetID)
+			hookMu.Unlock()
+			testSetStatus, verdict, runErr := r.RunTestSet(ctx, testSetID, testRunID, inst.AppID, false)
+			if runErr == nil {
+				hookMu.Lock()
+				runErr = r.checkOrderSensitivity(ctx, testRunID, testSetID, inst.AppID, testSetStatus)
+				hookMu.Unlock()
+			}
+			if runErr != nil {
+				mu.Lock()
+				if err == nil {
+					err = runErr
+				}
+				mu.Unlock()
+				return
+			}
+			if testSetStatus == models.TestSetStatusUserAbort {
+				mu.Lock()
+				userAbort = true
+				mu.Unlock()
+				return
+			}
+			state.addTestSet(testSetID, verdict)
+
+			hookMu.Lock()
+			defer hookMu.Unlock()
+			testSetResult, abort := r.processTestSetStatus(ctx, testRunID, testSetID, testSetStatus)
+			mu.Lock()
+			testRunResult = testRunResult && testSetResult
+			abortTestRun = abortTestRun || abort
+			mu.Unlock()
+			if abort {
+				return
+			}
+			_, hookErr := requestMockemulator.AfterTestHook(ctx, testRunID, testSetID, len(testSetIDs))
+			if hookErr != nil {
+				utils.LogError(r.logger, hookErr, "failed to get after test hook")
+			}
+		}(testSetID)
+	}
+	wg.Wait()
+	return testRunResult, abortTestRun, userAbort, err
+}
@@ -0,0 +1,117 @@
+//go:build linux
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.keploy.io/server/v2/config"
+	"go.uber.org/zap"
+)
+
+const (
+	githubAPIURL = "https://api.github.com"
+	gitlabAPIURL = "https://gitlab.com/api/v4"
+)
+
+// postPRComment posts the summary of the test run as a markdown comment on the configured pull/merge request.
+func (r *Replayer) postPRComment(ctx context.Context, state *runState, testRunID string, testRunResult bool) error {
+	prConf := r.config.Test.CIPRComment
+	if prConf.Token == "" || prConf.RepoOwner == "" || prConf.RepoName == "" || prConf.PRNumber <= 0 {
+		return fmt.Errorf("token, repoOwner, repoName and prNumber are required to post the PR comment")
+	}
+
+	req, err := newPRCommentRequest(ctx, prConf, summaryMarkdown(state, testRunID, testRunResult))
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the PR comment request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			r.logger.Debug("failed to close the PR comment response body", zap.Error(err))
+		}
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d while posting the PR comment: %s", resp.StatusCode, string(body))
+	}
+
+	r.logger.Info("posted the test run summary as PR comment", zap.String("provider", prConf.Provider), zap.String("repo", prConf.RepoOwner+"/"+prConf.RepoName), zap.Int("pr", prConf.PRNumber))
+	return nil
+}
+
+// newPRCommentRequest builds the provider specific request to comment on the pull/merge request.
+func newPRCommentRequest(ctx context.Context, prConf *config.PRCommentConfig, comment string) (*http.Request, error) {
+	payload, err := json.Marshal(map[string]string{"body": comment})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the PR comment: %w", err)
+	}
+
+	var endpoint string
+	header := http.Header{}
+	switch strings.ToLower(prConf.Provider) {
+	case "github", "":
+		apiURL := githubAPIURL
+		if prConf.APIURL != "" {
+			apiURL = strings.TrimSuffix(prConf.APIURL, "/")
+		}
+		endpoint = fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", apiURL, prConf.RepoOwner, prConf.RepoName, prConf.PRNumber)
+		header.Set("Authorization", "Bearer "+prConf.Token)
+		header.Set("Accept", "application/vnd.github+json")
+	case "gitlab":
+		apiURL := gitlabAPIURL
+		if prConf.APIURL != "" {
+			apiURL = strings.TrimSuffix(prConf.APIURL, "/")
+		}
+		project := url.PathEscape(prConf.RepoOwner + "/" + prConf.RepoName)
+		endpoint = fmt.Sprintf("%s/projects/%s/merge_requests/%d/notes", apiURL, project, prConf.PRNumber)
+		header.Set("PRIVATE-TOKEN", prConf.Token)
+	default:
+		return nil, fmt.Errorf("unsupported PR comment provider: %s, should be github or gitlab", prConf.Provider)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the PR comment request: %w", err)
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// summaryMarkdown formats the complete test run summary as a markdown table.
+func summaryMarkdown(state *runState, testRunID string, testRunResult bool) string {
+	totalTests, totalTestPassed, totalTestFailed := state.totals()
+	status := ":white_check_mark: Passed"
+	if !testRunResult {
+		status = ":x: Failed"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### Keploy test run summary (%s)\n\n", testRunID))
+	sb.WriteString(fmt.Sprintf("**Status:** %s | **Total tests:** %d | **Passed:** %d | **Failed:** %d\n\n", status, totalTests, totalTestPassed, totalTestFailed))
+	sb.WriteString("| Test Set | Total | Passed | Failed | Status |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	for _, testSetID := range state.sortedTestSuiteNames() {
+		verdict := state.verdict(testSetID)
+		testSetStatus := ":white_check_mark:"
+		if !verdict.Status {
+			testSetStatus = ":x:"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %s |\n", testSetID, verdict.Total, verdict.Passed, verdict.Failed, testSetStatus))
+	}
+	return sb.String()
+}
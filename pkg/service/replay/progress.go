@@ -0,0 +1,94 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// StreamTestProgress streams the progress events of the test run on the channel until the context is done, an empty
+// test run id streams the events of every test run. The events are sent without blocking, they are dropped when the
+// channel is full, so the channel should be buffered for a slow consumer. Once it returns, the channel is not sent
+// on anymore and can be closed by its owner. The replayer never closes the channel.
+func (r *Replayer) StreamTestProgress(ctx context.Context, testRunID string, ch chan<- models.TestProgressEvent) error {
+	if ch == nil {
+		return errors.New("the progress channel is nil")
+	}
+	r.sinkMu.Lock()
+	if r.progressSinks == nil {
+		r.progressSinks = map[chan<- models.TestProgressEvent]string{}
+	}
+	r.progressSinks[ch] = testRunID
+	r.sinkMu.Unlock()
+
+	<-ctx.Done()
+
+	r.sinkMu.Lock()
+	delete(r.progressSinks, ch)
+	r.sinkMu.Unlock()
+	return nil
+}
+
+// sendProgress sends the progress event on the channels streaming its test run.
+func (r *Replayer) sendProgress(event models.TestProgressEvent) {
+	r.sinkMu.Lock()
+	defer r.sinkMu.Unlock()
+	for ch, testRunID := range r.progressSinks {
+		if testRunID != "" && testRunID != event.TestRunID {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			r.logger.Debug("dropped the test progress event as the progress channel is full", zap.String("testcase", event.TestCaseID), zap.String("test-set", event.TestSetID))
+		}
+	}
+}
+
+// testSetProgress tracks the progress of the test cases of a test set, which may run in parallel.
+type testSetProgress struct {
+	r         *Replayer
+	testRunID string
+	testSetID string
+	total     int
+	started   time.Time
+	completed int64
+}
+
+func (r *Replayer) newTestSetProgress(testRunID, testSetID string, total int) *testSetProgress {
+	return &testSetProgress{
+		r:         r,
+		testRunID: testRunID,
+		testSetID: testSetID,
+		total:     total,
+		started:   time.Now(),
+	}
+}
+
+// start sends the progress event of the test case starting to run.
+func (p *testSetProgress) start(testCaseID string) {
+	p.send(testCaseID, models.TestStatusRunning, int(atomic.LoadInt64(&p.completed)))
+}
+
+// end counts the test case as completed and sends its progress event with its final status.
+func (p *testSetProgress) end(testCaseID string, status models.TestStatus) {
+	p.send(testCaseID, status, int(atomic.AddInt64(&p.completed, 1)))
+}
+
+func (p *testSetProgress) send(testCaseID string, status models.TestStatus, completed int) {
+	p.r.sendProgress(models.TestProgressEvent{
+		TestRunID:      p.testRunID,
+		TestSetID:      p.testSetID,
+		TestCaseID:     testCaseID,
+		Status:         status,
+		ElapsedMs:      time.Since(p.started).Milliseconds(),
+		TotalCases:     p.total,
+		CompletedCases: completed,
+	})
+}
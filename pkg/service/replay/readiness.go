@@ -0,0 +1,175 @@
+//go:build linux
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultReadinessTimeout is how long the readiness probe is polled when no timeout is configured
+	defaultReadinessTimeout = 60 * time.Second
+	// the probe is polled with an exponential backoff, so that a fast starting app is not waited for long
+	readinessInitialBackoff = 100 * time.Millisecond
+	readinessMaxBackoff     = 5 * time.Second
+	readinessRequestTimeout = time.Second
+	// maxReadinessLineSize bounds the buffered line of the app output, a longer line is matched as is
+	maxReadinessLineSize = 64 * 1024
+)
+
+// waitUntilReady polls the readiness probe of the app until it responds with a 2xx status code or the readiness
+// timeout elapses, in which case the test cases are run anyway. The polling backs off exponentially from 100ms. The host of the probe is replaced with the ip of
+// the container of the docker apps. It only returns an error when the context is done.
+func (r *Replayer) waitUntilReady(ctx context.Context, appID uint64) error {
+	timeout := r.config.Test.ReadinessTimeout
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	backoff := readinessInitialBackoff
+	client := &http.Client{Timeout: readinessRequestTimeout}
+	started := time.Now()
+
+	for {
+		if r.probeReady(ctx, client, appID) {
+			r.logger.Info("the application is ready", zap.String("readiness probe", r.config.Test.ReadinessProbe), zap.Duration("after", time.Since(started)))
+			return nil
+		}
+		wait := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return ctx.Err()
+		case <-deadline.C:
+			wait.Stop()
+			r.logger.Warn("the application is not ready before the readiness timeout, running the testcases anyway", zap.String("readiness probe", r.config.Test.ReadinessProbe), zap.Duration("timeout", timeout))
+			return nil
+		case <-wait.C:
+		}
+		backoff = min(backoff*2, readinessMaxBackoff)
+	}
+}
+
+// probeReady requests the readiness probe once, the container of the docker apps may not have an ip yet.
+func (r *Replayer) probeReady(ctx context.Context, client *http.Client, appID uint64) bool {
+	probeURL := r.config.Test.ReadinessProbe
+	if utils.IsDockerKind(utils.CmdType(r.config.CommandType)) {
+		userIP, err := r.instrumentation.GetContainerIP(ctx, appID)
+		if err != nil {
+			r.logger.Debug("the ip of the application container is not known yet", zap.Error(err))
+			return false
+		}
+		probeURL, err = utils.ReplaceHostToIP(probeURL, userIP)
+		if err != nil {
+			r.logger.Debug("failed to replace the host of the readiness probe", zap.String("readiness probe", probeURL), zap.Error(err))
+			return false
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
+	if err != nil {
+		r.logger.Debug("failed to create the readiness probe request", zap.String("readiness probe", probeURL), zap.Error(err))
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		r.logger.Debug("the application is not ready yet", zap.String("readiness probe", probeURL), zap.Error(err))
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// logLineWatcher scans the stdout and the stderr of the app for the first line matching the readiness log pattern,
+// for the apps logging that they are ready without a health endpoint to probe.
+type logLineWatcher struct {
+	pattern *regexp.Regexp
+	mu      sync.Mutex
+	matched string
+	ready   chan struct{}
+}
+
+func newLogLineWatcher(pattern *regexp.Regexp) *logLineWatcher {
+	return &logLineWatcher{pattern: pattern, ready: make(chan struct{})}
+}
+
+// stream returns a writer splitting an output of the app into lines, each output has its own writer so that the
+// partial lines of the stdout and the stderr are not mixed.
+func (w *logLineWatcher) stream() *logLineStream {
+	return &logLineStream{watcher: w}
+}
+
+func (w *logLineWatcher) match(line string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.matched != "" || !w.pattern.MatchString(line) {
+		return
+	}
+	w.matched = line
+	close(w.ready)
+}
+
+func (w *logLineWatcher) matchedLine() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.matched
+}
+
+type logLineStream struct {
+	watcher *logLineWatcher
+	partial []byte
+}
+
+func (s *logLineStream) Write(p []byte) (int, error) {
+	select {
+	case <-s.watcher.ready:
+		return len(p), nil
+	default:
+	}
+	s.partial = append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+		s.watcher.match(strings.TrimRight(string(s.partial[:i]), "\r"))
+		s.partial = s.partial[i+1:]
+	}
+	if len(s.partial) > maxReadinessLineSize {
+		s.watcher.match(string(s.partial))
+		s.partial = nil
+	}
+	return len(p), nil
+}
+
+// waitForLogLine waits until the app logs a line matching the readiness log pattern or the readiness timeout
+// elapses, in which case the test cases are run anyway. It only returns an error when the context is done.
+func (r *Replayer) waitForLogLine(ctx context.Context, watcher *logLineWatcher) error {
+	timeout := r.config.Test.ReadinessTimeout
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	started := time.Now()
+
+	select {
+	case <-watcher.ready:
+		r.logger.Info("the application is ready", zap.String("log line", watcher.matchedLine()), zap.Duration("after", time.Since(started)))
+		return nil
+	case <-deadline.C:
+		r.logger.Warn("the application did not log the readiness log line before the readiness timeout, running the testcases anyway", zap.String("readiness log pattern", r.config.Test.ReadinessLogPattern), zap.Duration("timeout", timeout))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,71 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// referenceResponse replays the request of the test case against the reference implementation at the reference base path.
+func (r *Replayer) referenceResponse(ctx context.Context, appID uint64, testCase *models.TestCase, testSetID string) (*models.HTTPResp, error) {
+	refCase := *testCase
+	if testCase.Kind == models.GRPC_EXPORT {
+		base, err := url.Parse(r.config.Test.ReferenceBasePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the reference base path: %w", err)
+		}
+		headers := make(map[string]string, len(testCase.GrpcReq.Headers.PseudoHeaders))
+		for key, value := range testCase.GrpcReq.Headers.PseudoHeaders {
+			headers[key] = value
+		}
+		headers[":authority"] = base.Host
+		refCase.GrpcReq.Headers.PseudoHeaders = headers
+	} else if testCase.Kind == models.WS {
+		base, err := url.Parse(r.config.Test.ReferenceBasePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the reference base path: %w", err)
+		}
+		u, err := url.Parse(testCase.WSReq.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the websocket url: %w", err)
+		}
+		u.Host = base.Host
+		refCase.WSReq.URL = u.String()
+	} else {
+		refURL, err := ReplaceBaseURL(r.config.Test.ReferenceBasePath, testCase.HTTPReq.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to replace the request basePath with the reference base path: %w", err)
+		}
+		refCase.HTTPReq.URL = refURL
+	}
+	return r.emulatorFor(&refCase).SimulateRequest(ctx, appID, &refCase, testSetID)
+}
+
+// compareWithReference compares the live response of the application with the live response of the reference
+// implementation, instead of the recorded response. The noise of the test case and the test set is applied as usual.
+func (r *Replayer) compareWithReference(tc *models.TestCase, refResponse, actualResponse *models.HTTPResp, testSetID string) (bool, *models.Result) {
+	refCase := *tc
+	// the grpc response of the reference is already wrapped in a http response
+	refCase.Kind = models.HTTP
+	refCase.HTTPResp = *refResponse
+	return r.compareResp(&refCase, actualResponse, testSetID)
+}
+
+// compareTestCase compares the actual response of the test case with its recorded response, or with the live
+// response of the reference implementation when the reference base path is set.
+func (r *Replayer) compareTestCase(ctx context.Context, appID uint64, testCase *models.TestCase, actualResponse *models.HTTPResp, testSetID string) (bool, *models.Result, error) {
+	if r.config.Test.ReferenceBasePath == "" {
+		testPass, testResult := r.compareResp(testCase, actualResponse, testSetID)
+		return testPass, testResult, nil
+	}
+	refResp, err := r.referenceResponse(ctx, appID, testCase, testSetID)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to simulate request against the reference implementation: %w", err)
+	}
+	testPass, testResult := r.compareWithReference(testCase, refResp, actualResponse, testSetID)
+	return testPass, testResult, nil
+}
@@ -6,16 +6,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
-	"strconv"
+	"regexp"
 	"strings"
+	"sync"
 	"syscall"
+	"text/template"
 	"time"
 
-	"github.com/k0kubun/pp/v3"
 	"go.keploy.io/server/v2/config"
 	"go.keploy.io/server/v2/pkg"
 	"go.keploy.io/server/v2/pkg/models"
@@ -24,11 +25,6 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-var completeTestReport = make(map[string]TestReportVerdict)
-var totalTests int
-var totalTestPassed int
-var totalTestFailed int
-
 // emulator contains the struct instance that implements RequestEmulator interface. This is done for
 // attaching the objects dynamically as plugins.
 var requestMockemulator RequestMockHandler
@@ -46,12 +42,52 @@ type Replayer struct {
 	telemetry       Telemetry
 	instrumentation Instrumentation
 	config          *config.Config
+	hookMetadata    *models.HookMetadata
+	// mu guards the hook metadata and the time shifts, as the test sets may run in parallel
+	mu sync.Mutex
+	// timeShifts are the shifts of the timestamps of the test sets when the time shift replay is enabled
+	timeShifts map[string]time.Duration
+	// shuffleSeeds are the seeds the test cases are shuffled with in the randomized order, by test run
+	shuffleSeeds map[string]int64
+	firstFailure *FailedTestCase
+	grpcEmulator RequestMockHandler
+	wsEmulator   RequestMockHandler
+	// summaryWriter writes the results and the summaries in the output format
+	summaryWriter SummaryWriter
+	// istanbul collects the coverage of the js apps, nil unless the istanbul coverage driver is used
+	istanbul *istanbulCollector
+	// requestTransformers rewrite the http requests of the test cases before they are sent, in order
+	requestTransformers []RequestTransformer
+	// sinkMu guards the result sink the test case results are streamed on and the progress sinks
+	sinkMu     sync.Mutex
+	resultSink chan<- models.TestResult
+	// progressSinks are the channels the progress events are streamed on, by the test run they stream
+	progressSinks map[chan<- models.TestProgressEvent]string
 }
 
-func NewReplayer(logger *zap.Logger, testDB TestDB, mockDB MockDB, reportDB ReportDB, testSetConf Config, telemetry Telemetry, instrumentation Instrumentation, config *config.Config) Service {
+func NewReplayer(logger *zap.Logger, testDB TestDB, mockDB MockDB, reportDB ReportDB, testSetConf Config, telemetry Telemetry, instrumentation Instrumentation, cfg *config.Config) Service {
 	// set the request emulator for simulating test case requests, if not set
 	if requestMockemulator == nil {
-		SetTestUtilInstance(NewRequestMockUtil(logger, config.Path, "mocks", config.Test.APITimeout, config.Test.BasePath))
+		opts := []RequestMockUtilOption{WithMaxResponseBodyKB(cfg.Test.MaxResponseBodyKB)}
+		if len(cfg.Test.GlobalHeaders) > 0 {
+			opts = append(opts, WithGlobalHeaders(cfg.Test.GlobalHeaders, cfg.Test.OverrideHeaders))
+		}
+		if cfg.Test.RequestSigning != nil {
+			opts = append(opts, WithRequestSigning(cfg.Test.RequestSigning))
+		}
+		if cfg.Test.BasePath != "" && hasClientTLS(cfg.Test) {
+			tlsConfig, err := pkg.ClientTLSConfig(cfg.Test.ClientCert, cfg.Test.ClientKey, cfg.Test.ClientKeyPassphrase, cfg.Test.CACert, cfg.Test.InsecureSkipVerify)
+			if err != nil {
+				utils.LogError(logger, err, "failed to build the tls config of the requests, sending them without it")
+			} else {
+				opts = append(opts, WithClientTLS(tlsConfig))
+			}
+		}
+		SetTestUtilInstance(NewRequestMockUtil(logger, config.StoragePath(cfg), "mocks", cfg.Test.APITimeout, cfg.Test.BasePath, opts...))
+	}
+	var istanbul *istanbulCollector
+	if cfg.Test.CoverageDriver == IstanbulCoverageDriver {
+		istanbul = newIstanbulCollector(cfg.Test.APITimeout)
 	}
 	return &Replayer{
 		logger:          logger,
@@ -61,7 +97,11 @@ func NewReplayer(logger *zap.Logger, testDB TestDB, mockDB MockDB, reportDB Repo
 		testSetConf:     testSetConf,
 		telemetry:       telemetry,
 		instrumentation: instrumentation,
-		config:          config,
+		config:          cfg,
+		grpcEmulator:    newGrpcRequestEmulator(logger, cfg.Test.APITimeout, requestMockemulator),
+		wsEmulator:      NewWSMockHandler(logger, cfg.Test.APITimeout, requestMockemulator),
+		summaryWriter:   newSummaryWriter(cfg.Output.Format, os.Stdout),
+		istanbul:        istanbul,
 	}
 }
 
@@ -74,6 +114,9 @@ func (r *Replayer) Start(ctx context.Context) error {
 	var stopReason = "replay completed successfully"
 	var hookCancel context.CancelFunc
 
+	// sending the buffered telemetry events at the end of the run
+	defer r.telemetry.Flush()
+
 	// defering the stop function to stop keploy in case of any error in record or in case of context cancellation
 	defer func() {
 		select {
@@ -111,6 +154,60 @@ func (r *Replayer) Start(ctx context.Context) error {
 		return fmt.Errorf(errMsg)
 	}
 
+	if shardTotal := r.config.Test.ShardTotal; shardTotal > 1 {
+		testSetIDs, err = shardTestSetIDs(testSetIDs, r.config.Test.ShardIndex, shardTotal)
+		if err != nil {
+			stopReason = "failed to shard the test sets"
+			utils.LogError(r.logger, err, stopReason)
+			return err
+		}
+		r.logger.Info("running the test sets of the shard", zap.Int("shard index", r.config.Test.ShardIndex), zap.Int("shard total", shardTotal), zap.Strings("test sets", testSetIDs))
+		if len(testSetIDs) == 0 {
+			stopReason = "no test set in the shard"
+			r.logger.Info(stopReason)
+			return nil
+		}
+	}
+
+	if since := r.config.Test.RunTestSetsSince; !since.IsZero() {
+		testSetIDs = r.testSetsModifiedSince(testSetIDs, since)
+		if len(testSetIDs) == 0 {
+			stopReason = "no test set modified since the given time"
+			r.logger.Info(stopReason, zap.Time("since", since))
+			return nil
+		}
+	}
+
+	if r.config.Test.DryRun {
+		stopReason = "dry run completed"
+		err = r.dryRun(ctx, testSetIDs)
+		if err != nil {
+			stopReason = fmt.Sprintf("failed to list the test cases: %v", err)
+			utils.LogError(r.logger, err, stopReason)
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			return fmt.Errorf(stopReason)
+		}
+		return nil
+	}
+
+	if r.config.Test.Deduplication != "" {
+		for _, testSetID := range testSetIDs {
+			if _, ok := r.config.Test.SelectedTests[testSetID]; !ok && len(r.config.Test.SelectedTests) != 0 {
+				continue
+			}
+			removed, err := r.DeduplicateTestCases(ctx, testSetID, models.DedupStrategy(r.config.Test.Deduplication))
+			if err != nil {
+				utils.LogError(r.logger, err, "failed to deduplicate the test cases", zap.String("test-set", testSetID))
+				continue
+			}
+			if removed > 0 {
+				r.logger.Info(fmt.Sprintf("removed %d duplicate test cases", removed), zap.String("test-set", testSetID))
+			}
+		}
+	}
+
 	testRunID, err := r.GetNextTestRunID(ctx)
 	if err != nil {
 		stopReason = fmt.Sprintf("failed to get next test run id: %v", err)
@@ -134,49 +231,79 @@ func (r *Replayer) Start(ctx context.Context) error {
 
 	hookCancel = inst.HookCancel
 
+	if r.config.Test.AutoDenoise {
+		stopReason = "auto denoise completed"
+		err = r.autoDenoiseTestSets(ctx, testSetIDs, inst.AppID)
+		if err != nil {
+			stopReason = fmt.Sprintf("failed to auto denoise the test sets: %v", err)
+			utils.LogError(r.logger, err, stopReason)
+			return err
+		}
+		return nil
+	}
+
+	state := newRunState()
+	if r.config.Test.JUnitReportPath != "" {
+		state.junit = &junitReporter{}
+	}
+	r.mu.Lock()
+	r.firstFailure = nil
+	r.mu.Unlock()
+
 	testSetResult := false
 	testRunResult := true
 	abortTestRun := false
-	for _, testSetID := range testSetIDs {
-		if _, ok := r.config.Test.SelectedTests[testSetID]; !ok && len(r.config.Test.SelectedTests) != 0 {
-			continue
-		}
-		requestMockemulator.ProcessMockFile(ctx, testSetID)
-		testSetStatus, err := r.RunTestSet(ctx, testSetID, testRunID, inst.AppID, false)
+	if r.config.Test.MaxParallel > 1 {
+		var userAbort bool
+		testRunResult, abortTestRun, userAbort, err = r.runTestSetsInParallel(ctx, state, testSetIDs, testRunID, inst)
 		if err != nil {
 			stopReason = fmt.Sprintf("failed to run test set: %v", err)
 			utils.LogError(r.logger, err, stopReason)
-			if err == context.Canceled {
+			if errors.Is(err, context.Canceled) {
 				return err
 			}
 			return fmt.Errorf(stopReason)
 		}
-		switch testSetStatus {
-		case models.TestSetStatusAppHalted:
-			testSetResult = false
-			abortTestRun = true
-		case models.TestSetStatusInternalErr:
-			testSetResult = false
-			abortTestRun = true
-		case models.TestSetStatusFaultUserApp:
-			testSetResult = false
-			abortTestRun = true
-		case models.TestSetStatusUserAbort:
+		if userAbort {
 			return nil
-		case models.TestSetStatusFailed:
-			testSetResult = false
-		case models.TestSetStatusPassed:
-			testSetResult = true
-			requestMockemulator.ProcessTestRunStatus(ctx, testSetResult, testSetID)
-		}
-		testRunResult = testRunResult && testSetResult
-		if abortTestRun {
-			break
 		}
+	} else {
+		for _, testSetID := range testSetIDs {
+			if _, ok := r.config.Test.SelectedTests[testSetID]; !ok && len(r.config.Test.SelectedTests) != 0 {
+				continue
+			}
+			requestMockemulator.ProcessMockFile(ctx, testSetID)
+			testSetStatus, verdict, err := r.RunTestSet(ctx, testSetID, testRunID, inst.AppID, false)
+			if err != nil {
+				stopReason = fmt.Sprintf("failed to run test set: %v", err)
+				utils.LogError(r.logger, err, stopReason)
+				if err == context.Canceled {
+					return err
+				}
+				return fmt.Errorf(stopReason)
+			}
+			if testSetStatus == models.TestSetStatusUserAbort {
+				return nil
+			}
+			err = r.checkOrderSensitivity(ctx, testRunID, testSetID, inst.AppID, testSetStatus)
+			if err != nil {
+				return err
+			}
+			state.addTestSet(testSetID, verdict)
+			testSetResult, abortTestRun = r.processTestSetStatus(ctx, testRunID, testSetID, testSetStatus)
+			testRunResult = testRunResult && testSetResult
+			if abortTestRun {
+				break
+			}
 
-		_, err = requestMockemulator.AfterTestHook(ctx, testRunID, testSetID, len(testSetIDs))
-		if err != nil {
-			utils.LogError(r.logger, err, "failed to get after test hook")
+			_, err = requestMockemulator.AfterTestHook(ctx, testRunID, testSetID, len(testSetIDs))
+			if err != nil {
+				utils.LogError(r.logger, err, "failed to get after test hook")
+			}
+
+			if r.failedFast() {
+				break
+			}
 		}
 	}
 
@@ -185,12 +312,84 @@ func (r *Replayer) Start(ctx context.Context) error {
 		testRunStatus = "pass"
 	}
 
+	_, totalTestPassed, totalTestFailed := state.totals()
 	r.telemetry.TestRun(totalTestPassed, totalTestFailed, len(testSetIDs), testRunStatus)
 
+	// the summary is written even if the test run is aborted, so that the partial results are not lost
+	if r.config.Test.SummaryJSONPath != "" {
+		err = writeJSONSummary(r.config.Test.SummaryJSONPath, state, testRunID, testRunResult, abortTestRun, r.getFirstFailure())
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to write the json summary", zap.String("path", r.config.Test.SummaryJSONPath))
+		}
+	}
+
+	if r.config.Test.SARIFOutputPath != "" {
+		err = r.writeSARIFReport(ctx, r.config.Test.SARIFOutputPath, state, testRunID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to write the sarif report", zap.String("path", r.config.Test.SARIFOutputPath))
+		} else {
+			r.logger.Info("sarif report is written", zap.String("path", r.config.Test.SARIFOutputPath))
+		}
+	}
+
+	if r.istanbul != nil {
+		coveragePath := filepath.Join("coverage", "coverage-final.json")
+		err = r.istanbul.write(coveragePath)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to write the istanbul coverage", zap.String("path", coveragePath))
+		} else {
+			r.logger.Info("istanbul coverage is written", zap.String("path", coveragePath))
+		}
+	}
+
+	if r.config.Test.JUnitReportPath != "" {
+		err = state.junit.write(r.config.Test.JUnitReportPath, testRunID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to write the junit report", zap.String("path", r.config.Test.JUnitReportPath))
+		} else {
+			r.logger.Info("junit report is written", zap.String("path", r.config.Test.JUnitReportPath))
+		}
+	}
+
+	var coverageErr error
 	if !abortTestRun {
-		r.printSummary(ctx, testRunResult)
+		coverageErr = r.printSummary(ctx, state, testRunID, testRunResult)
+
+		if r.config.Test.RemoteReportURL != "" {
+			err = utils.UploadReports(ctx, r.logger, filepath.Join(config.StoragePath(r.config), "reports", testRunID), r.config.Test.RemoteReportURL)
+			if err != nil {
+				utils.LogError(r.logger, err, "failed to upload the reports", zap.String("url", r.config.Test.RemoteReportURL))
+			}
+		}
+
+		if r.config.Test.CIPRComment != nil {
+			err = r.postPRComment(ctx, state, testRunID, testRunResult)
+			if err != nil {
+				utils.LogError(r.logger, err, "failed to post the test run summary as PR comment")
+			}
+		}
 	}
-	return nil
+
+	if r.config.Test.CoverageMerge && utils.CmdType(r.config.CommandType) == utils.Native && r.config.Test.GoCoverage {
+		err = r.mergeGoCoverage(ctx)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to merge the go coverage with the coverage of the previous runs")
+		}
+	}
+
+	// the exit hooks of the CI integrations, an aborted test run is a failure
+	exitHook := r.config.Test.OnFailure
+	if testRunResult && !abortTestRun && coverageErr == nil {
+		exitHook = r.config.Test.OnSuccess
+	}
+	if exitHook != "" {
+		r.logger.Info("Running the exit hook", zap.String("script", exitHook), zap.String("test run status", testRunStatus))
+		err = r.executeScript(ctx, exitHook, "", testRunID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to execute the exit hook", zap.String("script", exitHook))
+		}
+	}
+	return coverageErr
 }
 
 func (r *Replayer) Instrument(ctx context.Context) (*InstrumentState, error) {
@@ -208,6 +407,7 @@ func (r *Replayer) Instrument(ctx context.Context) (*InstrumentState, error) {
 	}
 
 	var cancel context.CancelFunc
+	var hookMetadata *models.HookMetadata
 	// starting the hooks and proxy
 	select {
 	case <-ctx.Done():
@@ -215,7 +415,7 @@ func (r *Replayer) Instrument(ctx context.Context) (*InstrumentState, error) {
 	default:
 		hookCtx := context.WithoutCancel(ctx)
 		hookCtx, cancel = context.WithCancel(hookCtx)
-		err = r.instrumentation.Hook(hookCtx, appID, models.HookOptions{Mode: models.MODE_TEST, EnableTesting: r.config.EnableTesting})
+		hookMetadata, err = r.instrumentation.Hook(hookCtx, appID, models.HookOptions{Mode: models.MODE_TEST, EnableTesting: r.config.EnableTesting})
 		if err != nil {
 			cancel()
 			if errors.Is(err, context.Canceled) {
@@ -224,7 +424,47 @@ func (r *Replayer) Instrument(ctx context.Context) (*InstrumentState, error) {
 			return &InstrumentState{}, fmt.Errorf("failed to start the hooks and proxy: %w", err)
 		}
 	}
-	return &InstrumentState{AppID: appID, HookCancel: cancel}, nil
+	if hookMetadata != nil {
+		r.logger.Info("hooks and proxy started", zap.Int("proxy port", hookMetadata.ProxyPort), zap.Strings("loaded hooks", hookMetadata.LoadedHooks), zap.Duration("start duration", hookMetadata.StartDuration))
+	}
+	r.mu.Lock()
+	r.hookMetadata = hookMetadata
+	r.mu.Unlock()
+	return &InstrumentState{AppID: appID, HookCancel: cancel, HookMetadata: hookMetadata}, nil
+}
+
+// processTestSetStatus returns whether the test set passed and whether the test run should be aborted
+// because of the status of the test set, accepting the failures of the test set if enabled.
+func (r *Replayer) processTestSetStatus(ctx context.Context, testRunID, testSetID string, testSetStatus models.TestSetStatus) (testSetResult bool, abortTestRun bool) {
+	switch testSetStatus {
+	case models.TestSetStatusAppHalted:
+		testSetResult = false
+		abortTestRun = true
+	case models.TestSetStatusInternalErr:
+		testSetResult = false
+		abortTestRun = true
+	case models.TestSetStatusFaultUserApp:
+		testSetResult = false
+		abortTestRun = true
+	case models.TestSetStatusTimedOut:
+		// the remaining test sets still run, only the timed out one fails
+		testSetResult = false
+	case models.TestSetStatusFailed:
+		testSetResult = false
+		if r.config.Test.AutoAccept {
+			r.acceptFailures(ctx, testRunID, testSetID)
+		}
+	case models.TestSetStatusPassed:
+		testSetResult = true
+		requestMockemulator.ProcessTestRunStatus(ctx, testSetResult, testSetID)
+	}
+	return testSetResult, abortTestRun
+}
+
+func (r *Replayer) getHookMetadata() *models.HookMetadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.hookMetadata
 }
 
 func (r *Replayer) GetNextTestRunID(ctx context.Context) (string, error) {
@@ -242,7 +482,25 @@ func (r *Replayer) GetAllTestSetIDs(ctx context.Context) ([]string, error) {
 	return r.testDB.GetAllTestSetIDs(ctx)
 }
 
-func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID string, appID uint64, serveTest bool) (models.TestSetStatus, error) {
+func (r *Replayer) FetchTestCase(ctx context.Context, testSetID, testCaseID string) (*models.TestCase, error) {
+	return r.testDB.GetTestCase(ctx, testSetID, testCaseID)
+}
+
+func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID string, appID uint64, serveTest bool) (models.TestSetStatus, TestSetVerdict, error) {
+	// the test sets run by the serve mode are not part of a run of Start, which flushes the telemetry at its end
+	if serveTest {
+		defer r.telemetry.Flush()
+	}
+	// bound the wall-clock time of the test set, the partial report is still written when the deadline is exceeded
+	if r.config.Test.TestSetTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Test.TestSetTimeout)
+		defer cancel()
+	}
+	timedOut := func() bool {
+		return r.config.Test.TestSetTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	}
+
 	// creating error group to manage proper shutdown of all the go routines and to propagate the error to the caller
 	runTestSetErrGrp, runTestSetCtx := errgroup.WithContext(ctx)
 	runTestSetCtx = context.WithValue(runTestSetCtx, models.ErrGroupKey, runTestSetErrGrp)
@@ -268,17 +526,17 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 		//Execute the Pre-script before each test-set if provided
 		conf, err = r.testSetConf.Read(runTestSetCtx, testSetID)
 		if err != nil {
-			return models.TestSetStatusFailed, fmt.Errorf("failed to read test set config: %w", err)
+			return models.TestSetStatusFailed, TestSetVerdict{}, fmt.Errorf("failed to read test set config: %w", err)
 		}
 		if conf == nil {
-			return models.TestSetStatusFailed, fmt.Errorf("test set config not found")
+			return models.TestSetStatusFailed, TestSetVerdict{}, fmt.Errorf("test set config not found")
 		}
 		postscript = conf.PostScript
 
 		r.logger.Info("Running Pre-script", zap.String("script", conf.PreScript), zap.String("test-set", testSetID))
-		err = r.executeScript(runTestSetCtx, conf.PreScript)
+		err = r.executeScript(runTestSetCtx, conf.PreScript, testSetID, testRunID)
 		if err != nil {
-			return models.TestSetStatusFaultScript, fmt.Errorf("failed to execute pre-script: %w", err)
+			return models.TestSetStatusFaultScript, TestSetVerdict{}, fmt.Errorf("failed to execute pre-script: %w", err)
 		}
 	}
 
@@ -286,7 +544,12 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 	var appErr models.AppError
 	var success int
 	var failure int
-	var totalConsumedMocks = map[string]bool{}
+	var recorded int
+	// the test cases consuming each mock
+	var totalConsumedMocks = map[string][]string{}
+	var fuzzyMatchedMocks = map[string]bool{}
+	// the chained variables are extracted and substituted within the run of the test set only
+	chain := newRequestChain(r.config.Test.Chaining[testSetID])
 
 	testSetStatus := models.TestSetStatusPassed
 	testSetStatusByErrChan := models.TestSetStatusRunning
@@ -295,26 +558,45 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 
 	testCases, err := r.testDB.GetTestCases(runTestSetCtx, testSetID)
 	if err != nil {
-		return models.TestSetStatusFailed, fmt.Errorf("failed to get test cases: %w", err)
+		return models.TestSetStatusFailed, TestSetVerdict{}, fmt.Errorf("failed to get test cases: %w", err)
 	}
 
 	if len(testCases) == 0 {
-		return models.TestSetStatusPassed, nil
+		return models.TestSetStatusPassed, TestSetVerdict{}, nil
+	}
+	// the names are indexed by the order of the test set, before the test cases are filtered or shuffled
+	testCaseNames := r.testCaseNames(testCases)
+
+	if r.config.Test.TimeShiftReplay {
+		r.shiftTestCases(testSetID, testCases)
 	}
 
 	cmdType := utils.CmdType(r.config.CommandType)
 	var userIP string
+	var snapshotID string
 
-	err = r.SetupOrUpdateMocks(runTestSetCtx, appID, testSetID, models.BaseTime, time.Now(), Start)
+	// the mocks are in the shifted time, so every mock recorded until now is before now plus the shift
+	err = r.SetupOrUpdateMocks(runTestSetCtx, appID, testSetID, models.BaseTime, time.Now().Add(r.timeShift(testSetID)), Start)
 	if err != nil {
-		return models.TestSetStatusFailed, err
+		return models.TestSetStatusFailed, TestSetVerdict{}, err
 	}
 
 	if r.config.Test.BasePath == "" {
+		// the readiness log line can only be watched for in the output of the app started by keploy
+		var logWatcher *logLineWatcher
+		runOpts := models.RunOptions{}
+		if r.config.Test.ReadinessLogPattern != "" && !serveTest {
+			pattern, err := regexp.Compile(r.config.Test.ReadinessLogPattern)
+			if err != nil {
+				return models.TestSetStatusFailed, TestSetVerdict{}, fmt.Errorf("invalid readiness log pattern: %w", err)
+			}
+			logWatcher = newLogLineWatcher(pattern)
+			runOpts.Stdout, runOpts.Stderr = logWatcher.stream(), logWatcher.stream()
+		}
 		if !serveTest {
 			runTestSetErrGrp.Go(func() error {
 				defer utils.Recover(r.logger)
-				appErr = r.RunApplication(runTestSetCtx, appID, models.RunOptions{})
+				appErr = r.RunApplication(runTestSetCtx, appID, runOpts)
 				if appErr.AppErrorType == models.ErrCtxCanceled {
 					return nil
 				}
@@ -351,17 +633,37 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 			return nil
 		})
 
-		// Delay for user application to run
-		select {
-		case <-time.After(time.Duration(r.config.Test.Delay) * time.Second):
-		case <-runTestSetCtx.Done():
-			return models.TestSetStatusUserAbort, context.Canceled
+		// Delay for user application to run, or until it logs its readiness line or its readiness probe succeeds
+		if logWatcher != nil {
+			if r.waitForLogLine(runTestSetCtx, logWatcher) != nil && !timedOut() {
+				return models.TestSetStatusUserAbort, TestSetVerdict{}, context.Canceled
+			}
+		} else if r.config.Test.ReadinessProbe != "" {
+			if r.waitUntilReady(runTestSetCtx, appID) != nil && !timedOut() {
+				return models.TestSetStatusUserAbort, TestSetVerdict{}, context.Canceled
+			}
+		} else {
+			select {
+			case <-time.After(time.Duration(r.config.Test.Delay) * time.Second):
+			case <-runTestSetCtx.Done():
+				if !timedOut() {
+					return models.TestSetStatusUserAbort, TestSetVerdict{}, context.Canceled
+				}
+			}
 		}
 
-		if utils.IsDockerKind(cmdType) {
+		if utils.IsDockerKind(cmdType) && !timedOut() {
 			userIP, err = r.instrumentation.GetContainerIP(ctx, appID)
 			if err != nil {
-				return models.TestSetStatusFailed, err
+				return models.TestSetStatusFailed, TestSetVerdict{}, err
+			}
+		}
+
+		// the app is warmed up, every test case starts from this state
+		if r.config.Test.UseSnapshot && !timedOut() {
+			snapshotID, err = r.instrumentation.TakeSnapshot(runTestSetCtx, appID)
+			if err != nil {
+				return models.TestSetStatusFailed, TestSetVerdict{}, fmt.Errorf("failed to take the snapshot of the app: %w", err)
 			}
 		}
 	}
@@ -374,6 +676,26 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 		testCasesCount = len(selectedTests)
 	}
 
+	// the name filter narrows down the selected tests, the totals of the report count only the remaining ones
+	if r.config.Test.TestNameFilter != "" {
+		testCases, err = r.filterTestCasesByName(testCases, selectedTests)
+		if err != nil {
+			return models.TestSetStatusFailed, TestSetVerdict{}, err
+		}
+		testCasesCount = len(testCases)
+	}
+
+	if r.keepsRecordedOrder(testSetID) && (r.config.Test.OrderByLastFailure || r.config.Test.RandomizeOrder) {
+		r.logger.Warn("running the test cases in the recorded order as the test set has chaining rules", zap.String("test-set", testSetID))
+	} else {
+		if r.config.Test.OrderByLastFailure {
+			testCases = r.orderByLastFailure(runTestSetCtx, testRunID, testSetID, testCases)
+		}
+		if r.config.Test.RandomizeOrder {
+			testCases = r.shuffleTestCases(testRunID, testSetID, testCases)
+		}
+	}
+
 	// Inserting the initial report for the test set
 	testReport := &models.TestReport{
 		Version: models.GetVersion(),
@@ -384,29 +706,63 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 	err = r.reportDB.InsertReport(runTestSetCtx, testRunID, testSetID, testReport)
 	if err != nil {
 		utils.LogError(r.logger, err, "failed to insert report")
-		return models.TestSetStatusFailed, err
+		return models.TestSetStatusFailed, TestSetVerdict{}, err
 	}
 
+	// log the noise used for the comparisons, overly broad noise can silently mask regressions
+	appliedNoise := r.testSetNoise(testSetID)
+	r.logger.Debug("effective noise config for the test set", zap.String("test-set", testSetID), zap.Any("noise", appliedNoise))
+
 	// var to exit the loop
 	var exitLoop bool
 	// var to store the error in the loop
 	var loopErr error
 
+	progress := r.newTestSetProgress(testRunID, testSetID, testCasesCount)
+
+	if workers := r.testCaseWorkers(testSetID); workers > 1 {
+		var parallelCases []*models.TestCase
+		for _, testCase := range testCases {
+			if _, ok := selectedTests[testCase.Name]; ok || len(selectedTests) == 0 {
+				parallelCases = append(parallelCases, testCase)
+			}
+		}
+		run, err := r.runTestCasesInParallel(runTestSetCtx, appID, testRunID, testSetID, parallelCases, userIP, chain, exitLoopChan, workers, progress)
+		if err != nil {
+			loopErr = err
+		}
+		success, failure, recorded = int(run.success), int(run.failure), int(run.recorded)
+		if run.failed {
+			testSetStatus = models.TestSetStatusFailed
+		}
+		if run.aborted {
+			testSetStatus = testSetStatusByErrChan
+		}
+		// every test case has already been run by the workers
+		testCases = nil
+	}
+
+	caseRun := testCaseRun{testRunID: testRunID, testSetID: testSetID, userIP: userIP, serial: true, chain: chain}
 	for _, testCase := range testCases {
 
 		if _, ok := selectedTests[testCase.Name]; !ok && len(selectedTests) != 0 {
 			continue
 		}
 
-		// replace the request URL's BasePath/origin if provided
-		if r.config.Test.BasePath != "" {
-			newURL, err := ReplaceBaseURL(r.config.Test.BasePath, testCase.HTTPReq.URL)
-			if err != nil {
-				r.logger.Warn("failed to replace the request basePath", zap.String("testcase", testCase.Name), zap.String("basePath", r.config.Test.BasePath), zap.Error(err))
-			} else {
-				testCase.HTTPReq.URL = newURL
-			}
-			r.logger.Debug("test case request origin", zap.String("testcase", testCase.Name), zap.String("TestCaseURL", testCase.HTTPReq.URL), zap.String("basePath", r.config.Test.BasePath))
+		// a test case of another test set running concurrently has already failed
+		if r.failedFast() {
+			break
+		}
+
+		if r.maxFailuresReached(testSetID, failure) {
+			testSetStatus = models.TestSetStatusFailed
+			break
+		}
+
+		// the test cases failing without a result (e.g. their request failed) stop the test set as well
+		if r.config.Test.FailFast && failure > 0 {
+			testSetStatus = models.TestSetStatusFailed
+			break
 		}
 
 		// Checking for errors in the mocking and application
@@ -421,99 +777,70 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 			break
 		}
 
-		var testStatus models.TestStatus
-		var testResult *models.Result
-		var testPass bool
-		var loopErr error
-
-		//No need to handle mocking when basepath is provided
-		err := r.SetupOrUpdateMocks(runTestSetCtx, appID, testSetID, testCase.HTTPReq.Timestamp, testCase.HTTPResp.Timestamp, Update)
-		if err != nil {
-			utils.LogError(r.logger, err, "failed to update mocks")
+		if timedOut() {
 			break
 		}
 
-		if utils.IsDockerKind(cmdType) && r.config.Test.BasePath == "" {
-
-			testCase.HTTPReq.URL, err = utils.ReplaceHostToIP(testCase.HTTPReq.URL, userIP)
+		if snapshotID != "" {
+			err := r.instrumentation.RestoreSnapshot(runTestSetCtx, appID, snapshotID)
 			if err != nil {
-				utils.LogError(r.logger, err, "failed to replace host to docker container's IP")
+				utils.LogError(r.logger, err, "failed to restore the snapshot of the app", zap.String("testcase", testCase.Name))
+				testSetStatus = models.TestSetStatusInternalErr
 				break
 			}
-			r.logger.Debug("", zap.Any("replaced URL in case of docker env", testCase.HTTPReq.URL))
 		}
 
-		started := time.Now().UTC()
-		resp, loopErr := requestMockemulator.SimulateRequest(runTestSetCtx, appID, testCase, testSetID)
-		if loopErr != nil {
-			utils.LogError(r.logger, err, "failed to simulate request")
-			failure++
-			continue
+		//No need to handle mocking when basepath is provided
+		err := r.SetupOrUpdateMocks(runTestSetCtx, appID, testSetID, testCase.HTTPReq.Timestamp, testCase.HTTPResp.Timestamp, Update)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to update mocks")
+			break
 		}
 
-		var consumedMocks []string
-		if r.config.Test.BasePath == "" {
-			consumedMocks, err = r.instrumentation.GetConsumedMocks(runTestSetCtx, appID)
-			if err != nil {
-				utils.LogError(r.logger, err, "failed to get consumed filtered mocks")
-			}
-			if r.config.Test.RemoveUnusedMocks {
-				for _, mockName := range consumedMocks {
-					totalConsumedMocks[mockName] = true
-				}
-			}
+		progress.start(testCase.Name)
+		started := time.Now()
+		outcome := r.replayTestCase(runTestSetCtx, appID, caseRun, testCase)
+		for _, mockName := range outcome.consumedMocks {
+			totalConsumedMocks[mockName] = append(totalConsumedMocks[mockName], testCase.Name)
 		}
-
-		testPass, testResult = r.compareResp(testCase, resp, testSetID)
-		if !testPass {
-			// log the consumed mocks during the test run of the test case for test set
-			r.logger.Info("result", zap.Any("testcase id", models.HighlightFailingString(testCase.Name)), zap.Any("testset id", models.HighlightFailingString(testSetID)), zap.Any("passed", models.HighlightFailingString(testPass)))
-			r.logger.Debug("Consumed Mocks", zap.Any("mocks", consumedMocks))
-		} else {
-			r.logger.Info("result", zap.Any("testcase id", models.HighlightPassingString(testCase.Name)), zap.Any("testset id", models.HighlightPassingString(testSetID)), zap.Any("passed", models.HighlightPassingString(testPass)))
+		for _, mockName := range outcome.fuzzyMocks {
+			fuzzyMatchedMocks[mockName] = true
 		}
-		if testPass {
-			testStatus = models.TestStatusPassed
-			success++
-		} else {
-			testStatus = models.TestStatusFailed
+		if outcome.recorded {
+			recorded++
+			progress.end(testCase.Name, models.TestStatusRecorded)
+			continue
+		}
+		testCaseResult := outcome.result
+		switch {
+		case testCaseResult == nil:
+			// the test cases failing without a result (e.g. their request failed) fail the test set as well
+			failure++
+			testSetStatus = models.TestSetStatusFailed
+			progress.end(testCase.Name, models.TestStatusFailed)
+			continue
+		case testCaseResult.Status != models.TestStatusPassed:
 			failure++
 			testSetStatus = models.TestSetStatusFailed
+		default:
+			success++
 		}
 
-		if testResult != nil {
-			testCaseResult := &models.TestResult{
-				Kind:       models.HTTP,
-				Name:       testSetID,
-				Status:     testStatus,
-				Started:    started.Unix(),
-				Completed:  time.Now().UTC().Unix(),
-				TestCaseID: testCase.Name,
-				Req: models.HTTPReq{
-					Method:     testCase.HTTPReq.Method,
-					ProtoMajor: testCase.HTTPReq.ProtoMajor,
-					ProtoMinor: testCase.HTTPReq.ProtoMinor,
-					URL:        testCase.HTTPReq.URL,
-					URLParams:  testCase.HTTPReq.URLParams,
-					Header:     testCase.HTTPReq.Header,
-					Body:       testCase.HTTPReq.Body,
-					Binary:     testCase.HTTPReq.Binary,
-					Form:       testCase.HTTPReq.Form,
-					Timestamp:  testCase.HTTPReq.Timestamp,
-				},
-				Res:          *resp,
-				TestCasePath: filepath.Join(r.config.Path, testSetID),
-				MockPath:     filepath.Join(r.config.Path, testSetID, requestMockemulator.FetchMockName()),
-				Noise:        testCase.Noise,
-				Result:       *testResult,
-			}
-			loopErr = r.reportDB.InsertTestCaseResult(runTestSetCtx, testRunID, testSetID, testCaseResult)
-			if loopErr != nil {
-				utils.LogError(r.logger, err, "failed to insert test case result")
-				break
-			}
-		} else {
-			utils.LogError(r.logger, nil, "test result is nil")
+		loopErr = r.reportDB.InsertTestCaseResult(runTestSetCtx, testRunID, testSetID, testCaseResult)
+		if loopErr != nil {
+			utils.LogError(r.logger, loopErr, "failed to insert test case result")
+			break
+		}
+		r.sendResult(testCaseResult)
+		progress.end(testCase.Name, testCaseResult.Status)
+		if err := r.summaryWriter.TestCaseResult(testSetID, testCaseResult, time.Since(started)); err != nil {
+			utils.LogError(r.logger, err, "failed to write the test case result")
+		}
+
+		// stop at the first failing test case, the partial report is still written below
+		if testCaseResult.Status != models.TestStatusPassed && r.config.Test.FailFast {
+			r.recordFirstFailure(testSetID, testCase.Name)
+			r.logger.Warn("stopping the test run at the first failing test case as fail fast is enabled", zap.String("testcase", testCase.Name), zap.String("test-set", testSetID))
 			break
 		}
 
@@ -524,21 +851,34 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 		}
 	}
 
+	// the post-script and the report of the timed out test set must not be cut off by the expired deadline
+	cleanupCtx := runTestSetCtx
+	if timedOut() {
+		r.logger.Warn("test set timed out, writing the partial report", zap.String("test-set", testSetID), zap.Duration("timeout", r.config.Test.TestSetTimeout))
+		cleanupCtx = context.WithoutCancel(runTestSetCtx)
+	}
+
 	//Execute the Post-script after each test-set if provided
 	if r.config.Test.BasePath != "" {
 		r.logger.Info("Running Post-script", zap.String("script", postscript), zap.String("test-set", testSetID))
-		err = r.executeScript(runTestSetCtx, postscript)
+		err = r.executeScript(cleanupCtx, postscript, testSetID, testRunID)
 		if err != nil {
-			return models.TestSetStatusFaultScript, fmt.Errorf("failed to execute post-script: %w", err)
+			return models.TestSetStatusFaultScript, TestSetVerdict{}, fmt.Errorf("failed to execute post-script: %w", err)
 		}
 	}
-	testCaseResults, err := r.reportDB.GetTestCaseResults(runTestSetCtx, testRunID, testSetID)
+	if recorded > 0 {
+		r.logger.Info(fmt.Sprintf("Recorded %d new test cases", recorded), zap.String("test-set", testSetID))
+		testCasesCount -= recorded
+	}
+
+	testCaseResults, err := r.reportDB.GetTestCaseResults(cleanupCtx, testRunID, testSetID)
 	if err != nil {
-		if runTestSetCtx.Err() != context.Canceled {
+		if cleanupCtx.Err() != context.Canceled {
 			utils.LogError(r.logger, err, "failed to get test case results")
 			testSetStatus = models.TestSetStatusInternalErr
 		}
 	}
+	setTestCaseNames(testCaseResults, testCaseNames)
 
 	// Checking errors for final iteration
 	// Checking for errors in the loop
@@ -553,14 +893,26 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 		}
 	}
 
+	if timedOut() {
+		testSetStatus = models.TestSetStatusTimedOut
+	}
+
 	testReport = &models.TestReport{
-		Version: models.GetVersion(),
-		TestSet: testSetID,
-		Status:  string(testSetStatus),
-		Total:   testCasesCount,
-		Success: success,
-		Failure: failure,
-		Tests:   testCaseResults,
+		Version:                models.GetVersion(),
+		TestSet:                testSetID,
+		Status:                 string(testSetStatus),
+		Total:                  testCasesCount,
+		Success:                success,
+		Failure:                failure,
+		Tests:                  testCaseResults,
+		InstrumentationDetails: r.getHookMetadata(),
+	}
+	if r.config.Test.ReportNoise {
+		testReport.AppliedNoise = appliedNoise
+	}
+	if r.config.Test.BasePath == "" {
+		testReport.ConsumedMocks = totalConsumedMocks
+		testReport.FuzzyMatchedMocks = sortedMockNames(fuzzyMatchedMocks)
 	}
 
 	// final report should have reason for sudden stop of the test run so this should get canceled
@@ -568,45 +920,47 @@ func (r *Replayer) RunTestSet(ctx context.Context, testSetID string, testRunID s
 	err = r.reportDB.InsertReport(reportCtx, testRunID, testSetID, testReport)
 	if err != nil {
 		utils.LogError(r.logger, err, "failed to insert report")
-		return models.TestSetStatusInternalErr, fmt.Errorf("failed to insert report")
+		return models.TestSetStatusInternalErr, TestSetVerdict{}, fmt.Errorf("failed to insert report")
+	}
+
+	// the mock coverage is reported before the unused mocks are removed
+	if r.config.Test.BasePath == "" {
+		mockCoverage, err := r.MockCoverageReport(reportCtx, testRunID, testSetID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to build the mock coverage report", zap.String("test-set", testSetID))
+		} else if err := r.reportDB.InsertMockCoverageReport(reportCtx, testRunID, testSetID, mockCoverage); err != nil {
+			utils.LogError(r.logger, err, "failed to insert the mock coverage report", zap.String("test-set", testSetID))
+		}
 	}
 
 	// remove the unused mocks by the test cases of a testset (if the base path is not provided )
 	if r.config.Test.RemoveUnusedMocks && testSetStatus == models.TestSetStatusPassed && r.config.Test.BasePath == "" {
 		r.logger.Debug("consumed mocks from the completed testset", zap.Any("for test-set", testSetID), zap.Any("consumed mocks", totalConsumedMocks))
 		// delete the unused mocks from the data store
-		err = r.mockDB.UpdateMocks(runTestSetCtx, testSetID, totalConsumedMocks)
+		consumed := make(map[string]bool, len(totalConsumedMocks))
+		for mockName := range totalConsumedMocks {
+			consumed[mockName] = true
+		}
+		err = r.mockDB.UpdateMocks(runTestSetCtx, testSetID, consumed)
 		if err != nil {
 			utils.LogError(r.logger, err, "failed to delete unused mocks")
 		}
 	}
 
-	// TODO Need to decide on whether to use global variable or not
-	verdict := TestReportVerdict{
-		total:  testReport.Total,
-		failed: testReport.Failure,
-		passed: testReport.Success,
-		status: testSetStatus == models.TestSetStatusPassed,
+	verdict := TestSetVerdict{
+		Total:   testReport.Total,
+		Failed:  testReport.Failure,
+		Passed:  testReport.Success,
+		Status:  testSetStatus == models.TestSetStatusPassed,
+		results: testCaseResults,
 	}
 
-	completeTestReport[testSetID] = verdict
-	totalTests += testReport.Total
-	totalTestPassed += testReport.Success
-	totalTestFailed += testReport.Failure
-
-	if testSetStatus == models.TestSetStatusFailed || testSetStatus == models.TestSetStatusPassed {
-		if testSetStatus == models.TestSetStatusFailed {
-			pp.SetColorScheme(models.FailingColorScheme)
-		} else {
-			pp.SetColorScheme(models.PassingColorScheme)
-		}
-		if _, err := pp.Printf("\n <=========================================> \n  TESTRUN SUMMARY. For test-set: %s\n"+"\tTotal tests: %s\n"+"\tTotal test passed: %s\n"+"\tTotal test failed: %s\n <=========================================> \n\n", testReport.TestSet, testReport.Total, testReport.Success, testReport.Failure); err != nil {
-			utils.LogError(r.logger, err, "failed to print testrun summary")
-		}
+	if err := r.summaryWriter.TestSetSummary(testSetID, testSetStatus, verdict); err != nil {
+		utils.LogError(r.logger, err, "failed to write the test set summary")
 	}
 
 	r.telemetry.TestSetRun(testReport.Success, testReport.Failure, testSetID, string(testSetStatus))
-	return testSetStatus, nil
+	return testSetStatus, verdict, nil
 }
 
 func (r *Replayer) GetMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time) (filtered, unfiltered []*models.Mock, err error) {
@@ -615,16 +969,30 @@ func (r *Replayer) GetMocks(ctx context.Context, testSetID string, afterTime tim
 		return nil, nil, nil
 	}
 
-	filtered, err = r.mockDB.GetFilteredMocks(ctx, testSetID, afterTime, beforeTime)
+	// the window is in the shifted time of the test cases, the mocks are filtered in their recorded time
+	shift := r.timeShift(testSetID)
+	afterTime, beforeTime = afterTime.Add(-shift), beforeTime.Add(-shift)
+
+	// the mocks recorded longer than the mock ttl ago are stale
+	var cutoff time.Time
+	if r.config.Test.MockTTL > 0 {
+		cutoff = time.Now().Add(-r.config.Test.MockTTL)
+	}
+
+	filtered, err = r.mockDB.GetFilteredMocks(ctx, testSetID, afterTime, beforeTime, cutoff)
 	if err != nil {
 		utils.LogError(r.logger, err, "failed to get filtered mocks")
 		return nil, nil, err
 	}
-	unfiltered, err = r.mockDB.GetUnFilteredMocks(ctx, testSetID, afterTime, beforeTime)
+	unfiltered, err = r.mockDB.GetUnFilteredMocks(ctx, testSetID, afterTime, beforeTime, cutoff)
 	if err != nil {
 		utils.LogError(r.logger, err, "failed to get unfiltered mocks")
 		return nil, nil, err
 	}
+	if shift != 0 {
+		shiftMocks(filtered, shift)
+		shiftMocks(unfiltered, shift)
+	}
 	return filtered, unfiltered, err
 }
 
@@ -640,13 +1008,26 @@ func (r *Replayer) SetupOrUpdateMocks(ctx context.Context, appID uint64, testSet
 		return err
 	}
 
+	for _, mocks := range append(mockWindows(filteredMocks), mockWindows(unfilteredMocks)...) {
+		violations := ValidateMockOrdering(mocks)
+		if len(violations) == 0 {
+			continue
+		}
+		r.logger.Warn("mocks are not in the temporal order, the proxy may mismatch them", zap.String("test-set", testSetID), zap.Strings("violations", violations))
+		if r.config.Test.AutoSortMocks {
+			sortMocks(mocks)
+			r.logger.Debug("sorted the mocks by the timestamp of their requests", zap.String("test-set", testSetID))
+		}
+	}
+
 	if action == Start {
 		err = r.instrumentation.MockOutgoing(ctx, appID, models.OutgoingOptions{
-			Rules:          r.config.BypassRules,
-			MongoPassword:  r.config.Test.MongoPassword,
-			SQLDelay:       time.Duration(r.config.Test.Delay),
-			FallBackOnMiss: r.config.Test.FallBackOnMiss,
-			Mocking:        r.config.Test.Mocking,
+			Rules:             r.config.BypassRules,
+			MongoPassword:     r.config.Test.MongoPassword,
+			SQLDelay:          time.Duration(r.config.Test.Delay),
+			FallBackOnMiss:    r.config.Test.FallBackOnMiss,
+			Mocking:           r.config.Test.Mocking,
+			MockMatchStrategy: models.MockMatchStrategy(r.config.Test.MockMatchStrategy),
 		})
 		if err != nil {
 			utils.LogError(r.logger, err, "failed to mock outgoing")
@@ -674,59 +1055,193 @@ func (r *Replayer) GetTestSetStatus(ctx context.Context, testRunID string, testS
 	return status, nil
 }
 
-func (r *Replayer) compareResp(tc *models.TestCase, actualResponse *models.HTTPResp, testSetID string) (bool, *models.Result) {
+// newTestResult builds the result of the test case to be inserted in the report of the test set.
+func (r *Replayer) newTestResult(testSetID string, testCase *models.TestCase, resp *models.HTTPResp, testStatus models.TestStatus, testResult *models.Result, started time.Time) *models.TestResult {
+	kind := testCase.Kind
+	if kind == "" {
+		kind = models.HTTP
+	}
+	return &models.TestResult{
+		Kind:       kind,
+		Name:       testSetID,
+		Status:     testStatus,
+		Started:    started.Unix(),
+		Completed:  time.Now().UTC().Unix(),
+		TestCaseID: testCase.Name,
+		Req: models.HTTPReq{
+			Method:     testCase.HTTPReq.Method,
+			ProtoMajor: testCase.HTTPReq.ProtoMajor,
+			ProtoMinor: testCase.HTTPReq.ProtoMinor,
+			URL:        testCase.HTTPReq.URL,
+			URLParams:  testCase.HTTPReq.URLParams,
+			Header:     testCase.HTTPReq.Header,
+			Body:       testCase.HTTPReq.Body,
+			Binary:     testCase.HTTPReq.Binary,
+			Form:       testCase.HTTPReq.Form,
+			FormFiles:  testCase.HTTPReq.FormFiles,
+			Timestamp:  testCase.HTTPReq.Timestamp,
+		},
+		Res:          *resp,
+		TestCasePath: filepath.Join(config.StoragePath(r.config), testSetID),
+		MockPath:     filepath.Join(config.StoragePath(r.config), testSetID, requestMockemulator.FetchMockName()),
+		Noise:        testCase.Noise,
+		Result:       *testResult,
+	}
+}
 
+// testSetNoise returns the effective noise config of the test set, i.e. the global noise merged with the test set noise.
+func (r *Replayer) testSetNoise(testSetID string) config.GlobalNoise {
 	noiseConfig := r.config.Test.GlobalNoise.Global
 	if tsNoise, ok := r.config.Test.GlobalNoise.Testsets[testSetID]; ok {
 		noiseConfig = LeftJoinNoise(r.config.Test.GlobalNoise.Global, tsNoise)
 	}
-	return match(tc, actualResponse, noiseConfig, r.config.Test.IgnoreOrdering, r.logger)
+	return noiseConfig
 }
 
-func (r *Replayer) printSummary(ctx context.Context, testRunResult bool) {
-	if totalTests > 0 {
-		testSuiteNames := make([]string, 0, len(completeTestReport))
-		for testSuiteName := range completeTestReport {
-			testSuiteNames = append(testSuiteNames, testSuiteName)
-		}
-		sort.SliceStable(testSuiteNames, func(i, j int) bool {
-			testSuitePartsI := strings.Split(testSuiteNames[i], "-")
-			testSuitePartsJ := strings.Split(testSuiteNames[j], "-")
-			if len(testSuitePartsI) < 3 || len(testSuitePartsJ) < 3 {
-				return testSuiteNames[i] < testSuiteNames[j]
-			}
-			testSuiteIDNumberI, err1 := strconv.Atoi(testSuitePartsI[2])
-			testSuiteIDNumberJ, err2 := strconv.Atoi(testSuitePartsJ[2])
-			if err1 != nil || err2 != nil {
-				return false
-			}
-			return testSuiteIDNumberI < testSuiteIDNumberJ
-		})
-		if _, err := pp.Printf("\n <=========================================> \n  COMPLETE TESTRUN SUMMARY. \n\tTotal tests: %s\n"+"\tTotal test passed: %s\n"+"\tTotal test failed: %s\n", totalTests, totalTestPassed, totalTestFailed); err != nil {
-			utils.LogError(r.logger, err, "failed to print test run summary")
-			return
+func (r *Replayer) compareResp(tc *models.TestCase, actualResponse *models.HTTPResp, testSetID string) (bool, *models.Result) {
+
+	noiseConfig := r.testSetNoise(testSetID)
+	if tc.Kind == models.GRPC_EXPORT {
+		// compare the grpc-status, the metadata and the decoded message like a http response
+		grpcCase := *tc
+		grpcCase.HTTPResp = grpcToHTTPResp(tc.GrpcResp)
+		return match(&grpcCase, actualResponse, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+	}
+	if tc.Kind == models.WS {
+		// compare the handshake and the server frames like a http response, the order of the frames always matters
+		wsCase := *tc
+		wsCase.HTTPResp = wsToHTTPResp(tc.WSResp)
+		return match(&wsCase, actualResponse, noiseConfig, false, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+	}
+	var pass bool
+	var res *models.Result
+	if maxBody := r.config.Test.MaxResponseBodyKB * 1024; maxBody > 0 && (actualResponse.Truncated || len(tc.HTTPResp.Body) > maxBody) {
+		// the huge bodies (e.g. file downloads) are not compared, only the status code and the headers are
+		r.logger.Warn("skipping the body comparison as the response body exceeds the max response body size", zap.String("testcase", tc.Name), zap.Int("max size (KB)", r.config.Test.MaxResponseBodyKB))
+		bodyless := *tc
+		bodyless.HTTPResp.Body = ""
+		actualBodyless := *actualResponse
+		actualBodyless.Body = ""
+		pass, res = match(&bodyless, &actualBodyless, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+	} else if pkg.IsMultipartForm(tc.HTTPResp.Header) && pkg.IsMultipartForm(actualResponse.Header) {
+		return r.compareMultipartResp(tc, actualResponse, noiseConfig)
+	} else if r.config.Test.GraphQLMode {
+		pass, res = r.compareGraphQLResp(tc, actualResponse, noiseConfig)
+	} else if comparator, ok := r.bodyComparator(); ok {
+		pass, res = r.compareRespWith(comparator, tc, actualResponse, noiseConfig)
+	} else {
+		pass, res = match(tc, actualResponse, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+	}
+	return pass, res
+}
+
+// compareMultipartResp compares the multipart/form-data responses, e.g. the file downloads, by the field name, the
+// content type and the sha256 hash of their files. The random boundaries make their bodies and content types differ,
+// so the rest of the responses is compared without them.
+func (r *Replayer) compareMultipartResp(tc *models.TestCase, actualResponse *models.HTTPResp, noiseConfig config.GlobalNoise) (bool, *models.Result) {
+	expectedContentType := contentTypeOf(tc.HTTPResp.Header)
+	actualContentType := contentTypeOf(actualResponse.Header)
+	bodyless := *tc
+	bodyless.HTTPResp.Body = ""
+	actualBodyless := *actualResponse
+	actualBodyless.Body = ""
+	actualBodyless.Header = make(map[string]string, len(actualResponse.Header))
+	for key, value := range actualResponse.Header {
+		if strings.EqualFold(key, "Content-Type") {
+			value = expectedContentType
 		}
-		if _, err := pp.Printf("\n\tTest Suite Name\t\tTotal Test\tPassed\t\tFailed\t\n"); err != nil {
-			utils.LogError(r.logger, err, "failed to print test suite summary")
-			return
+		actualBodyless.Header[key] = value
+	}
+	pass, res := match(&bodyless, &actualBodyless, noiseConfig, r.config.Test.IgnoreOrdering, r.valueTolerance(), r.config.Test.Base64JSONFields, r.logger)
+
+	expectedFiles, err := pkg.ParseMultipartFiles([]byte(tc.HTTPResp.Body), expectedContentType)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to parse the recorded multipart response of the test case", zap.String("testcase", tc.Name))
+		return false, res
+	}
+	actualFiles, err := pkg.ParseMultipartFiles([]byte(actualResponse.Body), actualContentType)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to parse the actual multipart response of the test case", zap.String("testcase", tc.Name))
+		return false, res
+	}
+	formNoise := map[string][]string{}
+	for field, regexArr := range noiseConfig["form"] {
+		formNoise[field] = regexArr
+	}
+	for field, regexArr := range tc.Noise {
+		a := strings.Split(field, ".")
+		if len(a) > 1 && a[0] == "form" {
+			formNoise[strings.Join(a[1:], ".")] = regexArr
 		}
-		for _, testSuiteName := range testSuiteNames {
-			if completeTestReport[testSuiteName].status {
-				pp.SetColorScheme(models.PassingColorScheme)
+	}
+	var filesPass bool
+	filesPass, res.FormFilesResult = CompareFormFiles(expectedFiles, actualFiles, formNoise)
+	if !filesPass {
+		r.logger.Warn("the files of the multipart response do not match the recorded ones", zap.String("testcase", tc.Name))
+	}
+	return pass && filesPass, res
+}
+
+// contentTypeOf returns the content type of the headers, whose name is matched case insensitively.
+func contentTypeOf(header map[string]string) string {
+	for key, value := range header {
+		if strings.EqualFold(key, "Content-Type") {
+			return value
+		}
+	}
+	return ""
+}
+
+// printSummary prints the summary of the test run, it returns ErrCoverageBelowThreshold when the go coverage
+// of the test run is below test.minCoverage.
+func (r *Replayer) printSummary(ctx context.Context, state *runState, testRunID string, testRunResult bool) error {
+	totalTests, totalTestPassed, totalTestFailed := state.totals()
+	if totalTests > 0 {
+		summary := RunSummary{
+			TestRunID:    testRunID,
+			Passed:       testRunResult,
+			TotalTests:   totalTests,
+			TotalPassed:  totalTestPassed,
+			TotalFailed:  totalTestFailed,
+			FirstFailure: r.getFirstFailure(),
+		}
+		summary.Duration, summary.AvgTestCaseDuration = state.timing()
+		for _, testSuiteName := range state.sortedTestSuiteNames() {
+			row := TestSetRow{TestSetID: testSuiteName, Verdict: state.verdict(testSuiteName)}
+			score, err := r.GetStabilityScore(ctx, testSuiteName, r.config.Report.StabilityRuns)
+			if err != nil {
+				r.logger.Debug("failed to compute the stability score", zap.String("test-set", testSuiteName), zap.Error(err))
 			} else {
-				pp.SetColorScheme(models.FailingColorScheme)
+				row.Stability = &score
 			}
-			if _, err := pp.Printf("\n\t%s\t\t%s\t\t%s\t\t%s", testSuiteName, completeTestReport[testSuiteName].total, completeTestReport[testSuiteName].passed, completeTestReport[testSuiteName].failed); err != nil {
-				utils.LogError(r.logger, err, "failed to print test suite details")
-				return
+			summary.TestSets = append(summary.TestSets, row)
+		}
+		summary.SlowestTestCases = r.slowestTestCases(ctx, testRunID, state.sortedTestSuiteNames())
+		if r.config.Test.BasePath == "" {
+			fidelity, err := r.GetMockFidelityReport(ctx, testRunID)
+			if err != nil {
+				r.logger.Debug("failed to compute the mock fidelity", zap.String("test-run", testRunID), zap.Error(err))
+			} else {
+				summary.MockFidelity = fidelity
 			}
 		}
-		if _, err := pp.Printf("\n<=========================================> \n\n"); err != nil {
-			utils.LogError(r.logger, err, "failed to print separator")
-			return
+		if err := r.summaryWriter.TestRunSummary(summary); err != nil {
+			utils.LogError(r.logger, err, "failed to write the test run summary")
+			return nil
 		}
 		r.logger.Info("test run completed", zap.Bool("passed overall", testRunResult))
 
+		if r.config.Test.HTMLReport {
+			outputDir := r.config.Test.HTMLReportDir
+			if outputDir == "" {
+				outputDir = filepath.Join(config.StoragePath(r.config), "reports", testRunID, "html")
+			}
+			err := r.GenerateHTMLReport(ctx, testRunID, outputDir)
+			if err != nil {
+				utils.LogError(r.logger, err, "failed to generate the html report")
+			}
+		}
+
 		if utils.CmdType(r.config.CommandType) == utils.Native && r.config.Test.GoCoverage {
 			r.logger.Info("there is an opportunity to get the coverage here")
 
@@ -736,6 +1251,7 @@ func (r *Replayer) printSummary(ctx context.Context, testRunResult bool) {
 				utils.LogError(r.logger, err, "failed to get the coverage of the go binary", zap.Any("cmd", coverCmd.String()))
 			}
 			r.logger.Sugar().Infoln("\n", models.HighlightPassingString(string(output)))
+			percentOutput := string(output)
 			generateCovTxtCmd := exec.CommandContext(ctx, "go", "tool", "covdata", "textfmt", "-i="+os.Getenv("GOCOVERDIR"), "-o="+os.Getenv("GOCOVERDIR")+"/total-coverage.txt")
 			output, err = generateCovTxtCmd.Output()
 			if err != nil {
@@ -744,8 +1260,12 @@ func (r *Replayer) printSummary(ctx context.Context, testRunResult bool) {
 			if len(output) > 0 {
 				r.logger.Sugar().Infoln("\n", models.HighlightFailingString(string(output)))
 			}
+			if r.config.Test.MinCoverage > 0 {
+				return r.checkMinCoverage(percentOutput, os.Getenv("GOCOVERDIR")+"/total-coverage.txt")
+			}
 		}
 	}
+	return nil
 }
 
 func (r *Replayer) RunApplication(ctx context.Context, appID uint64, opts models.RunOptions) models.AppError {
@@ -831,18 +1351,29 @@ func (r *Replayer) NormalizeTestCases(ctx context.Context, testRun string, testS
 	}
 
 	testCaseResultMap := make(map[string]models.TestResult)
-	testCases, err := r.testDB.GetTestCases(ctx, testSetID)
-	if err != nil {
-		return fmt.Errorf("failed to get test cases: %w", err)
-	}
 	selectedTestCases := make([]*models.TestCase, 0, len(selectedTestCaseIDs))
 
-	if len(selectedTestCaseIDs) == 0 {
-		selectedTestCases = testCases
+	// a single test case is read from its own file instead of reading the whole test set
+	if len(selectedTestCaseIDs) == 1 {
+		testCase, err := r.testDB.GetTestCase(ctx, testSetID, selectedTestCaseIDs[0])
+		if err != nil && !errors.Is(err, models.ErrTestCaseNotFound) {
+			return fmt.Errorf("failed to get test case: %w", err)
+		}
+		if testCase != nil {
+			selectedTestCases = append(selectedTestCases, testCase)
+		}
 	} else {
-		for _, testCase := range testCases {
-			if _, ok := ArrayToMap(selectedTestCaseIDs)[testCase.Name]; ok {
-				selectedTestCases = append(selectedTestCases, testCase)
+		testCases, err := r.testDB.GetTestCases(ctx, testSetID)
+		if err != nil {
+			return fmt.Errorf("failed to get test cases: %w", err)
+		}
+		if len(selectedTestCaseIDs) == 0 {
+			selectedTestCases = testCases
+		} else {
+			for _, testCase := range testCases {
+				if _, ok := ArrayToMap(selectedTestCaseIDs)[testCase.Name]; ok {
+					selectedTestCases = append(selectedTestCases, testCase)
+				}
 			}
 		}
 	}
@@ -859,8 +1390,12 @@ func (r *Replayer) NormalizeTestCases(ctx context.Context, testRun string, testS
 		if testCaseResultMap[testCase.Name].Status == models.TestStatusPassed {
 			continue
 		}
-		testCase.HTTPResp = testCaseResultMap[testCase.Name].Res
-		err = r.testDB.UpdateTestCase(ctx, testCase, testSetID)
+		if models.NormalizeStrategy(r.config.Normalize.Strategy) == models.NormalizePartial {
+			mergeResponse(&testCase.HTTPResp, testCaseResultMap[testCase.Name].Res, testCaseResultMap[testCase.Name].Result)
+		} else {
+			testCase.HTTPResp = testCaseResultMap[testCase.Name].Res
+		}
+		err := r.testDB.UpdateTestCase(ctx, testCase, testSetID)
 		if err != nil {
 			return fmt.Errorf("failed to update test case: %w", err)
 		}
@@ -868,12 +1403,61 @@ func (r *Replayer) NormalizeTestCases(ctx context.Context, testRun string, testS
 	return nil
 }
 
-func (r *Replayer) executeScript(ctx context.Context, script string) error {
+// acceptFailures normalizes the failing test cases of the test set, i.e. updates their expected response
+// with the actual one, and reports the accepted test cases.
+func (r *Replayer) acceptFailures(ctx context.Context, testRunID, testSetID string) {
+	testReport, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to get the test report to accept the failures", zap.String("test-set", testSetID))
+		return
+	}
+	var failed []string
+	for _, result := range testReport.Tests {
+		if result.Status == models.TestStatusFailed {
+			failed = append(failed, result.TestCaseID)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+	err = r.NormalizeTestCases(ctx, testRunID, testSetID, failed, testReport.Tests)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to accept the failing test cases", zap.String("test-set", testSetID))
+		return
+	}
+	r.logger.Info(fmt.Sprintf("Accepted the actual responses of %d failing test cases as expected", len(failed)), zap.String("test-set", testSetID), zap.Strings("test cases", failed))
+}
+
+// scriptContext is the data the pre and post scripts are rendered with, e.g. pg_dump {{.TestSetID}}_db > /tmp/dump.sql
+type scriptContext struct {
+	TestSetID string
+	TestRunID string
+	Env       map[string]string
+}
+
+func (r *Replayer) executeScript(ctx context.Context, script, testSetID, testRunID string) error {
 
 	if script == "" {
 		return nil
 	}
 
+	tmpl, err := template.New("script").Option("missingkey=zero").Parse(script)
+	if err != nil {
+		return fmt.Errorf("failed to parse the script template: %w", err)
+	}
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	var rendered strings.Builder
+	err = tmpl.Execute(&rendered, scriptContext{TestSetID: testSetID, TestRunID: testRunID, Env: env})
+	if err != nil {
+		return fmt.Errorf("failed to render the script template: %w", err)
+	}
+	script = rendered.String()
+
 	// Define the function to cancel the command
 	cmdCancel := func(cmd *exec.Cmd) func() error {
 		return func() error {
@@ -881,7 +1465,7 @@ func (r *Replayer) executeScript(ctx context.Context, script string) error {
 		}
 	}
 
-	cmdErr := utils.ExecuteCommand(ctx, r.logger, script, cmdCancel, 25*time.Second)
+	cmdErr := utils.ExecuteCommand(ctx, r.logger, script, cmdCancel, 25*time.Second, nil, nil, nil)
 	if cmdErr.Err != nil {
 		return fmt.Errorf("failed to execute script: %w", cmdErr.Err)
 	}
@@ -895,3 +1479,48 @@ func (r *Replayer) DeleteTestSet(ctx context.Context, testSetID string) error {
 func (r *Replayer) DeleteTests(ctx context.Context, testSetID string, testCaseIDs []string) error {
 	return r.testDB.DeleteTests(ctx, testSetID, testCaseIDs)
 }
+
+// CreateTestCase validates the test case and inserts it in the test set, for the test cases generated outside
+// of the record flow. The name of the test case must not be taken in the test set.
+func (r *Replayer) CreateTestCase(ctx context.Context, testSetID string, tc *models.TestCase) error {
+	if tc == nil {
+		return errors.New("test case is nil")
+	}
+	if tc.Name == "" {
+		return errors.New("test case name is empty")
+	}
+	if tc.Kind == "" {
+		tc.Kind = models.HTTP
+	}
+	if tc.Kind == models.HTTP {
+		u, err := url.ParseRequestURI(tc.HTTPReq.URL)
+		if err != nil {
+			return fmt.Errorf("invalid url %q of the test case %s: %w", tc.HTTPReq.URL, tc.Name, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("url %q of the test case %s should be absolute", tc.HTTPReq.URL, tc.Name)
+		}
+		if tc.HTTPReq.Timestamp.IsZero() || tc.HTTPResp.Timestamp.IsZero() {
+			return fmt.Errorf("request and response timestamps of the test case %s should be set", tc.Name)
+		}
+	}
+	for _, assertion := range tc.DBAssertions {
+		if err := assertion.Validate(); err != nil {
+			return fmt.Errorf("invalid database assertion of the test case %s: %w", tc.Name, err)
+		}
+	}
+
+	testCases, err := r.testDB.GetTestCases(ctx, testSetID)
+	if err != nil {
+		return fmt.Errorf("failed to get the test cases of the test set: %w", err)
+	}
+	for _, testCase := range testCases {
+		if testCase.Name == tc.Name {
+			return fmt.Errorf("test case %s already exists in the test set %s", tc.Name, testSetID)
+		}
+	}
+	if err := r.testDB.InsertTestCase(ctx, tc, testSetID); err != nil {
+		return fmt.Errorf("failed to insert the test case: %w", err)
+	}
+	return nil
+}
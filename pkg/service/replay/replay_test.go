@@ -0,0 +1,60 @@
+//go:build linux
+
+package replay
+
+import (
+	"testing"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// multipartResp returns a multipart/form-data response with the files, the boundary is random.
+func multipartResp(t *testing.T, files ...models.FormFile) models.HTTPResp {
+	t.Helper()
+	body, contentType, err := pkg.MultipartBody(models.HTTPReq{FormFiles: files})
+	if err != nil {
+		t.Fatalf("failed to build the multipart body: %v", err)
+	}
+	return models.HTTPResp{
+		StatusCode: 200,
+		Header:     map[string]string{"Content-Type": contentType},
+		Body:       body.String(),
+	}
+}
+
+func TestCompareRespComparesTheFilesOfTheMultipartResponses(t *testing.T) {
+	report := models.FormFile{Name: "report", Filename: "report.csv", ContentType: "text/csv", Data: []byte("id,name\n1,keploy\n")}
+	changed := models.FormFile{Name: "report", Filename: "report.csv", ContentType: "text/csv", Data: []byte("id,name\n2,keploy\n")}
+	retyped := models.FormFile{Name: "report", Filename: "report.csv", ContentType: "text/plain", Data: report.Data}
+
+	tests := []struct {
+		name   string
+		actual []models.FormFile
+		noise  map[string][]string
+		want   bool
+	}{
+		{name: "same files with another boundary", actual: []models.FormFile{report}, want: true},
+		{name: "changed data", actual: []models.FormFile{changed}, want: false},
+		{name: "changed content type", actual: []models.FormFile{retyped}, want: false},
+		{name: "missing file", actual: nil, want: false},
+		{name: "noisy field name", actual: []models.FormFile{changed}, noise: map[string][]string{"form.report": {}}, want: true},
+	}
+
+	r := &Replayer{logger: zap.NewNop(), config: &config.Config{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &models.TestCase{Name: "test-1", HTTPResp: multipartResp(t, report), Noise: tt.noise}
+			actual := multipartResp(t, tt.actual...)
+			pass, res := r.compareResp(tc, &actual, "test-set-0")
+			if pass != tt.want {
+				t.Fatalf("compareResp() = %v, want %v, form files result: %+v", pass, tt.want, res.FormFilesResult)
+			}
+			if len(res.FormFilesResult) != 1 || res.FormFilesResult[0].Name != "report" {
+				t.Fatalf("compareResp() form files result = %+v, want the result of the report file", res.FormFilesResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package replay
+
+import (
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// SetResultSink registers the channel each test case result is sent on, right after it is inserted in the report.
+// The results are sent without blocking, they are dropped when the channel is full, so the channel should be
+// buffered for a slow consumer. Passing nil unregisters the sink; once it returns, the channel is not sent on
+// anymore and can be closed by its owner. The replayer never closes the channel.
+func (r *Replayer) SetResultSink(sink chan<- models.TestResult) {
+	r.sinkMu.Lock()
+	defer r.sinkMu.Unlock()
+	r.resultSink = sink
+}
+
+// sendResult sends the test case result on the registered sink, if any.
+func (r *Replayer) sendResult(result *models.TestResult) {
+	r.sinkMu.Lock()
+	defer r.sinkMu.Unlock()
+	if r.resultSink == nil {
+		return
+	}
+	select {
+	case r.resultSink <- *result:
+	default:
+		r.logger.Warn("dropped the test case result as the result sink is full", zap.String("testcase", result.TestCaseID), zap.String("test-set", result.Name))
+	}
+}
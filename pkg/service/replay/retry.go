@@ -0,0 +1,78 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// isRetryable checks whether the failed test case should be re-run. Any failure is retried unless transient status
+// codes are configured, the test case is only retried when its actual status code is one of them then and differs
+// from the expected one, so that the deterministic failures fail fast.
+func (r *Replayer) isRetryable(tc *models.TestCase, resp *models.HTTPResp) bool {
+	if len(r.config.Test.RetryOnStatus) == 0 {
+		return true
+	}
+	if resp == nil || resp.StatusCode == tc.HTTPResp.StatusCode {
+		return false
+	}
+	for _, status := range r.config.Test.RetryOnStatus {
+		if status == resp.StatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// retryFailedTestCase re-simulates the failed test case up to Test.MaxRetries times, sleeping Test.RetryDelayMs
+// between the attempts, until it passes or its failure is not retryable. The mocks of the test case window are set up again before every
+// attempt when resetMocks is set, i.e. when the test cases are not run in parallel.
+// It returns the response and the result of the last completed attempt along with the number of attempts made.
+func (r *Replayer) retryFailedTestCase(ctx context.Context, appID uint64, testCase *models.TestCase, testSetID string, resp *models.HTTPResp, testResult *models.Result, resetMocks bool) (*models.HTTPResp, bool, *models.Result, int) {
+	testPass := false
+	attempts := 1
+	for attempt := 1; attempt <= r.config.Test.MaxRetries && r.isRetryable(testCase, resp); attempt++ {
+		diff := ""
+		if testResult != nil {
+			diff = resultDiff(*testResult)
+		}
+		r.logger.Debug("retrying the failed test case", zap.String("testcase", testCase.Name), zap.Int("attempt", attempt), zap.String("previous diff", diff))
+
+		select {
+		case <-ctx.Done():
+			return resp, testPass, testResult, attempts
+		case <-time.After(time.Duration(r.config.Test.RetryDelayMs) * time.Millisecond):
+		}
+
+		if resetMocks {
+			err := r.SetupOrUpdateMocks(ctx, appID, testSetID, testCase.HTTPReq.Timestamp, testCase.HTTPResp.Timestamp, Update)
+			if err != nil {
+				utils.LogError(r.logger, err, "failed to update mocks for the retry", zap.String("testcase", testCase.Name), zap.Int("attempt", attempt))
+				return resp, testPass, testResult, attempts
+			}
+		}
+
+		retryResp, err := r.emulatorFor(testCase).SimulateRequest(ctx, appID, testCase, testSetID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to simulate request for the retry", zap.String("testcase", testCase.Name), zap.Int("attempt", attempt))
+			return resp, testPass, testResult, attempts
+		}
+		retryPass, retryResult, err := r.compareTestCase(ctx, appID, testCase, retryResp, testSetID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to compare the response of the retry", zap.String("testcase", testCase.Name), zap.Int("attempt", attempt))
+			return resp, testPass, testResult, attempts
+		}
+		attempts++
+		resp, testPass, testResult = retryResp, retryPass, retryResult
+		if testPass {
+			r.logger.Warn("test case passed on retry, it is flaky", zap.String("testcase", testCase.Name), zap.String("test-set", testSetID), zap.Int("attempts", attempts))
+			break
+		}
+	}
+	return resp, testPass, testResult, attempts
+}
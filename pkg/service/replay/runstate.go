@@ -0,0 +1,92 @@
+//go:build linux
+
+package replay
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runState collects the verdicts of the test sets of a single test run, it is allocated by every call of Start
+// so that the test runs in the same process don't share their counts.
+type runState struct {
+	// mu guards the fields below, as the test sets may run in parallel
+	mu                 sync.Mutex
+	completeTestReport map[string]TestSetVerdict
+	totalTests         int
+	totalTestPassed    int
+	totalTestFailed    int
+	// started is when the test run started, for its wall-clock duration
+	started time.Time
+	// junit collects the test suites of the junit report, nil unless the report is written
+	junit *junitReporter
+}
+
+func newRunState() *runState {
+	return &runState{completeTestReport: make(map[string]TestSetVerdict), started: time.Now()}
+}
+
+// addTestSet adds the verdict of the completed test set to the test run.
+func (s *runState) addTestSet(testSetID string, verdict TestSetVerdict) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completeTestReport[testSetID] = verdict
+	s.totalTests += verdict.Total
+	s.totalTestPassed += verdict.Passed
+	s.totalTestFailed += verdict.Failed
+	if s.junit != nil {
+		s.junit.addTestSet(testSetID, verdict.results)
+	}
+}
+
+// totals returns the number of the tests, the passed and the failed tests of the test run.
+func (s *runState) totals() (total, passed, failed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.totalTests, s.totalTestPassed, s.totalTestFailed
+}
+
+// timing returns the wall-clock duration of the test run so far and the average duration of its test cases.
+func (s *runState) timing() (elapsed, perTestCase time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elapsed = time.Since(s.started)
+	if s.totalTests > 0 {
+		perTestCase = elapsed / time.Duration(s.totalTests)
+	}
+	return elapsed, perTestCase
+}
+
+// verdict returns the verdict of the completed test set.
+func (s *runState) verdict(testSetID string) TestSetVerdict {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.completeTestReport[testSetID]
+}
+
+// sortedTestSuiteNames returns the names of the completed test sets ordered by their index.
+func (s *runState) sortedTestSuiteNames() []string {
+	s.mu.Lock()
+	testSuiteNames := make([]string, 0, len(s.completeTestReport))
+	for testSuiteName := range s.completeTestReport {
+		testSuiteNames = append(testSuiteNames, testSuiteName)
+	}
+	s.mu.Unlock()
+	sort.SliceStable(testSuiteNames, func(i, j int) bool {
+		testSuitePartsI := strings.Split(testSuiteNames[i], "-")
+		testSuitePartsJ := strings.Split(testSuiteNames[j], "-")
+		if len(testSuitePartsI) < 3 || len(testSuitePartsJ) < 3 {
+			return testSuiteNames[i] < testSuiteNames[j]
+		}
+		testSuiteIDNumberI, err1 := strconv.Atoi(testSuitePartsI[2])
+		testSuiteIDNumberJ, err2 := strconv.Atoi(testSuitePartsJ[2])
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		return testSuiteIDNumberI < testSuiteIDNumberJ
+	})
+	return testSuiteNames
+}
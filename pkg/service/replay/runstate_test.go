@@ -0,0 +1,212 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/pkg/platform/yaml/configdb/testset"
+	"go.keploy.io/server/v2/pkg/platform/yaml/mockdb"
+	"go.keploy.io/server/v2/pkg/platform/yaml/reportdb"
+	"go.keploy.io/server/v2/pkg/platform/yaml/testdb"
+	"go.uber.org/zap"
+)
+
+func TestRunStateKeepsTheCountsOfEachRun(t *testing.T) {
+	runs := []struct {
+		name     string
+		verdicts map[string]TestSetVerdict
+		want     testRunSummary
+	}{
+		{
+			name: "first replayer",
+			verdicts: map[string]TestSetVerdict{
+				"test-set-0": {Total: 3, Passed: 2, Failed: 1},
+				"test-set-1": {Total: 2, Passed: 2, Status: true},
+			},
+			want: testRunSummary{TotalTests: 5, TotalPassed: 4, TotalFailed: 1},
+		},
+		{
+			name: "second replayer",
+			verdicts: map[string]TestSetVerdict{
+				"test-set-0": {Total: 1, Passed: 1, Status: true},
+			},
+			want: testRunSummary{TotalTests: 1, TotalPassed: 1},
+		},
+	}
+
+	dir := t.TempDir()
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			// every replayer allocates its own state per run, as Start does
+			r := &Replayer{logger: zap.NewNop()}
+			state := newRunState()
+			for testSetID, verdict := range run.verdicts {
+				state.addTestSet(testSetID, verdict)
+			}
+
+			path := filepath.Join(dir, run.name+".json")
+			if err := writeJSONSummary(path, state, "test-run-0", true, false, r.getFirstFailure()); err != nil {
+				t.Fatalf("failed to write the summary: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read the summary: %v", err)
+			}
+			var got testRunSummary
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("failed to unmarshal the summary: %v", err)
+			}
+			if got.TotalTests != run.want.TotalTests || got.TotalPassed != run.want.TotalPassed || got.TotalFailed != run.want.TotalFailed {
+				t.Errorf("got %d tests, %d passed and %d failed, want %d, %d and %d", got.TotalTests, got.TotalPassed, got.TotalFailed, run.want.TotalTests, run.want.TotalPassed, run.want.TotalFailed)
+			}
+			if len(got.TestSets) != len(run.verdicts) {
+				t.Errorf("got %d test sets, want %d", len(got.TestSets), len(run.verdicts))
+			}
+		})
+	}
+}
+
+// nopTelemetry drops the telemetry events of the test runs.
+type nopTelemetry struct{}
+
+func (nopTelemetry) TestSetRun(int, int, string, string) {}
+func (nopTelemetry) TestRun(int, int, int, string)       {}
+func (nopTelemetry) MockTestRun(int)                     {}
+func (nopTelemetry) Flush()                              {}
+
+func TestStartKeepsTheStateOfEachRun(t *testing.T) {
+	// the app returns a changed user 2 while broken, failing its test case
+	var broken atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `{"id":1}`
+		if r.URL.Path == "/users/2" {
+			body = `{"id":2}`
+			if broken.Load() {
+				body = `{"id":0}`
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	defer func(emulator RequestMockHandler) { requestMockemulator = emulator }(requestMockemulator)
+	requestMockemulator = NewRequestMockUtil(zap.NewNop(), t.TempDir(), "mocks", 5, server.URL)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keploy")
+	logger := zap.NewNop()
+	testDB := testdb.New(logger, path)
+	recorded := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, tc := range []struct{ testSetID, path, body string }{
+		{"test-set-0", "/users/1", `{"id":1}`},
+		{"test-set-0", "/users/2", `{"id":2}`},
+		{"test-set-1", "/users/1", `{"id":1}`},
+	} {
+		testCase := &models.TestCase{
+			Version: models.GetVersion(),
+			Kind:    models.HTTP,
+			HTTPReq: models.HTTPReq{Method: "GET", URL: "http://localhost:8080" + tc.path, ProtoMajor: 1, ProtoMinor: 1, Timestamp: recorded.Add(time.Duration(i) * time.Second)},
+			HTTPResp: models.HTTPResp{
+				StatusCode: 200,
+				Header:     map[string]string{"Content-Type": "application/json", "Content-Length": "8", "Date": ""},
+				Body:       tc.body,
+				Timestamp:  recorded.Add(time.Duration(i)*time.Second + time.Millisecond),
+			},
+			Noise: map[string][]string{"header.date": {}},
+		}
+		if err := testDB.InsertTestCase(context.Background(), testCase, tc.testSetID); err != nil {
+			t.Fatalf("failed to insert the test case: %v", err)
+		}
+	}
+
+	// the test sets run against the base path need their config
+	testSetConf := testset.New[*models.TestSet](logger, path)
+	for _, testSetID := range []string{"test-set-0", "test-set-1"} {
+		if err := testSetConf.Write(context.Background(), testSetID, &models.TestSet{}); err != nil {
+			t.Fatalf("failed to write the test set config: %v", err)
+		}
+	}
+
+	cfg := &config.Config{Path: path}
+	cfg.Test.BasePath = server.URL
+	cfg.Test.APITimeout = 5
+	r := NewReplayer(logger, testDB, mockdb.New(logger, path, ""), reportdb.New(logger, filepath.Join(path, "reports"), ""), testSetConf, nopTelemetry{}, nil, cfg)
+
+	runs := []struct {
+		name          string
+		broken        bool
+		selectedTests map[string][]string
+		want          testRunSummary
+		wantSuites    []junitTestSuite
+	}{
+		{
+			name:       "first run with a failure",
+			broken:     true,
+			want:       testRunSummary{TotalTests: 3, TotalPassed: 2, TotalFailed: 1},
+			wantSuites: []junitTestSuite{{Name: "test-set-0", Tests: 2, Failures: 1}, {Name: "test-set-1", Tests: 1}},
+		},
+		{
+			name:          "second run of a single test set",
+			selectedTests: map[string][]string{"test-set-1": {}},
+			want:          testRunSummary{TotalTests: 1, TotalPassed: 1, Passed: true},
+			wantSuites:    []junitTestSuite{{Name: "test-set-1", Tests: 1}},
+		},
+	}
+	for i, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			broken.Store(run.broken)
+			cfg.Test.SelectedTests = run.selectedTests
+			cfg.Test.SummaryJSONPath = filepath.Join(dir, fmt.Sprintf("summary-%d.json", i))
+			cfg.Test.JUnitReportPath = filepath.Join(dir, fmt.Sprintf("junit-%d.xml", i))
+			if err := r.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			data, err := os.ReadFile(cfg.Test.SummaryJSONPath)
+			if err != nil {
+				t.Fatalf("failed to read the summary: %v", err)
+			}
+			var summary testRunSummary
+			if err := json.Unmarshal(data, &summary); err != nil {
+				t.Fatalf("failed to unmarshal the summary: %v", err)
+			}
+			if summary.TotalTests != run.want.TotalTests || summary.TotalPassed != run.want.TotalPassed || summary.TotalFailed != run.want.TotalFailed || summary.Passed != run.want.Passed {
+				t.Errorf("the summary has %d tests, %d passed and %d failed, passed %v, want %d, %d, %d and %v", summary.TotalTests, summary.TotalPassed, summary.TotalFailed, summary.Passed, run.want.TotalTests, run.want.TotalPassed, run.want.TotalFailed, run.want.Passed)
+			}
+
+			data, err = os.ReadFile(cfg.Test.JUnitReportPath)
+			if err != nil {
+				t.Fatalf("failed to read the junit report: %v", err)
+			}
+			var report junitTestSuites
+			if err := xml.Unmarshal(data, &report); err != nil {
+				t.Fatalf("failed to unmarshal the junit report: %v", err)
+			}
+			var suites []junitTestSuite
+			for _, suite := range report.Suites {
+				suites = append(suites, junitTestSuite{Name: suite.Name, Tests: suite.Tests, Failures: suite.Failures})
+			}
+			if !reflect.DeepEqual(suites, run.wantSuites) {
+				t.Errorf("the junit report has the suites %+v, want %+v", suites, run.wantSuites)
+			}
+			if report.Tests != run.want.TotalTests || report.Failures != run.want.TotalFailed {
+				t.Errorf("the junit report has %d tests and %d failures, want %d and %d", report.Tests, report.Failures, run.want.TotalTests, run.want.TotalFailed)
+			}
+		})
+	}
+}
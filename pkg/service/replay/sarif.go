@@ -0,0 +1,141 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLog is the root of a SARIF 2.1.0 report (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html),
+// only the properties needed to point the editors to the failing test case files are filled.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+const sarifRuleID = "keploy/testcase-failed"
+
+// writeSARIFReport writes the failed test cases of the test sets run so far as a SARIF report to the file at the path,
+// each result points to the yaml file of the failed test case and carries the diff of its response.
+func (r *Replayer) writeSARIFReport(ctx context.Context, path string, state *runState, testRunID string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "keploy",
+			Version:        string(models.GetVersion()),
+			InformationURI: "https://keploy.io",
+			Rules: []sarifRule{{
+				ID:               sarifRuleID,
+				ShortDescription: sarifMessage{Text: "The response of the test case did not match the recorded response"},
+			}},
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, testSetID := range state.sortedTestSuiteNames() {
+		report, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+		if err != nil {
+			r.logger.Debug("no report found for the test set in the test run", zap.String("test-set", testSetID), zap.String("test-run", testRunID), zap.Error(err))
+			continue
+		}
+		for _, result := range report.Tests {
+			if result.Status != models.TestStatusFailed {
+				continue
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  sarifRuleID,
+				Level:   "error",
+				Message: sarifMessage{Text: fmt.Sprintf("test case %s of %s failed in the test run %s", result.TestCaseID, testSetID, testRunID)},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: r.testCaseURI(testSetID, result.TestCaseID)},
+					},
+				}},
+				Properties: map[string]string{
+					"testSet":  testSetID,
+					"testCase": result.TestCaseID,
+					"diff":     resultDiff(result.Result),
+				},
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the sarif report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return fmt.Errorf("failed to create the sarif report directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o777); err != nil {
+		return fmt.Errorf("failed to write the sarif report: %w", err)
+	}
+	return nil
+}
+
+// testCaseURI returns the path of the yaml file of the test case, relative to the working directory when possible
+// as the editors resolve the relative uris against the workspace root.
+func (r *Replayer) testCaseURI(testSetID, testCaseID string) string {
+	path := filepath.Join(config.StoragePath(r.config), testSetID, "tests", testCaseID+".yaml")
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
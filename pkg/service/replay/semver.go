@@ -0,0 +1,116 @@
+//go:build linux
+
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// ValueTolerance relaxes the comparison of the json values which are expected to drift between the record and the replay.
+type ValueTolerance struct {
+	// FloatAbs is the absolute tolerance of the non-integral numbers, 0 compares them exactly.
+	FloatAbs float64
+	// FloatRel is the tolerance of the non-integral numbers relative to the larger of them, 0 compares them exactly.
+	FloatRel float64
+	// SemverFields are the dot separated paths of the fields compared as semantic versions.
+	SemverFields []string
+	// Semver is the part of the version whose difference is tolerated: patch, minor or major.
+	// The versions are compared exactly when it is empty.
+	Semver string
+	// TypeCoerceFields are the dot separated paths of the fields whose values are compared as strings.
+	TypeCoerceFields []string
+	// IgnoreExtraFields ignores the keys of the actual objects which are not in the expected ones.
+	IgnoreExtraFields bool
+}
+
+func (r *Replayer) valueTolerance() ValueTolerance {
+	return ValueTolerance{
+		FloatAbs:          r.config.Test.FloatAbsTolerance,
+		FloatRel:          r.config.Test.FloatRelTolerance,
+		SemverFields:      r.config.Test.GlobalNoise.SemverFields,
+		Semver:            r.config.Test.SemverTolerance,
+		TypeCoerceFields:  r.config.Test.GlobalNoise.TypeCoerce,
+		IgnoreExtraFields: r.config.Test.IgnoreExtraFields,
+	}
+}
+
+// isSemverField reports whether the json path is one of the semver fields, the path is matched case-insensitively.
+func (t ValueTolerance) isSemverField(key string) bool {
+	for _, field := range t.SemverFields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTypeCoerceField reports whether the json path is one of the type coerced fields, the path is matched case-insensitively.
+func (t ValueTolerance) isTypeCoerceField(key string) bool {
+	for _, field := range t.TypeCoerceFields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeCoercedEqual reports whether the scalar values of a type coerced field are equal once formatted as strings,
+// so that the ids serialized as a number by a json library and as a string by another one match.
+func typeCoercedEqual(key string, expected, actual interface{}, tolerance ValueTolerance) bool {
+	if !tolerance.isTypeCoerceField(key) || !isJSONScalar(expected) || !isJSONScalar(actual) {
+		return false
+	}
+	return fmt.Sprintf("%v", expected) == fmt.Sprintf("%v", actual)
+}
+
+func isJSONScalar(v interface{}) bool {
+	switch v.(type) {
+	case string, float64, bool:
+		return true
+	}
+	return false
+}
+
+// semverCompatible reports whether the expected and the actual values of a semver field differ only in
+// the tolerated part of the version. The values which are not valid semantic versions never match here.
+func semverCompatible(key string, expected, actual interface{}, tolerance ValueTolerance) bool {
+	if !tolerance.isSemverField(key) {
+		return false
+	}
+	exp, ok := canonicalSemver(expected)
+	if !ok {
+		return false
+	}
+	act, ok := canonicalSemver(actual)
+	if !ok {
+		return false
+	}
+	switch tolerance.Semver {
+	case "major":
+		return true
+	case "minor":
+		return semver.Major(exp) == semver.Major(act)
+	case "patch":
+		return semver.MajorMinor(exp) == semver.MajorMinor(act)
+	default:
+		return semver.Compare(exp, act) == 0
+	}
+}
+
+// canonicalSemver returns the version with the "v" prefix expected by the semver package, e.g. 1.2.3 -> v1.2.3.
+func canonicalSemver(val interface{}) (string, bool) {
+	s, ok := val.(string)
+	if !ok {
+		return "", false
+	}
+	if !strings.HasPrefix(s, "v") {
+		s = "v" + s
+	}
+	if !semver.IsValid(s) {
+		return "", false
+	}
+	return s, true
+}
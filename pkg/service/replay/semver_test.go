@@ -0,0 +1,79 @@
+//go:build linux
+
+package replay
+
+import (
+	"testing"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestMatchComparesTheSemverFieldsOfTheNoiseConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		semverFields []string
+		tolerance    string
+		typeCoerce   []string
+		expected     string
+		actual       string
+		want         bool
+	}{
+		{
+			name:     "version not in the semver fields",
+			expected: `{"version":"1.2.3"}`,
+			actual:   `{"version":"1.2.4"}`,
+			want:     false,
+		},
+		{
+			name:         "patch difference within the patch tolerance",
+			semverFields: []string{"version"},
+			tolerance:    "patch",
+			expected:     `{"version":"1.2.3"}`,
+			actual:       `{"version":"1.2.4"}`,
+			want:         true,
+		},
+		{
+			name:         "minor difference out of the patch tolerance",
+			semverFields: []string{"version"},
+			tolerance:    "patch",
+			expected:     `{"version":"1.2.3"}`,
+			actual:       `{"version":"1.3.0"}`,
+			want:         false,
+		},
+		{
+			name:         "nested semver field matched case-insensitively",
+			semverFields: []string{"Server.Version"},
+			tolerance:    "minor",
+			expected:     `{"server":{"version":"v2.1.0"}}`,
+			actual:       `{"server":{"version":"v2.4.1"}}`,
+			want:         true,
+		},
+		{
+			name:         "semver and type coerced fields of the noise config",
+			semverFields: []string{"version"},
+			tolerance:    "major",
+			typeCoerce:   []string{"id"},
+			expected:     `{"id":42,"version":"1.0.0"}`,
+			actual:       `{"id":"42","version":"3.0.0"}`,
+			want:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Test.GlobalNoise.SemverFields = tt.semverFields
+			cfg.Test.GlobalNoise.TypeCoerce = tt.typeCoerce
+			cfg.Test.SemverTolerance = tt.tolerance
+			r := &Replayer{logger: zap.NewNop(), config: cfg}
+
+			tc := &models.TestCase{Name: "test-1", HTTPResp: jsonResp(tt.expected)}
+			actual := jsonResp(tt.actual)
+			pass, res := match(tc, &actual, map[string]map[string][]string{}, false, r.valueTolerance(), nil, zap.NewNop())
+			if pass != tt.want {
+				t.Fatalf("match() = %v, want %v, body result: %+v", pass, tt.want, res.BodyResult)
+			}
+		})
+	}
+}
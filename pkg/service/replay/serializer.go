@@ -0,0 +1,74 @@
+//go:build linux
+
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// BodySerializer converts the request and response bodies between the wire format of the application
+// and the json format they are stored and compared in.
+type BodySerializer interface {
+	Serialize(v interface{}) ([]byte, error)
+	Deserialize(data []byte, v interface{}) error
+	ContentType() string
+}
+
+// RequestMockUtilOption configures the default request mock handler.
+type RequestMockUtilOption func(*requestMockUtil)
+
+// WithBodySerializer sets the serializer used to encode the request bodies sent to the application
+// and to decode its response bodies back to json before the comparison.
+func WithBodySerializer(serializer BodySerializer) RequestMockUtilOption {
+	return func(t *requestMockUtil) {
+		t.serializer = serializer
+	}
+}
+
+// WithMaxResponseBodyKB truncates the response bodies read from the application to the size in kilobytes.
+func WithMaxResponseBodyKB(maxBodyKB int) RequestMockUtilOption {
+	return func(t *requestMockUtil) {
+		t.maxBodyKB = maxBodyKB
+	}
+}
+
+// JSONBodySerializer is the default serializer, the bodies are sent as they are recorded.
+type JSONBodySerializer struct{}
+
+func (JSONBodySerializer) Serialize(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONBodySerializer) Deserialize(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (JSONBodySerializer) ContentType() string {
+	return "application/json"
+}
+
+// MessagePackBodySerializer sends the bodies as MessagePack. The json numbers holding integers are encoded as
+// MessagePack integers, so that the applications decoding them into integer fields accept them.
+type MessagePackBodySerializer struct{}
+
+func (MessagePackBodySerializer) Serialize(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.UseCompactInts(true)
+	enc.UseCompactFloats(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MessagePackBodySerializer) Deserialize(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+func (MessagePackBodySerializer) ContentType() string {
+	return "application/msgpack"
+}
@@ -0,0 +1,164 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// base64BodySerializer sends the json bodies base64 encoded, standing in for a binary format of the application.
+type base64BodySerializer struct{}
+
+func (base64BodySerializer) Serialize(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(data)), nil
+}
+
+func (base64BodySerializer) Deserialize(data []byte, v interface{}) error {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decoded, v)
+}
+
+func (base64BodySerializer) ContentType() string {
+	return "application/x-base64-json"
+}
+
+func TestSimulateRequestWithBodySerializer(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []RequestMockUtilOption
+		serializer BodySerializer
+	}{
+		{name: "no serializer", serializer: JSONBodySerializer{}},
+		{name: "json serializer", opts: []RequestMockUtilOption{WithBodySerializer(JSONBodySerializer{})}, serializer: JSONBodySerializer{}},
+		{name: "pluggable serializer", opts: []RequestMockUtilOption{WithBodySerializer(base64BodySerializer{})}, serializer: base64BodySerializer{}},
+		{name: "message pack serializer", opts: []RequestMockUtilOption{WithBodySerializer(MessagePackBodySerializer{})}, serializer: MessagePackBodySerializer{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the app echoes the name of the user it receives in the format of the serializer
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Content-Type"); got != tt.serializer.ContentType() {
+					t.Errorf("the request content type = %q, want %q", got, tt.serializer.ContentType())
+				}
+				data, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read the request body: %v", err)
+				}
+				var user map[string]interface{}
+				if err := tt.serializer.Deserialize(data, &user); err != nil {
+					t.Errorf("failed to deserialize the request body %q: %v", data, err)
+				}
+				body, err := tt.serializer.Serialize(map[string]interface{}{"greeting": "hello " + user["name"].(string)})
+				if err != nil {
+					t.Errorf("failed to serialize the response body: %v", err)
+				}
+				w.Header().Set("Content-Type", tt.serializer.ContentType())
+				_, _ = w.Write(body)
+			}))
+			defer server.Close()
+
+			handler := NewRequestMockUtil(zap.NewNop(), t.TempDir(), "mocks", 5, "", tt.opts...)
+			tc := &models.TestCase{
+				Name: "test-1",
+				Kind: models.HTTP,
+				HTTPReq: models.HTTPReq{
+					Method: models.Method(http.MethodPost),
+					URL:    server.URL + "/greet",
+					Header: map[string]string{"Content-Type": "application/json"},
+					Body:   `{"name":"keploy"}`,
+				},
+			}
+			resp, err := handler.SimulateRequest(context.Background(), 0, tc, "test-set-0")
+			if err != nil {
+				t.Fatalf("SimulateRequest() error = %v", err)
+			}
+			if resp.Body != `{"greeting":"hello keploy"}` {
+				t.Fatalf("SimulateRequest() body = %q, want the json body", resp.Body)
+			}
+			if tc.HTTPReq.Body != `{"name":"keploy"}` {
+				t.Fatalf("SimulateRequest() changed the recorded request body to %q", tc.HTTPReq.Body)
+			}
+		})
+	}
+}
+
+func TestMessagePackBodySerializerRoundTrip(t *testing.T) {
+	// the keys are sorted as the json encoding of the decoded maps sorts them
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "object", body: `{"active":true,"id":1,"manager":null,"name":"keploy"}`},
+		{name: "floats and negative integers", body: `{"balance":-42,"price":19.99,"ratio":0.5}`},
+		{name: "large integer", body: `{"id":9007199254740991}`},
+		{name: "nested arrays of objects", body: `{"orders":[{"id":1,"items":[{"qty":2,"sku":"a"}]},{"id":2,"items":[]}]}`},
+		{name: "top-level array", body: `[1,"two",[3.5],{"four":4}]`},
+		{name: "unicode string", body: `{"greeting":"héllo wörld ✓"}`},
+	}
+	serializer := MessagePackBodySerializer{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body interface{}
+			if err := json.Unmarshal([]byte(tt.body), &body); err != nil {
+				t.Fatal(err)
+			}
+			data, err := serializer.Serialize(body)
+			if err != nil {
+				t.Fatalf("Serialize() error = %v", err)
+			}
+			if json.Valid(data) {
+				t.Fatalf("Serialize() = %q, want a MessagePack body", data)
+			}
+			var decoded interface{}
+			if err := serializer.Deserialize(data, &decoded); err != nil {
+				t.Fatalf("Deserialize() error = %v", err)
+			}
+			got, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("failed to marshal the decoded body: %v", err)
+			}
+			if string(got) != tt.body {
+				t.Fatalf("the round trip of %s = %s", tt.body, got)
+			}
+		})
+	}
+}
+
+func TestMessagePackBodySerializerEncodesTheIntegers(t *testing.T) {
+	// 0x01 is the MessagePack positive fixint 1 and 0xcb the prefix of a float64
+	tests := []struct {
+		name  string
+		value interface{}
+		want  byte
+	}{
+		{name: "integral json number", value: float64(1), want: 0x01},
+		{name: "fractional json number", value: 1.5, want: 0xcb},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MessagePackBodySerializer{}.Serialize(tt.value)
+			if err != nil {
+				t.Fatalf("Serialize() error = %v", err)
+			}
+			if data[0] != tt.want {
+				t.Fatalf("Serialize(%v) = %x, want the prefix %x", tt.value, data, tt.want)
+			}
+		})
+	}
+}
@@ -2,25 +2,51 @@ package replay
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.keploy.io/server/v2/pkg/models"
 )
 
+// ErrCoverageBelowThreshold is returned by Start when the go coverage of the test run is below test.minCoverage.
+var ErrCoverageBelowThreshold = errors.New("the coverage is below the minimum coverage")
+
+// TestSetVerdict is the outcome of a test set run, aggregated by Start into the summary of the test run.
+type TestSetVerdict struct {
+	Total  int
+	Passed int
+	Failed int
+	Status bool
+	// results are the results of the test cases, added to the junit report of the test run
+	results []models.TestResult
+}
+
+// RequestTransformer rewrites the http request of a test case right before it is sent to the application,
+// e.g. to replace an expired auth token of the recorded request with a fresh one.
+type RequestTransformer interface {
+	Transform(ctx context.Context, req *models.HTTPReq) (*models.HTTPReq, error)
+}
+
 type Instrumentation interface {
 	//Setup prepares the environment for the recording
 	Setup(ctx context.Context, cmd string, opts models.SetupOptions) (uint64, error)
 	//Hook will load hooks and start the proxy server.
-	Hook(ctx context.Context, id uint64, opts models.HookOptions) error
+	Hook(ctx context.Context, id uint64, opts models.HookOptions) (*models.HookMetadata, error)
 	MockOutgoing(ctx context.Context, id uint64, opts models.OutgoingOptions) error
 	// SetMocks Allows for setting mocks between test runs for better filtering and matching
 	SetMocks(ctx context.Context, id uint64, filtered []*models.Mock, unFiltered []*models.Mock) error
 	// GetConsumedMocks to log the names of the mocks that were consumed during the test run of failed test cases
 	GetConsumedMocks(ctx context.Context, id uint64) ([]string, error)
+	// GetFuzzyMatchedMocks returns the names of the consumed mocks which were matched fuzzily rather than exactly
+	GetFuzzyMatchedMocks(ctx context.Context, id uint64) ([]string, error)
 	// Run is blocking call and will execute until error
 	Run(ctx context.Context, id uint64, opts models.RunOptions) models.AppError
 
 	GetContainerIP(ctx context.Context, id uint64) (string, error)
+	// TakeSnapshot checkpoints the process tree of the app and returns the id of the snapshot
+	TakeSnapshot(ctx context.Context, id uint64) (string, error)
+	// RestoreSnapshot restores the app to the snapshot, isolating the test cases without restarting the app
+	RestoreSnapshot(ctx context.Context, id uint64, snapshotID string) error
 }
 
 type Service interface {
@@ -28,28 +54,83 @@ type Service interface {
 	Instrument(ctx context.Context) (*InstrumentState, error)
 	GetNextTestRunID(ctx context.Context) (string, error)
 	GetAllTestSetIDs(ctx context.Context) ([]string, error)
-	RunTestSet(ctx context.Context, testSetID string, testRunID string, appID uint64, serveTest bool) (models.TestSetStatus, error)
+	// GetShardedTestSetIDs returns the test sets of the shard, every test set whose index in the sorted test set ids modulo shardTotal is shardIndex
+	GetShardedTestSetIDs(ctx context.Context, shardIndex, shardTotal int) ([]string, error)
+	// AddRequestTransformer runs the http requests of the test cases through the transformer before they are sent, after the transformers added before it
+	AddRequestTransformer(t RequestTransformer)
+	// FetchTestCase reads a single test case of the test set, models.ErrTestCaseNotFound is returned when it does not exist
+	FetchTestCase(ctx context.Context, testSetID, testCaseID string) (*models.TestCase, error)
+	RunTestSet(ctx context.Context, testSetID string, testRunID string, appID uint64, serveTest bool) (models.TestSetStatus, TestSetVerdict, error)
 	GetTestSetStatus(ctx context.Context, testRunID string, testSetID string) (models.TestSetStatus, error)
 	RunApplication(ctx context.Context, appID uint64, opts models.RunOptions) models.AppError
 	Normalize(ctx context.Context) error
 	DenoiseTestCases(ctx context.Context, testSetID string, noiseParams []*models.NoiseParams) ([]*models.NoiseParams, error)
+	// AutoDenoise replays the test set sampleRuns times and returns the noise params of the fields differing in more than the Test.DenoiseThreshold fraction of the runs
+	AutoDenoise(ctx context.Context, testSetID string, sampleRuns int) ([]*models.NoiseParams, error)
 	NormalizeTestCases(ctx context.Context, testRun string, testSetID string, selectedTestCaseIDs []string, testResult []models.TestResult) error
 	DeleteTests(ctx context.Context, testSetID string, testCaseIDs []string) error
 	DeleteTestSet(ctx context.Context, testSetID string) error
+	// CreateTestCase validates the test case and inserts it in the test set, without going through the record flow
+	CreateTestCase(ctx context.Context, testSetID string, tc *models.TestCase) error
+	MergeTestSets(ctx context.Context, targetID string, sourceIDs []string) error
+	SplitTestSet(ctx context.Context, setID string, chunkSize int) ([]string, error)
+	// ExportTestSet writes the test cases, the mocks and the config of the test set as a zip bundle to the destination path
+	ExportTestSet(ctx context.Context, testSetID, destPath string) error
+	// ImportTestSet imports the zip bundle of a test set, suffixing its id when it is taken, and returns the id it is imported as
+	ImportTestSet(ctx context.Context, srcPath string) (string, error)
+	// ExtractFailures copies the failed test cases of the test run and their mocks into a new test set
+	ExtractFailures(ctx context.Context, testRunID, newSetID string) error
+	// GenerateHTMLReport writes an html report of the test run with the diffs of the failed test cases
+	GenerateHTMLReport(ctx context.Context, testRunID string, outputDir string) error
+	// GetStabilityScore returns the average pass rate of the test set over its last N test runs
+	GetStabilityScore(ctx context.Context, testSetID string, lastN int) (float64, error)
+	// ListFlakyTestCases lists the test cases passing less than the threshold ratio of the last N test runs
+	ListFlakyTestCases(ctx context.Context, testSetID string, lastN int, threshold float64) ([]string, error)
+	// MockCoverageReport lists the mocks of the test set and the test cases which consumed them in the test run
+	MockCoverageReport(ctx context.Context, testRunID, testSetID string) (*models.MockCoverageReport, error)
+	// GetMockFidelityReport counts the mocks of the test run matched exactly, matched fuzzily and not matched, the latest test run when the id is empty
+	GetMockFidelityReport(ctx context.Context, testRunID string) (*models.MockFidelityReport, error)
+	// GetMocksGroupedByEndpoint groups the mocks of the test set by the endpoint they mock, "METHOD /path" for http
+	GetMocksGroupedByEndpoint(ctx context.Context, testSetID string) (map[string][]*models.Mock, error)
+	// GenerateFromAccessLog generates a test case without response in the test set for each request of the nginx, apache or clf access log
+	GenerateFromAccessLog(ctx context.Context, logPath string, format string, testSetID string) (int, error)
+	// ExportDependencyGraph writes the graph of the test cases of the test set, their dependencies and consumed mocks, as dot, mermaid or json
+	ExportDependencyGraph(ctx context.Context, testSetID string, format string, outputPath string) error
+	// MigrateMocks migrates the mocks of the test set from a schema version to another, it returns the number of the migrated mocks
+	MigrateMocks(ctx context.Context, testSetID string, fromVersion, toVersion int) (int, error)
+	// EncryptMocks encrypts the mock files of the test sets, all of them when none is given, it returns the number of the encrypted files
+	EncryptMocks(ctx context.Context, testSetIDs []string) (int, error)
+	// DecryptMocks decrypts the mock files of the test sets, all of them when none is given, it returns the number of the decrypted files
+	DecryptMocks(ctx context.Context, testSetIDs []string) (int, error)
+	// DeduplicateTestCases deletes the test cases of the test set with the same request as an earlier one
+	DeduplicateTestCases(ctx context.Context, testSetID string, strategy models.DedupStrategy) (removed int, err error)
+	// SetResultSink streams the test case results on the channel as they are inserted in the reports, nil unregisters it
+	SetResultSink(sink chan<- models.TestResult)
+	// StreamTestProgress streams the progress events of the test run on the channel until the context is done, an empty test run id streams every test run
+	StreamTestProgress(ctx context.Context, testRunID string, ch chan<- models.TestProgressEvent) error
 }
 
 type TestDB interface {
 	GetAllTestSetIDs(ctx context.Context) ([]string, error)
 	GetTestCases(ctx context.Context, testSetID string) ([]*models.TestCase, error)
+	GetTestCase(ctx context.Context, testSetID, testCaseID string) (*models.TestCase, error)
 	UpdateTestCase(ctx context.Context, testCase *models.TestCase, testSetID string) error
+	InsertTestCase(ctx context.Context, testCase *models.TestCase, testSetID string) error
 	DeleteTests(ctx context.Context, testSetID string, testCaseIDs []string) error
 	DeleteTestSet(ctx context.Context, testSetID string) error
 }
 
 type MockDB interface {
-	GetFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time) ([]*models.Mock, error)
-	GetUnFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time) ([]*models.Mock, error)
+	// GetFilteredMocks and GetUnFilteredMocks exclude the mocks whose recorded time plus their ttl is before the
+	// cutoff, the zero cutoff keeps all the mocks
+	GetFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time, cutoff time.Time) ([]*models.Mock, error)
+	GetUnFilteredMocks(ctx context.Context, testSetID string, afterTime time.Time, beforeTime time.Time, cutoff time.Time) ([]*models.Mock, error)
 	UpdateMocks(ctx context.Context, testSetID string, mockNames map[string]bool) error
+	InsertMock(ctx context.Context, mock *models.Mock, testSetID string) error
+	GetMocksGroupedByEndpoint(ctx context.Context, testSetID string) (map[string][]*models.Mock, error)
+	ReplaceMocks(ctx context.Context, testSetID string, mocks []*models.Mock, migrator models.MockMigrator) (int, error)
+	EncryptMocks(ctx context.Context, testSetID string) (bool, error)
+	DecryptMocks(ctx context.Context, testSetID string) (bool, error)
 }
 
 type ReportDB interface {
@@ -58,6 +139,7 @@ type ReportDB interface {
 	GetReport(ctx context.Context, testRunID string, testSetID string) (*models.TestReport, error)
 	InsertTestCaseResult(ctx context.Context, testRunID string, testSetID string, result *models.TestResult) error
 	InsertReport(ctx context.Context, testRunID string, testSetID string, testReport *models.TestReport) error
+	InsertMockCoverageReport(ctx context.Context, testRunID string, testSetID string, report *models.MockCoverageReport) error
 }
 
 type Config interface {
@@ -69,6 +151,8 @@ type Telemetry interface {
 	TestSetRun(success int, failure int, testSet string, runStatus string)
 	TestRun(success int, failure int, testSets int, runStatus string)
 	MockTestRun(utilizedMocks int)
+	// Flush sends the buffered telemetry events
+	Flush()
 }
 
 // RequestMockHandler defines an interface for implementing hooks that extend and customize
@@ -84,8 +168,9 @@ type RequestMockHandler interface {
 }
 
 type InstrumentState struct {
-	AppID      uint64
-	HookCancel context.CancelFunc
+	AppID        uint64
+	HookCancel   context.CancelFunc
+	HookMetadata *models.HookMetadata
 }
 
 type MockAction string
@@ -0,0 +1,38 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// GetShardedTestSetIDs returns the test sets of the shard of a test run split across shardTotal CI workers.
+func (r *Replayer) GetShardedTestSetIDs(ctx context.Context, shardIndex, shardTotal int) ([]string, error) {
+	testSetIDs, err := r.testDB.GetAllTestSetIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all test set ids: %w", err)
+	}
+	return shardTestSetIDs(testSetIDs, shardIndex, shardTotal)
+}
+
+// shardTestSetIDs returns every test set whose index in the sorted test set ids modulo shardTotal is shardIndex.
+// The ids are sorted so that the workers agree on the shards whatever the order the test sets are listed in.
+func shardTestSetIDs(testSetIDs []string, shardIndex, shardTotal int) ([]string, error) {
+	if shardTotal < 1 {
+		return nil, fmt.Errorf("invalid shard total %d, it should be at least 1", shardTotal)
+	}
+	if shardIndex < 0 || shardIndex >= shardTotal {
+		return nil, fmt.Errorf("invalid shard index %d, it should be between 0 and %d", shardIndex, shardTotal-1)
+	}
+	sorted := append([]string(nil), testSetIDs...)
+	sort.Strings(sorted)
+	shard := []string{}
+	for i, testSetID := range sorted {
+		if i%shardTotal == shardIndex {
+			shard = append(shard, testSetID)
+		}
+	}
+	return shard, nil
+}
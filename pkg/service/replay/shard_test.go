@@ -0,0 +1,74 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestGetShardedTestSetIDs(t *testing.T) {
+	// the test sets are listed out of order, as the directory entries may be
+	testSetIDs := []string{"test-set-3", "test-set-0", "test-set-4", "test-set-1", "test-set-2"}
+
+	tests := []struct {
+		name       string
+		testSetIDs []string
+		shardIndex int
+		shardTotal int
+		want       []string
+		wantErr    bool
+	}{
+		{name: "single shard", testSetIDs: testSetIDs, shardIndex: 0, shardTotal: 1, want: []string{"test-set-0", "test-set-1", "test-set-2", "test-set-3", "test-set-4"}},
+		{name: "first of the uneven shards", testSetIDs: testSetIDs, shardIndex: 0, shardTotal: 2, want: []string{"test-set-0", "test-set-2", "test-set-4"}},
+		{name: "last of the uneven shards", testSetIDs: testSetIDs, shardIndex: 1, shardTotal: 2, want: []string{"test-set-1", "test-set-3"}},
+		{name: "more shards than the test sets", testSetIDs: testSetIDs, shardIndex: 6, shardTotal: 7, want: []string{}},
+		{name: "no test set", shardIndex: 0, shardTotal: 3, want: []string{}},
+		{name: "lexical order of the ids", testSetIDs: []string{"test-set-2", "test-set-10", "test-set-1"}, shardIndex: 1, shardTotal: 3, want: []string{"test-set-10"}},
+		{name: "zero shard total", testSetIDs: testSetIDs, shardIndex: 0, shardTotal: 0, wantErr: true},
+		{name: "negative shard total", testSetIDs: testSetIDs, shardIndex: 0, shardTotal: -1, wantErr: true},
+		{name: "negative shard index", testSetIDs: testSetIDs, shardIndex: -1, shardTotal: 2, wantErr: true},
+		{name: "shard index equal to the shard total", testSetIDs: testSetIDs, shardIndex: 2, shardTotal: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed := append([]string(nil), tt.testSetIDs...)
+			r := &Replayer{logger: zap.NewNop(), testDB: &fakeTestDB{testSetIDs: listed}}
+			got, err := r.GetShardedTestSetIDs(context.Background(), tt.shardIndex, tt.shardTotal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetShardedTestSetIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("GetShardedTestSetIDs() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(listed, tt.testSetIDs) {
+				t.Fatalf("GetShardedTestSetIDs() reordered the listed test sets to %v", listed)
+			}
+		})
+	}
+}
+
+func TestShardTestSetIDsCoversEveryTestSetOnce(t *testing.T) {
+	testSetIDs := []string{"test-set-0", "test-set-1", "test-set-2", "test-set-3", "test-set-4", "test-set-5", "test-set-6"}
+	for _, shardTotal := range []int{1, 2, 3, 7, 10} {
+		var all []string
+		for shardIndex := 0; shardIndex < shardTotal; shardIndex++ {
+			shard, err := shardTestSetIDs(testSetIDs, shardIndex, shardTotal)
+			if err != nil {
+				t.Fatalf("shardTestSetIDs(%d, %d) error = %v", shardIndex, shardTotal, err)
+			}
+			if len(shard) > len(testSetIDs)/shardTotal+1 {
+				t.Fatalf("shardTestSetIDs(%d, %d) = %v, the shards are unbalanced", shardIndex, shardTotal, shard)
+			}
+			all = append(all, shard...)
+		}
+		sort.Strings(all)
+		if !reflect.DeepEqual(all, testSetIDs) {
+			t.Fatalf("the %d shards = %v, want every test set once", shardTotal, all)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package replay
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// WithRequestSigning signs the http requests sent to the application with the hmac of the request signing config.
+func WithRequestSigning(signing *config.RequestSigningConfig) RequestMockUtilOption {
+	return func(t *requestMockUtil) {
+		t.signing = signing
+	}
+}
+
+// signingHash returns the hash function of the hmac algorithm.
+func signingHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "hmac-sha256":
+		return sha256.New, nil
+	case "hmac-sha512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported request signing algorithm %q, it should be hmac-sha256 or hmac-sha512", algorithm)
+}
+
+// signRequest adds the hex encoded hmac of the canonical request to the signature header of the request.
+// The canonical request is the method, the path, the sorted signed headers and the sha256 hash of the body,
+// separated by new lines. The multipart bodies are signed as recorded, as their boundary is only known when sent.
+func signRequest(req *models.HTTPReq, signing *config.RequestSigningConfig) error {
+	newHash, err := signingHash(signing.Algorithm)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse the url of the request: %w", err)
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	headers := make(map[string]string, len(req.Header))
+	for key, value := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(value)
+	}
+	signedHeaders := make([]string, 0, len(signing.SignedHeaders))
+	for _, header := range signing.SignedHeaders {
+		signedHeaders = append(signedHeaders, strings.ToLower(header))
+	}
+	sort.Strings(signedHeaders)
+
+	var canonical strings.Builder
+	canonical.WriteString(strings.ToUpper(string(req.Method)) + "\n")
+	canonical.WriteString(path + "\n")
+	for _, header := range signedHeaders {
+		canonical.WriteString(header + ":" + headers[header] + "\n")
+	}
+	bodyHash := sha256.Sum256([]byte(req.Body))
+	canonical.WriteString(hex.EncodeToString(bodyHash[:]))
+
+	mac := hmac.New(newHash, []byte(signing.SecretKey))
+	mac.Write([]byte(canonical.String()))
+
+	header := make(map[string]string, len(req.Header)+1)
+	for key, value := range req.Header {
+		// a signature recorded with the request is outdated
+		if strings.EqualFold(key, signing.SignatureHeader) {
+			continue
+		}
+		header[key] = value
+	}
+	header[signing.SignatureHeader] = hex.EncodeToString(mac.Sum(nil))
+	req.Header = header
+	return nil
+}
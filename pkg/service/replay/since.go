@@ -0,0 +1,55 @@
+//go:build linux
+
+package replay
+
+import (
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"go.keploy.io/server/v2/config"
+	"go.uber.org/zap"
+)
+
+// testSetsModifiedSince returns the test sets with a test case or a mock file modified since the time.
+// The test sets whose files can't be read are kept, so that they are not skipped silently.
+func (r *Replayer) testSetsModifiedSince(testSetIDs []string, since time.Time) []string {
+	var modified []string
+	for _, testSetID := range testSetIDs {
+		ok, err := modifiedSince(filepath.Join(config.StoragePath(r.config), testSetID), since)
+		if err != nil {
+			r.logger.Warn("failed to read the modification time of the test set, running it", zap.String("test-set", testSetID), zap.Error(err))
+			modified = append(modified, testSetID)
+			continue
+		}
+		if !ok {
+			r.logger.Info("skipping the test set not modified since the given time", zap.String("test-set", testSetID), zap.Time("since", since))
+			continue
+		}
+		modified = append(modified, testSetID)
+	}
+	return modified
+}
+
+// modifiedSince reports whether a file of the directory was modified since the time.
+func modifiedSince(dir string, since time.Time) (bool, error) {
+	found := false
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().Before(since) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
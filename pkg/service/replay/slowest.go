@@ -0,0 +1,50 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SlowTestCase is a test case of the test run with the time its response took.
+type SlowTestCase struct {
+	TestSetID  string `json:"testSetId"`
+	TestCaseID string `json:"testCaseId"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// slowestTestCases returns the Test.SlowTestTopN slowest test cases of the test sets of the test run, read from
+// their reports. The latency of the response is used, or the timestamps of the result when it isn't known.
+func (r *Replayer) slowestTestCases(ctx context.Context, testRunID string, testSetIDs []string) []SlowTestCase {
+	topN := r.config.Test.SlowTestTopN
+	if topN <= 0 {
+		return nil
+	}
+	var testCases []SlowTestCase
+	for _, testSetID := range testSetIDs {
+		results, err := r.reportDB.GetTestCaseResults(ctx, testRunID, testSetID)
+		if err != nil {
+			r.logger.Debug("failed to get the test case results for the slowest test cases", zap.String("test-set", testSetID), zap.Error(err))
+			continue
+		}
+		for _, result := range results {
+			durationMs := result.LatencyMs
+			if durationMs == 0 {
+				// the timestamps only have a precision of seconds
+				durationMs = (result.Completed - result.Started) * int64(time.Second/time.Millisecond)
+			}
+			testCases = append(testCases, SlowTestCase{TestSetID: testSetID, TestCaseID: result.TestCaseID, DurationMs: durationMs})
+		}
+	}
+	sort.SliceStable(testCases, func(i, j int) bool {
+		return testCases[i].DurationMs > testCases[j].DurationMs
+	})
+	if len(testCases) > topN {
+		testCases = testCases[:topN]
+	}
+	return testCases
+}
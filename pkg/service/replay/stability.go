@@ -0,0 +1,100 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// GetStabilityScore returns the average pass rate of the test set over its last N test runs.
+// A score of 1.0 means the test set always passed, while a lower score means it is flaky or failing.
+// All the test runs are considered when lastN is not positive.
+func (r *Replayer) GetStabilityScore(ctx context.Context, testSetID string, lastN int) (float64, error) {
+	reports, err := r.lastReports(ctx, testSetID, lastN)
+	if err != nil {
+		return 0, err
+	}
+
+	var sum float64
+	var runs int
+	for _, report := range reports {
+		if report.Total == 0 {
+			continue
+		}
+		sum += float64(report.Success) / float64(report.Total)
+		runs++
+	}
+	if runs == 0 {
+		return 0, fmt.Errorf("no test runs found for the test set %s", testSetID)
+	}
+	return sum / float64(runs), nil
+}
+
+// ListFlakyTestCases lists the test cases of the test set which passed in less than the threshold ratio
+// of the last N test runs they were run in.
+func (r *Replayer) ListFlakyTestCases(ctx context.Context, testSetID string, lastN int, threshold float64) ([]string, error) {
+	reports, err := r.lastReports(ctx, testSetID, lastN)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := map[string]int{}
+	passes := map[string]int{}
+	for _, report := range reports {
+		for _, result := range report.Tests {
+			runs[result.TestCaseID]++
+			if result.Status == models.TestStatusPassed {
+				passes[result.TestCaseID]++
+			}
+		}
+	}
+
+	var flaky []string
+	for testCaseID, count := range runs {
+		if float64(passes[testCaseID])/float64(count) < threshold {
+			flaky = append(flaky, testCaseID)
+		}
+	}
+	sort.Strings(flaky)
+	return flaky, nil
+}
+
+// lastReports returns the reports of the test set from its last N test runs, latest first.
+// Test runs in which the test set was not run are skipped.
+func (r *Replayer) lastReports(ctx context.Context, testSetID string, lastN int) ([]*models.TestReport, error) {
+	testRunIDs, err := r.reportDB.GetAllTestRunIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all test run ids: %w", err)
+	}
+	sort.SliceStable(testRunIDs, func(i, j int) bool {
+		return testRunIndex(testRunIDs[i]) > testRunIndex(testRunIDs[j])
+	})
+
+	var reports []*models.TestReport
+	for _, testRunID := range testRunIDs {
+		if lastN > 0 && len(reports) == lastN {
+			break
+		}
+		report, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+		if err != nil || report == nil {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// testRunIndex returns the index of the test run id (e.g. 3 for test-run-3), or -1 if it has none.
+func testRunIndex(testRunID string) int {
+	idx, err := strconv.Atoi(strings.TrimPrefix(testRunID, models.TestRunTemplateName))
+	if err != nil {
+		return -1
+	}
+	return idx
+}
@@ -0,0 +1,76 @@
+//go:build linux
+
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// testRunSummary is the machine-readable summary of the complete test run.
+type testRunSummary struct {
+	TestRunID   string `json:"testRunId"`
+	Passed      bool   `json:"passed"`
+	Aborted     bool   `json:"aborted"`
+	TotalTests  int    `json:"totalTests"`
+	TotalPassed int    `json:"totalPassed"`
+	TotalFailed int    `json:"totalFailed"`
+	// DurationMs is the wall-clock duration of the test run and AvgTestCaseMs its average per test case
+	DurationMs    int64            `json:"durationMs"`
+	AvgTestCaseMs float64          `json:"avgTestCaseMs"`
+	TestSets      []testSetSummary `json:"testSets"`
+	// FirstFailure is the failing test case at which the run stopped in the fail fast mode
+	FirstFailure *FailedTestCase `json:"firstFailure,omitempty"`
+}
+
+type testSetSummary struct {
+	Name   string `json:"name"`
+	Total  int    `json:"total"`
+	Passed int    `json:"passed"`
+	Failed int    `json:"failed"`
+	Status bool   `json:"status"`
+}
+
+// writeJSONSummary writes the summary of the test sets run so far as json to the file at the path.
+// The run is reported as aborted when it stopped at the first failing test case in the fail fast mode.
+func writeJSONSummary(path string, state *runState, testRunID string, testRunResult, aborted bool, firstFailure *FailedTestCase) error {
+	aborted = aborted || firstFailure != nil
+	totalTests, totalTestPassed, totalTestFailed := state.totals()
+	elapsed, perTestCase := state.timing()
+	summary := testRunSummary{
+		TestRunID:     testRunID,
+		Passed:        testRunResult && !aborted,
+		Aborted:       aborted,
+		FirstFailure:  firstFailure,
+		TotalTests:    totalTests,
+		TotalPassed:   totalTestPassed,
+		TotalFailed:   totalTestFailed,
+		DurationMs:    elapsed.Milliseconds(),
+		AvgTestCaseMs: durationMs(perTestCase),
+		TestSets:      []testSetSummary{},
+	}
+	for _, testSetID := range state.sortedTestSuiteNames() {
+		verdict := state.verdict(testSetID)
+		summary.TestSets = append(summary.TestSets, testSetSummary{
+			Name:   testSetID,
+			Total:  verdict.Total,
+			Passed: verdict.Passed,
+			Failed: verdict.Failed,
+			Status: verdict.Status,
+		})
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the test run summary: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return fmt.Errorf("failed to create the summary directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o777); err != nil {
+		return fmt.Errorf("failed to write the test run summary: %w", err)
+	}
+	return nil
+}
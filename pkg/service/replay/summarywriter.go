@@ -0,0 +1,271 @@
+//go:build linux
+
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/k0kubun/pp/v3"
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// SummaryWriter writes the results of the test cases and the summaries of the test sets and of the test run.
+type SummaryWriter interface {
+	TestCaseResult(testSetID string, result *models.TestResult, duration time.Duration) error
+	TestSetSummary(testSetID string, status models.TestSetStatus, verdict TestSetVerdict) error
+	TestRunSummary(summary RunSummary) error
+}
+
+// RunSummary is the summary of the complete test run.
+type RunSummary struct {
+	TestRunID   string
+	Passed      bool
+	TotalTests  int
+	TotalPassed int
+	TotalFailed int
+	TestSets    []TestSetRow
+	// Duration is the wall-clock duration of the test run and AvgTestCaseDuration its average per test case
+	Duration            time.Duration
+	AvgTestCaseDuration time.Duration
+	// FirstFailure is set when the run stopped at the first failing test case in the fail fast mode
+	FirstFailure *FailedTestCase
+	// SlowestTestCases are the slowest test cases of the test run, slowest first, when Test.SlowTestTopN is set
+	SlowestTestCases []SlowTestCase
+	// MockFidelity counts the mocks of the test run matched exactly, fuzzily or not at all
+	MockFidelity *models.MockFidelityReport
+}
+
+// TestSetRow is the line of a test set in the summary of the test run.
+type TestSetRow struct {
+	TestSetID string
+	Verdict   TestSetVerdict
+	// Stability is the ratio of the passing runs of the test set over the last test runs, nil if unknown
+	Stability *float64
+}
+
+// newSummaryWriter returns the summary writer of the output format, text (the default), json or tap.
+func newSummaryWriter(format string, out io.Writer) SummaryWriter {
+	switch format {
+	case "json":
+		return NewJSONSummaryWriter(out)
+	case "tap":
+		return NewTAPSummaryWriter(out)
+	}
+	return &TextSummaryWriter{}
+}
+
+// TextSummaryWriter pretty prints the summaries in colour for the terminals, the results of the
+// test cases are already logged as they complete.
+type TextSummaryWriter struct{}
+
+func (TextSummaryWriter) TestCaseResult(string, *models.TestResult, time.Duration) error {
+	return nil
+}
+
+func (TextSummaryWriter) TestSetSummary(testSetID string, status models.TestSetStatus, verdict TestSetVerdict) error {
+	if status != models.TestSetStatusFailed && status != models.TestSetStatusPassed && status != models.TestSetStatusTimedOut {
+		return nil
+	}
+	if status == models.TestSetStatusPassed {
+		pp.SetColorScheme(models.PassingColorScheme)
+	} else {
+		pp.SetColorScheme(models.FailingColorScheme)
+	}
+	// the totals of a timed out test set only count the test cases completed before the deadline
+	if status == models.TestSetStatusTimedOut {
+		testSetID += " (timed out)"
+	}
+	_, err := pp.Printf("\n <=========================================> \n  TESTRUN SUMMARY. For test-set: %s\n"+"\tTotal tests: %s\n"+"\tTotal test passed: %s\n"+"\tTotal test failed: %s\n <=========================================> \n\n", testSetID, verdict.Total, verdict.Passed, verdict.Failed)
+	if err != nil {
+		return fmt.Errorf("failed to print testrun summary: %w", err)
+	}
+	return nil
+}
+
+func (TextSummaryWriter) TestRunSummary(summary RunSummary) error {
+	if _, err := pp.Printf("\n <=========================================> \n  COMPLETE TESTRUN SUMMARY. \n\tTotal tests: %s\n"+"\tTotal test passed: %s\n"+"\tTotal test failed: %s\n"+"\tTotal time: %s\n"+"\tAverage time per test case: %s\n", summary.TotalTests, summary.TotalPassed, summary.TotalFailed, summary.Duration.Round(time.Millisecond).String(), summary.AvgTestCaseDuration.Round(time.Millisecond).String()); err != nil {
+		return fmt.Errorf("failed to print test run summary: %w", err)
+	}
+	if models.IsColorDisabled() {
+		if err := printPlainTestSetTable(summary.TestSets); err != nil {
+			return fmt.Errorf("failed to print test suite summary: %w", err)
+		}
+	} else {
+		if _, err := pp.Printf("\n\tTest Suite Name\t\tTotal Test\tPassed\t\tFailed\t\tStability\t\n"); err != nil {
+			return fmt.Errorf("failed to print test suite summary: %w", err)
+		}
+		for _, row := range summary.TestSets {
+			if row.Verdict.Status {
+				pp.SetColorScheme(models.PassingColorScheme)
+			} else {
+				pp.SetColorScheme(models.FailingColorScheme)
+			}
+			if _, err := pp.Printf("\n\t%s\t\t%s\t\t%s\t\t%s\t\t%s", row.TestSetID, row.Verdict.Total, row.Verdict.Passed, row.Verdict.Failed, stabilityString(row.Stability)); err != nil {
+				return fmt.Errorf("failed to print test suite details: %w", err)
+			}
+		}
+	}
+	if _, err := pp.Printf("\n<=========================================> \n\n"); err != nil {
+		return fmt.Errorf("failed to print separator: %w", err)
+	}
+	if summary.FirstFailure != nil {
+		if _, err := pp.Printf("\n  TEST RUN ABORTED EARLY (fail fast) at the first failing test case %s of %s\n\n", summary.FirstFailure.TestCaseID, summary.FirstFailure.TestSetID); err != nil {
+			return fmt.Errorf("failed to print the first failing test case: %w", err)
+		}
+	}
+	if len(summary.SlowestTestCases) > 0 {
+		if _, err := pp.Printf("\n  TOP %s SLOWEST TEST CASES\n\tTest Case\t\tTest Suite Name\t\tDuration\n", len(summary.SlowestTestCases)); err != nil {
+			return fmt.Errorf("failed to print the slowest test cases: %w", err)
+		}
+		for _, slow := range summary.SlowestTestCases {
+			if _, err := pp.Printf("\t%s\t\t%s\t\t%s\n", slow.TestCaseID, slow.TestSetID, (time.Duration(slow.DurationMs) * time.Millisecond).String()); err != nil {
+				return fmt.Errorf("failed to print the slowest test case: %w", err)
+			}
+		}
+	}
+	if fidelity := summary.MockFidelity; fidelity != nil && fidelity.TotalMocks > 0 {
+		if _, err := pp.Printf("\n  MOCK FIDELITY\n\tTotal mocks: %s\n\tPerfectly matched: %s\n\tFuzzily matched: %s\n\tUnmatched: %s\n", fidelity.TotalMocks, fidelity.PerfectlyMatchedMocks, fidelity.FuzzilyMatchedMocks, fidelity.UnmatchedMocks); err != nil {
+			return fmt.Errorf("failed to print the mock fidelity: %w", err)
+		}
+	}
+	return nil
+}
+
+// printPlainTestSetTable prints the table of the test sets padded with spaces, the tab stops don't align the cells
+// longer than a tab, e.g. the long test set ids, in the plain text of the log files.
+func printPlainTestSetTable(rows []TestSetRow) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	if _, err := fmt.Fprintf(w, "\n\tTest Suite Name\tTotal Test\tPassed\tFailed\tStability\t\n"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "\t%s\t%d\t%d\t%d\t%s\t\n", row.TestSetID, row.Verdict.Total, row.Verdict.Passed, row.Verdict.Failed, stabilityString(row.Stability)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func stabilityString(stability *float64) string {
+	if stability == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *stability)
+}
+
+// JSONSummaryWriter writes every result and summary as a single line json object for the log aggregators.
+type JSONSummaryWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewJSONSummaryWriter(out io.Writer) *JSONSummaryWriter {
+	return &JSONSummaryWriter{out: out}
+}
+
+type jsonTestCaseEvent struct {
+	Event      string `json:"event"`
+	TestSetID  string `json:"testSetID"`
+	TestCaseID string `json:"testCaseID"`
+	Passed     bool   `json:"passed"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+type jsonTestSetEvent struct {
+	Event     string `json:"event,omitempty"`
+	TestSetID string `json:"testSetID"`
+	Status    string `json:"status"`
+	Total     int    `json:"total"`
+	Passed    int    `json:"passed"`
+	Failed    int    `json:"failed"`
+}
+
+type jsonTestRunEvent struct {
+	Event         string                     `json:"event"`
+	TestRunID     string                     `json:"testRunID"`
+	Passed        bool                       `json:"passed"`
+	Total         int                        `json:"total"`
+	TotalPassed   int                        `json:"totalPassed"`
+	TotalFailed   int                        `json:"totalFailed"`
+	DurationMs    int64                      `json:"durationMs"`
+	AvgTestCaseMs float64                    `json:"avgTestCaseMs"`
+	TestSets      []jsonTestSetEvent         `json:"testSets"`
+	FirstFailure  *FailedTestCase            `json:"firstFailure,omitempty"`
+	Slowest       []SlowTestCase             `json:"slowestTestCases,omitempty"`
+	MockFidelity  *models.MockFidelityReport `json:"mockFidelity,omitempty"`
+}
+
+func (w *JSONSummaryWriter) TestCaseResult(testSetID string, result *models.TestResult, duration time.Duration) error {
+	return w.write(jsonTestCaseEvent{
+		Event:      "testcase_result",
+		TestSetID:  testSetID,
+		TestCaseID: result.TestCaseID,
+		Passed:     result.Status == models.TestStatusPassed,
+		DurationMs: duration.Milliseconds(),
+	})
+}
+
+func (w *JSONSummaryWriter) TestSetSummary(testSetID string, status models.TestSetStatus, verdict TestSetVerdict) error {
+	return w.write(testSetEvent("testset_summary", testSetID, string(status), verdict))
+}
+
+// durationMs returns the duration in fractional milliseconds, the average of the fast test cases is below 1ms.
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (w *JSONSummaryWriter) TestRunSummary(summary RunSummary) error {
+	event := jsonTestRunEvent{
+		Event:         "testrun_summary",
+		TestRunID:     summary.TestRunID,
+		Passed:        summary.Passed,
+		Total:         summary.TotalTests,
+		TotalPassed:   summary.TotalPassed,
+		TotalFailed:   summary.TotalFailed,
+		DurationMs:    summary.Duration.Milliseconds(),
+		AvgTestCaseMs: durationMs(summary.AvgTestCaseDuration),
+		TestSets:      []jsonTestSetEvent{},
+		FirstFailure:  summary.FirstFailure,
+		Slowest:       summary.SlowestTestCases,
+		MockFidelity:  summary.MockFidelity,
+	}
+	for _, row := range summary.TestSets {
+		status := string(models.TestSetStatusFailed)
+		if row.Verdict.Status {
+			status = string(models.TestSetStatusPassed)
+		}
+		event.TestSets = append(event.TestSets, testSetEvent("", row.TestSetID, status, row.Verdict))
+	}
+	return w.write(event)
+}
+
+func testSetEvent(event, testSetID, status string, verdict TestSetVerdict) jsonTestSetEvent {
+	return jsonTestSetEvent{
+		Event:     event,
+		TestSetID: testSetID,
+		Status:    status,
+		Total:     verdict.Total,
+		Passed:    verdict.Passed,
+		Failed:    verdict.Failed,
+	}
+}
+
+// write writes the event as a line, the writes of the concurrent test sets don't interleave.
+func (w *JSONSummaryWriter) write(event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the summary event: %w", err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.out.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write the summary event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+//go:build linux
+
+package replay
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	yamlLib "gopkg.in/yaml.v3"
+)
+
+// TAPSummaryWriter writes the results in the Test Anything Protocol (version 14). Every test set is a subtest
+// numbering its test cases in order, the test sets are numbered in the order they complete with the plan
+// of the test run written last.
+type TAPSummaryWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	// testSets are the test point lines of the running test sets, written together once the test set completes
+	// so that the concurrent test sets don't interleave
+	testSets map[string][]string
+	started  bool
+	written  int
+}
+
+func NewTAPSummaryWriter(out io.Writer) *TAPSummaryWriter {
+	return &TAPSummaryWriter{out: out, testSets: map[string][]string{}}
+}
+
+// tapDiagnostic is the yaml diagnostic block of a failing test case.
+type tapDiagnostic struct {
+	Message    string `yaml:"message"`
+	Severity   string `yaml:"severity"`
+	DurationMs int64  `yaml:"duration_ms"`
+	Diff       string `yaml:"diff,omitempty"`
+}
+
+func (w *TAPSummaryWriter) TestCaseResult(testSetID string, result *models.TestResult, duration time.Duration) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	number := len(w.testSets[testSetID]) + 1
+	if result.Status == models.TestStatusPassed {
+		w.testSets[testSetID] = append(w.testSets[testSetID], fmt.Sprintf("ok %d - %s\n", number, tapDescription(result.TestCaseID)))
+		return nil
+	}
+
+	diagnostic, err := yamlLib.Marshal(tapDiagnostic{
+		Message:    fmt.Sprintf("test case %s %s", result.TestCaseID, strings.ToLower(string(result.Status))),
+		Severity:   "fail",
+		DurationMs: duration.Milliseconds(),
+		Diff:       resultDiff(result.Result),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the tap diagnostic: %w", err)
+	}
+	var point strings.Builder
+	point.WriteString(fmt.Sprintf("not ok %d - %s\n", number, tapDescription(result.TestCaseID)))
+	point.WriteString("  ---\n")
+	point.WriteString(indent(string(diagnostic), "  "))
+	point.WriteString("  ...\n")
+	w.testSets[testSetID] = append(w.testSets[testSetID], point.String())
+	return nil
+}
+
+func (w *TAPSummaryWriter) TestSetSummary(testSetID string, status models.TestSetStatus, _ TestSetVerdict) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	points := w.testSets[testSetID]
+	delete(w.testSets, testSetID)
+	w.written++
+
+	var sb strings.Builder
+	if !w.started {
+		sb.WriteString("TAP version 14\n")
+		w.started = true
+	}
+	sb.WriteString("# Subtest: " + testSetID + "\n")
+	for _, point := range points {
+		sb.WriteString(indent(point, "    "))
+	}
+	sb.WriteString(fmt.Sprintf("    1..%d\n", len(points)))
+	result := "ok"
+	if status != models.TestSetStatusPassed {
+		result = "not ok"
+	}
+	sb.WriteString(fmt.Sprintf("%s %d - %s\n", result, w.written, tapDescription(testSetID)))
+	return w.write(sb.String())
+}
+
+func (w *TAPSummaryWriter) TestRunSummary(summary RunSummary) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var sb strings.Builder
+	if !w.started {
+		sb.WriteString("TAP version 14\n")
+		w.started = true
+	}
+	sb.WriteString(fmt.Sprintf("1..%d\n", w.written))
+	sb.WriteString(fmt.Sprintf("# test run %s: %d tests, %d passed, %d failed\n", summary.TestRunID, summary.TotalTests, summary.TotalPassed, summary.TotalFailed))
+	sb.WriteString(fmt.Sprintf("# duration: %s, %s per test case\n", summary.Duration.Round(time.Millisecond), summary.AvgTestCaseDuration.Round(time.Millisecond)))
+	if summary.FirstFailure != nil {
+		sb.WriteString(fmt.Sprintf("# aborted early (fail fast) at the first failing test case %s of %s\n", summary.FirstFailure.TestCaseID, summary.FirstFailure.TestSetID))
+	}
+	for _, slow := range summary.SlowestTestCases {
+		sb.WriteString(fmt.Sprintf("# slow: %s of %s took %dms\n", slow.TestCaseID, slow.TestSetID, slow.DurationMs))
+	}
+	if fidelity := summary.MockFidelity; fidelity != nil && fidelity.TotalMocks > 0 {
+		sb.WriteString(fmt.Sprintf("# mocks: %d total, %d perfectly matched, %d fuzzily matched, %d unmatched\n", fidelity.TotalMocks, fidelity.PerfectlyMatchedMocks, fidelity.FuzzilyMatchedMocks, fidelity.UnmatchedMocks))
+	}
+	return w.write(sb.String())
+}
+
+func (w *TAPSummaryWriter) write(s string) error {
+	if _, err := io.WriteString(w.out, s); err != nil {
+		return fmt.Errorf("failed to write the tap output: %w", err)
+	}
+	return nil
+}
+
+// tapDescription escapes the # of the description, which would start a directive.
+func tapDescription(name string) string {
+	return strings.ReplaceAll(name, "#", `\#`)
+}
+
+// indent prefixes every line of s with the prefix.
+func indent(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	var sb strings.Builder
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		sb.WriteString(prefix + line)
+	}
+	return sb.String()
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package replay
+
+import (
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+)
+
+// testCaseNames returns the names of the test cases executed from the name template, by the names of their files.
+// The test cases named alike are suffixed with _N in the order of the test set, as they are when they are recorded.
+// It returns nil when no template is set.
+func (r *Replayer) testCaseNames(testCases []*models.TestCase) map[string]string {
+	if r.config.Test.NameTemplate == "" {
+		return nil
+	}
+	tmpl, err := models.ParseNameTemplate(r.config.Test.NameTemplate)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to parse the name template of the test cases")
+		return nil
+	}
+	names := make(map[string]string, len(testCases))
+	taken := make(map[string]bool, len(testCases))
+	for i, tc := range testCases {
+		name, err := models.TestCaseName(tmpl, tc, i+1, func(fileName string) bool { return taken[fileName] })
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to name the test case with the name template")
+			continue
+		}
+		taken[models.TestCaseFileName(name)] = true
+		names[tc.Name] = name
+	}
+	return names
+}
+
+// setTestCaseNames sets the names executed from the name template on the results of the test cases of the report,
+// their test case ids are kept as the names of the files to normalize or rerun them.
+func setTestCaseNames(results []models.TestResult, names map[string]string) {
+	for i := range results {
+		if name, ok := names[results[i].TestCaseID]; ok {
+			results[i].TestCaseName = name
+		}
+	}
+}
@@ -0,0 +1,76 @@
+//go:build linux
+
+package replay
+
+import (
+	"testing"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestTestCaseNamesMatchTheRecordedFileNames(t *testing.T) {
+	testCase := func(name string, method models.Method, path string, status int) *models.TestCase {
+		return &models.TestCase{
+			Name:     name,
+			Kind:     models.HTTP,
+			HTTPReq:  models.HTTPReq{Method: method, URL: "http://localhost:8080" + path},
+			HTTPResp: models.HTTPResp{StatusCode: status},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		template  string
+		testCases []*models.TestCase
+		want      map[string]string
+	}{
+		{
+			name:     "no template",
+			template: "",
+			testCases: []*models.TestCase{
+				testCase("test-1", "GET", "/api/users", 200),
+			},
+		},
+		{
+			name:     "test cases recorded with the template",
+			template: "test-{{.Method}}-{{.URL}}-{{.StatusCode}}",
+			testCases: []*models.TestCase{
+				testCase("test-GET-_api_users-200", "GET", "/api/users", 200),
+				testCase("test-POST-_api_users-201", "POST", "/api/users", 201),
+				testCase("test-GET-_api_users-200_1", "GET", "/api/users", 200),
+			},
+			want: map[string]string{
+				"test-GET-_api_users-200":   "test-GET-/api/users-200",
+				"test-POST-_api_users-201":  "test-POST-/api/users-201",
+				"test-GET-_api_users-200_1": "test-GET-/api/users-200_1",
+			},
+		},
+		{
+			name:     "test cases recorded before the template",
+			template: "{{.Method}}-{{.Index}}",
+			testCases: []*models.TestCase{
+				testCase("test-1", "GET", "/", 200),
+				testCase("test-2", "DELETE", "/", 204),
+			},
+			want: map[string]string{"test-1": "GET-1", "test-2": "DELETE-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Test.NameTemplate = tt.template
+			r := &Replayer{logger: zap.NewNop(), config: cfg}
+			got := r.testCaseNames(tt.testCases)
+			if len(got) != len(tt.want) {
+				t.Fatalf("testCaseNames() = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("testCaseNames()[%q] = %q, want %q", id, got[id], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// testCaseRun is the context the test cases of a test set are replayed in, by the serial and the parallel runs alike.
+type testCaseRun struct {
+	testRunID string
+	testSetID string
+	userIP    string
+	// serial is set if the mocks of the window of each test case are set up before its requests, the consumed
+	// mocks can only be attributed to the test case then
+	serial bool
+	// chain substitutes and extracts the chained variables of the test cases of the test set
+	chain *requestChain
+}
+
+// testCaseOutcome is the outcome of replaying a single test case.
+type testCaseOutcome struct {
+	// result is nil if the test case could not be run, e.g. its request failed
+	result *models.TestResult
+	// recorded is set if the missing response of the test case got recorded instead of compared
+	recorded bool
+	// consumedMocks and fuzzyMocks are the mocks consumed by the test case, only tracked in the serial run
+	consumedMocks []string
+	fuzzyMocks    []string
+}
+
+// replayTestCase simulates the request of the test case and compares the response. It is the single place a test
+// case is run, so that the serial and the parallel runs of the test cases support the same features.
+func (r *Replayer) replayTestCase(ctx context.Context, appID uint64, run testCaseRun, testCase *models.TestCase) testCaseOutcome {
+	var outcome testCaseOutcome
+	testSetID := run.testSetID
+
+	// keep the recorded URL to persist it back in case the response of the test case is recorded
+	recordedURL := testCase.HTTPReq.URL
+	recordedHeader := testCase.HTTPReq.Header
+	recordedBody := testCase.HTTPReq.Body
+
+	// the placeholders of the environment variables are expanded before the base path is replaced
+	if err := expandEnv(testCase); err != nil {
+		utils.LogError(r.logger, err, "failed to expand the environment variables of the test case", zap.String("testcase", testCase.Name))
+		return outcome
+	}
+
+	// the dependent test case fails with the reason its chained variables are not set
+	if err := run.chain.substitute(testCase); err != nil {
+		utils.LogError(r.logger, err, "failed to substitute the chained variables of the test case", zap.String("testcase", testCase.Name))
+		outcome.result = r.failedResult(testSetID, testCase, time.Now().UTC(), err.Error())
+		return outcome
+	}
+
+	if testCase.Kind == models.GRPC_EXPORT {
+		err := r.rewriteGrpcAuthority(testCase, run.userIP)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to replace the authority of the grpc request", zap.String("testcase", testCase.Name))
+			return outcome
+		}
+	} else if testCase.Kind == models.WS {
+		err := r.rewriteWSURL(testCase, run.userIP)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to replace the host of the websocket url", zap.String("testcase", testCase.Name))
+			return outcome
+		}
+	} else if r.config.Test.BasePath != "" {
+		// replace the request URL's BasePath/origin if provided
+		newURL, err := ReplaceBaseURL(r.config.Test.BasePath, testCase.HTTPReq.URL)
+		if err != nil {
+			r.logger.Warn("failed to replace the request basePath", zap.String("testcase", testCase.Name), zap.String("basePath", r.config.Test.BasePath), zap.Error(err))
+		} else {
+			testCase.HTTPReq.URL = newURL
+		}
+		r.logger.Debug("test case request origin", zap.String("testcase", testCase.Name), zap.String("TestCaseURL", testCase.HTTPReq.URL), zap.String("basePath", r.config.Test.BasePath))
+	} else if utils.IsDockerKind(utils.CmdType(r.config.CommandType)) {
+		newURL, err := utils.ReplaceHostToIP(testCase.HTTPReq.URL, run.userIP)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to replace host to docker container's IP", zap.String("testcase", testCase.Name))
+			return outcome
+		}
+		testCase.HTTPReq.URL = newURL
+		r.logger.Debug("", zap.Any("replaced URL in case of docker env", testCase.HTTPReq.URL))
+	}
+
+	if err := r.transformRequest(ctx, testCase); err != nil {
+		utils.LogError(r.logger, err, "failed to transform the request of the test case", zap.String("testcase", testCase.Name))
+		return outcome
+	}
+
+	scriptsPass := r.runTestCaseScript(ctx, testCase.PreTestCaseScript, "pre", testSetID, run.testRunID, testCase)
+
+	started := time.Now().UTC()
+	resp, err := r.emulatorFor(testCase).SimulateRequest(ctx, appID, testCase, testSetID)
+	if err != nil {
+		// the timed out test case is reported, so that the report tells why it failed
+		if isTimeout(err) && ctx.Err() == nil {
+			outcome.result = r.timeoutResult(testSetID, testCase, started)
+			return outcome
+		}
+		utils.LogError(r.logger, err, "failed to simulate request", zap.String("testcase", testCase.Name))
+		return outcome
+	}
+	latency := time.Since(started)
+	run.chain.extract(testCase.Name, resp)
+
+	// record the response of the test cases which were never recorded (e.g. created from the API documentation)
+	if r.config.Test.RecordMissingTestCases && testCase.Kind != models.GRPC_EXPORT && testCase.Kind != models.WS && missingResponse(testCase) {
+		testCase.HTTPResp = *resp
+		testCase.HTTPReq.URL = recordedURL
+		testCase.HTTPReq.Header = recordedHeader
+		testCase.HTTPReq.Body = recordedBody
+		r.unshiftTestCase(testSetID, testCase)
+		// the response is recorded now, not in the shifted time
+		testCase.HTTPResp.Timestamp = time.Now().UTC()
+		err = r.testDB.UpdateTestCase(ctx, testCase, testSetID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to record the response of the test case", zap.String("testcase", testCase.Name))
+			return outcome
+		}
+		r.logger.Info("recorded the response for the test case", zap.Any("testcase id", models.HighlightString(testCase.Name)), zap.Any("testset id", models.HighlightString(testSetID)))
+		outcome.recorded = true
+		return outcome
+	}
+
+	var consumptionEvents []models.MockConsumptionEvent
+	if run.serial && r.config.Test.BasePath == "" {
+		outcome.consumedMocks, err = r.instrumentation.GetConsumedMocks(ctx, appID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to get consumed filtered mocks")
+		}
+		outcome.fuzzyMocks, err = r.instrumentation.GetFuzzyMatchedMocks(ctx, appID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to get fuzzy matched mocks")
+		}
+		consumptionEvents = mockConsumptionEvents(outcome.consumedMocks, outcome.fuzzyMocks)
+	}
+
+	testPass, testResult, err := r.compareTestCase(ctx, appID, testCase, resp, testSetID)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to compare the response of the test case", zap.String("testcase", testCase.Name))
+		return outcome
+	}
+	attempts := 1
+	if !testPass && r.config.Test.MaxRetries > 0 {
+		// the mocks of the whole window are shared by the test cases running in parallel, so they are not set up again
+		resp, testPass, testResult, attempts = r.retryFailedTestCase(ctx, appID, testCase, testSetID, resp, testResult, run.serial)
+	}
+	var dbFailures []models.DBAssertionFailure
+	if len(testCase.DBAssertions) > 0 {
+		dbFailures = r.runDBAssertions(ctx, testCase)
+		testPass = testPass && len(dbFailures) == 0
+	}
+	testPass = r.checkLatency(testSetID, testCase, latency, testResult) && testPass
+	scriptsPass = r.runTestCaseScript(ctx, testCase.PostTestCaseScript, "post", testSetID, run.testRunID, testCase) && scriptsPass
+	testPass = testPass && scriptsPass
+	testStatus := models.TestStatusPassed
+	if !testPass {
+		testStatus = models.TestStatusFailed
+		// log the consumed mocks during the test run of the test case for test set
+		r.logger.Info("result", zap.Any("testcase id", models.HighlightFailingString(testCase.Name)), zap.Any("testset id", models.HighlightFailingString(testSetID)), zap.Any("passed", models.HighlightFailingString(testPass)))
+		r.logger.Debug("Consumed Mocks", zap.Any("mocks", outcome.consumedMocks))
+	} else {
+		r.logger.Info("result", zap.Any("testcase id", models.HighlightPassingString(testCase.Name)), zap.Any("testset id", models.HighlightPassingString(testSetID)), zap.Any("passed", models.HighlightPassingString(testPass)))
+	}
+	if testResult == nil {
+		utils.LogError(r.logger, nil, "test result is nil", zap.String("testcase", testCase.Name))
+		return outcome
+	}
+	r.collectCoverage(ctx, testSetID, testCase)
+	result := r.newTestResult(testSetID, testCase, resp, testStatus, testResult, started)
+	result.Attempts = attempts
+	result.Flaky = testPass && attempts > 1
+	result.DBAssertionFailures = dbFailures
+	result.LatencyMs = latency.Milliseconds()
+	result.MockConsumptionEvents = consumptionEvents
+	outcome.result = result
+	return outcome
+}
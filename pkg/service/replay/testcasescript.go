@@ -0,0 +1,26 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// runTestCaseScript runs the pre or the post script of the test case. It returns false when the script fails,
+// which fails the test case but lets the remaining test cases of the test set run.
+func (r *Replayer) runTestCaseScript(ctx context.Context, script, stage, testSetID, testRunID string, testCase *models.TestCase) bool {
+	if script == "" {
+		return true
+	}
+	r.logger.Debug("Running the "+stage+" script of the test case", zap.String("script", script), zap.String("testcase", testCase.Name), zap.String("test-set", testSetID))
+	err := r.executeScript(ctx, script, testSetID, testRunID)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to execute the "+stage+" script of the test case", zap.String("testcase", testCase.Name), zap.String("test-set", testSetID))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestReplayTestCaseFailsOnTheTestCaseScriptErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	defer func(emulator RequestMockHandler) { requestMockemulator = emulator }(requestMockemulator)
+	requestMockemulator = NewRequestMockUtil(zap.NewNop(), t.TempDir(), "mocks", 5, server.URL)
+
+	tests := []struct {
+		name       string
+		preScript  string
+		postScript string
+		want       models.TestStatus
+	}{
+		{name: "no scripts", want: models.TestStatusPassed},
+		{name: "passing scripts", preScript: "true", postScript: "exit 0", want: models.TestStatusPassed},
+		{name: "failing pre script", preScript: "exit 1", postScript: "true", want: models.TestStatusFailed},
+		{name: "failing post script", preScript: "true", postScript: "exit 3", want: models.TestStatusFailed},
+		{name: "unknown command", preScript: "keploy-unknown-command", want: models.TestStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Test.BasePath = server.URL
+			cfg.Test.APITimeout = 5
+			r := &Replayer{logger: zap.NewNop(), config: cfg}
+			tc := &models.TestCase{
+				Name:               "test-1",
+				Kind:               models.HTTP,
+				HTTPReq:            models.HTTPReq{Method: "GET", URL: "http://localhost:8080/users/1", ProtoMajor: 1, ProtoMinor: 1},
+				HTTPResp:           models.HTTPResp{StatusCode: 200, Header: map[string]string{"Content-Type": "application/json", "Content-Length": "8", "Date": ""}, Body: `{"id":1}`},
+				Noise:              map[string][]string{"header.date": {}},
+				PreTestCaseScript:  tt.preScript,
+				PostTestCaseScript: tt.postScript,
+			}
+			run := testCaseRun{testRunID: "test-run-0", testSetID: "test-set-0", chain: newRequestChain(nil)}
+			outcome := r.replayTestCase(context.Background(), 0, run, tc)
+			// the script errors fail the test case without aborting its run
+			if outcome.result == nil {
+				t.Fatalf("replayTestCase() did not run the test case")
+			}
+			if outcome.result.Status != tt.want {
+				t.Fatalf("replayTestCase() status = %s, want %s", outcome.result.Status, tt.want)
+			}
+		})
+	}
+}
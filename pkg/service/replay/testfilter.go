@@ -0,0 +1,29 @@
+//go:build linux
+
+package replay
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// filterTestCasesByName keeps the selected test cases whose name matches the test name filter. The filter is
+// unanchored like the go test -run flag, ^ and $ are needed to match the whole name.
+func (r *Replayer) filterTestCasesByName(testCases []*models.TestCase, selectedTests map[string]bool) ([]*models.TestCase, error) {
+	filter, err := regexp.Compile(r.config.Test.TestNameFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid test name filter %q: %w", r.config.Test.TestNameFilter, err)
+	}
+	var filtered []*models.TestCase
+	for _, testCase := range testCases {
+		if _, ok := selectedTests[testCase.Name]; !ok && len(selectedTests) != 0 {
+			continue
+		}
+		if filter.MatchString(testCase.Name) {
+			filtered = append(filtered, testCase)
+		}
+	}
+	return filtered, nil
+}
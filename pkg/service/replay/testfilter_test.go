@@ -0,0 +1,55 @@
+//go:build linux
+
+package replay
+
+import (
+	"reflect"
+	"testing"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestFilterTestCasesByName(t *testing.T) {
+	var testCases []*models.TestCase
+	for _, name := range []string{"test-1", "test-2", "test-10", "login-test-1"} {
+		testCases = append(testCases, &models.TestCase{Name: name})
+	}
+
+	tests := []struct {
+		name          string
+		filter        string
+		selectedTests []string
+		want          []string
+		wantErr       bool
+	}{
+		{name: "empty filter runs everything", filter: "", want: []string{"test-1", "test-2", "test-10", "login-test-1"}},
+		{name: "unanchored pattern", filter: "test-1", want: []string{"test-1", "test-10", "login-test-1"}},
+		{name: "anchored at the start", filter: "^test-1", want: []string{"test-1", "test-10"}},
+		{name: "anchored at both ends", filter: "^test-1$", want: []string{"test-1"}},
+		{name: "anchored character class", filter: "^test-[0-9]$", want: []string{"test-1", "test-2"}},
+		{name: "no match", filter: "^signup", want: nil},
+		{name: "intersection with the selected tests", filter: "^test-1", selectedTests: []string{"test-10", "test-2"}, want: []string{"test-10"}},
+		{name: "empty filter keeps the selected tests", filter: "", selectedTests: []string{"test-2", "login-test-1"}, want: []string{"test-2", "login-test-1"}},
+		{name: "invalid pattern", filter: "test-(", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Test.TestNameFilter = tt.filter
+			r := &Replayer{logger: zap.NewNop(), config: cfg}
+			filtered, err := r.filterTestCasesByName(testCases, ArrayToMap(tt.selectedTests))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterTestCasesByName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, tc := range filtered {
+				got = append(got, tc.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("filterTestCasesByName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
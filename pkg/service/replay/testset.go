@@ -0,0 +1,349 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.keploy.io/server/v2/pkg"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// MergeTestSets moves all the test cases and mocks of the source test sets into the target test set.
+// Test cases whose name already exists in the target are renamed to the next available test case name.
+// The emptied source test sets are deleted once all of their test cases and mocks are moved.
+func (r *Replayer) MergeTestSets(ctx context.Context, targetID string, sourceIDs []string) error {
+	targetCases, err := r.testDB.GetTestCases(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to get test cases of the target test set: %w", err)
+	}
+	names := make(map[string]bool, len(targetCases))
+	for _, tc := range targetCases {
+		names[tc.Name] = true
+	}
+
+	mocks, err := r.getAllMocks(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to get mocks of the target test set: %w", err)
+	}
+	hasTargetMocks := len(mocks) != 0
+
+	var merged []string
+	for _, sourceID := range sourceIDs {
+		if sourceID == targetID {
+			r.logger.Warn("skipping the target test set given as a source", zap.String("test-set", sourceID))
+			continue
+		}
+		testCases, err := r.testDB.GetTestCases(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to get test cases of %s: %w", sourceID, err)
+		}
+		for _, tc := range testCases {
+			if names[tc.Name] {
+				r.logger.Info("renaming the test case as it already exists in the target test set", zap.String("test-case", tc.Name), zap.String("source", sourceID), zap.String("target", targetID))
+				// an empty name lets the db pick the next available test case name, which it sets on the test case
+				tc.Name = ""
+			}
+			err = r.testDB.UpdateTestCase(ctx, tc, targetID)
+			if err != nil {
+				return fmt.Errorf("failed to move test case of %s: %w", sourceID, err)
+			}
+			names[tc.Name] = true
+		}
+
+		sourceMocks, err := r.getAllMocks(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to get mocks of %s: %w", sourceID, err)
+		}
+		mocks = append(mocks, sourceMocks...)
+		merged = append(merged, sourceID)
+	}
+
+	// mocks are matched by their timestamps, so rewrite the target mocks in the order they were recorded.
+	// Rewriting all of them also gives every mock a unique name.
+	if len(mocks) != 0 {
+		sort.SliceStable(mocks, func(i, j int) bool {
+			return mocks[i].Spec.ReqTimestampMock.Before(mocks[j].Spec.ReqTimestampMock)
+		})
+		if hasTargetMocks {
+			// removes every mock of the target test set as none of them is marked to be kept
+			err = r.mockDB.UpdateMocks(ctx, targetID, map[string]bool{})
+			if err != nil {
+				return fmt.Errorf("failed to clear the mocks of the target test set: %w", err)
+			}
+		}
+		for _, mock := range mocks {
+			err = r.mockDB.InsertMock(ctx, mock, targetID)
+			if err != nil {
+				return fmt.Errorf("failed to move mock to the target test set: %w", err)
+			}
+		}
+	}
+
+	for _, sourceID := range merged {
+		err = r.DeleteTestSet(ctx, sourceID)
+		if err != nil {
+			utils.LogError(r.logger, err, "failed to delete the merged test set", zap.String("test-set", sourceID))
+			return fmt.Errorf("failed to delete the merged test set %s: %w", sourceID, err)
+		}
+	}
+
+	r.logger.Info("merged test sets", zap.String("target", targetID), zap.Strings("sources", merged))
+	return nil
+}
+
+// SplitTestSet partitions the test cases of the test set into new test sets of at most chunkSize test cases
+// and returns the ids of the new test sets. Every new test set gets the mocks recorded during its test cases,
+// while the mocks shared across the test cases (config mocks and mocks recorded outside of any test case)
+// are duplicated into each of them. The original test set is deleted once it is split.
+func (r *Replayer) SplitTestSet(ctx context.Context, setID string, chunkSize int) ([]string, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size should be greater than 0, got %d", chunkSize)
+	}
+
+	testCases, err := r.testDB.GetTestCases(ctx, setID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test cases: %w", err)
+	}
+	if len(testCases) <= chunkSize {
+		r.logger.Info("test set is already within the chunk size, nothing to split", zap.String("test-set", setID), zap.Int("test cases", len(testCases)))
+		return []string{setID}, nil
+	}
+
+	mocks, err := r.getAllMocks(ctx, setID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mocks: %w", err)
+	}
+
+	testSetIDs, err := r.testDB.GetAllTestSetIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all test set ids: %w", err)
+	}
+
+	// test set config (pre/post scripts, templates) is copied to every new test set, if present
+	conf, err := r.testSetConf.Read(ctx, setID)
+	if err != nil {
+		r.logger.Debug("no test set config found to copy", zap.String("test-set", setID), zap.Error(err))
+		conf = nil
+	}
+
+	var chunks [][]*models.TestCase
+	for start := 0; start < len(testCases); start += chunkSize {
+		end := start + chunkSize
+		if end > len(testCases) {
+			end = len(testCases)
+		}
+		chunks = append(chunks, testCases[start:end])
+	}
+
+	// mocks recorded during the test cases of a chunk belong to that chunk, the rest are shared
+	chunkMocks := make([][]*models.Mock, len(chunks))
+	var sharedMocks []*models.Mock
+	for _, mock := range mocks {
+		owner := -1
+		if mock.Spec.Metadata["type"] != "config" {
+			for i, chunk := range chunks {
+				if mockInWindow(mock, chunk) {
+					owner = i
+					break
+				}
+			}
+		}
+		if owner == -1 {
+			sharedMocks = append(sharedMocks, mock)
+			continue
+		}
+		chunkMocks[owner] = append(chunkMocks[owner], mock)
+	}
+
+	var newIDs []string
+	for i, chunk := range chunks {
+		newID := pkg.NextID(testSetIDs, models.TestSetPattern)
+		testSetIDs = append(testSetIDs, newID)
+
+		for _, tc := range chunk {
+			err = r.testDB.UpdateTestCase(ctx, tc, newID)
+			if err != nil {
+				return newIDs, fmt.Errorf("failed to write test case to %s: %w", newID, err)
+			}
+		}
+
+		setMocks := append(append([]*models.Mock{}, sharedMocks...), chunkMocks[i]...)
+		sort.SliceStable(setMocks, func(i, j int) bool {
+			return setMocks[i].Spec.ReqTimestampMock.Before(setMocks[j].Spec.ReqTimestampMock)
+		})
+		for _, mock := range setMocks {
+			// InsertMock renames the mock, so insert a copy to keep the shared mocks intact for the other sets
+			m := *mock
+			err = r.mockDB.InsertMock(ctx, &m, newID)
+			if err != nil {
+				return newIDs, fmt.Errorf("failed to write mock to %s: %w", newID, err)
+			}
+		}
+
+		if conf != nil {
+			err = r.testSetConf.Write(ctx, newID, conf)
+			if err != nil {
+				return newIDs, fmt.Errorf("failed to write test set config to %s: %w", newID, err)
+			}
+		}
+		newIDs = append(newIDs, newID)
+	}
+
+	err = r.DeleteTestSet(ctx, setID)
+	if err != nil {
+		utils.LogError(r.logger, err, "failed to delete the split test set", zap.String("test-set", setID))
+		return newIDs, fmt.Errorf("failed to delete the split test set %s: %w", setID, err)
+	}
+
+	r.logger.Info("split test set", zap.String("test-set", setID), zap.Strings("new test sets", newIDs))
+	return newIDs, nil
+}
+
+// mockInWindow checks whether the mock was recorded between the first request and the last response of the test cases.
+func mockInWindow(mock *models.Mock, testCases []*models.TestCase) bool {
+	if len(testCases) == 0 || mock.Spec.ReqTimestampMock.IsZero() {
+		return false
+	}
+	start := testCases[0].HTTPReq.Timestamp
+	end := testCases[len(testCases)-1].HTTPResp.Timestamp
+	return !mock.Spec.ReqTimestampMock.Before(start) && !mock.Spec.ReqTimestampMock.After(end)
+}
+
+// getAllMocks returns every mock of the test set irrespective of the time window.
+func (r *Replayer) getAllMocks(ctx context.Context, testSetID string) ([]*models.Mock, error) {
+	filtered, err := r.mockDB.GetFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	unfiltered, err := r.mockDB.GetUnFilteredMocks(ctx, testSetID, time.Time{}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return append(filtered, unfiltered...), nil
+}
+
+// ExtractFailures copies the failed test cases of the test run across all the test sets into the new test set,
+// along with the mocks recorded during them and the mocks shared across the test cases of their test set
+// (config mocks and mocks recorded outside of any test case), so that the failures can be reproduced in isolation.
+// The source test sets are left untouched.
+func (r *Replayer) ExtractFailures(ctx context.Context, testRunID, newSetID string) error {
+	testSetIDs, err := r.testDB.GetAllTestSetIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get all test set ids: %w", err)
+	}
+	for _, id := range testSetIDs {
+		if id == newSetID {
+			return fmt.Errorf("test set %s already exists", newSetID)
+		}
+	}
+
+	names := map[string]bool{}
+	var mocks []*models.Mock
+	var sources []string
+	extracted := 0
+	for _, testSetID := range testSetIDs {
+		report, err := r.reportDB.GetReport(ctx, testRunID, testSetID)
+		if err != nil {
+			r.logger.Debug("no report found for the test set in the test run", zap.String("test-set", testSetID), zap.String("test-run", testRunID), zap.Error(err))
+			continue
+		}
+		failed := map[string]bool{}
+		for _, result := range report.Tests {
+			if result.Status == models.TestStatusFailed {
+				failed[result.TestCaseID] = true
+			}
+		}
+		if len(failed) == 0 {
+			continue
+		}
+
+		testCases, err := r.testDB.GetTestCases(ctx, testSetID)
+		if err != nil {
+			return fmt.Errorf("failed to get test cases of %s: %w", testSetID, err)
+		}
+		var failedCases []*models.TestCase
+		for _, tc := range testCases {
+			if failed[tc.Name] {
+				failedCases = append(failedCases, tc)
+			}
+		}
+		if len(failedCases) == 0 {
+			r.logger.Warn("failed test cases of the report are no longer in the test set", zap.String("test-set", testSetID))
+			continue
+		}
+
+		setMocks, err := r.getAllMocks(ctx, testSetID)
+		if err != nil {
+			return fmt.Errorf("failed to get mocks of %s: %w", testSetID, err)
+		}
+		for _, mock := range setMocks {
+			if mock.Spec.Metadata["type"] == "config" {
+				mocks = append(mocks, mock)
+				continue
+			}
+			owned, ownedByFailure := false, false
+			for _, tc := range testCases {
+				if mockInWindow(mock, []*models.TestCase{tc}) {
+					owned = true
+					ownedByFailure = failed[tc.Name]
+					break
+				}
+			}
+			if !owned || ownedByFailure {
+				mocks = append(mocks, mock)
+			}
+		}
+
+		for _, tc := range failedCases {
+			if names[tc.Name] {
+				r.logger.Info("renaming the test case as it already exists in the new test set", zap.String("test-case", tc.Name), zap.String("source", testSetID), zap.String("target", newSetID))
+				// an empty name lets the db pick the next available test case name, which it sets on the test case
+				tc.Name = ""
+			}
+			err = r.testDB.UpdateTestCase(ctx, tc, newSetID)
+			if err != nil {
+				return fmt.Errorf("failed to copy test case of %s: %w", testSetID, err)
+			}
+			names[tc.Name] = true
+			extracted++
+		}
+		sources = append(sources, testSetID)
+	}
+
+	if extracted == 0 {
+		r.logger.Info("no failed test cases found in the test run", zap.String("test-run", testRunID))
+		return nil
+	}
+
+	sort.SliceStable(mocks, func(i, j int) bool {
+		return mocks[i].Spec.ReqTimestampMock.Before(mocks[j].Spec.ReqTimestampMock)
+	})
+	for _, mock := range mocks {
+		// InsertMock renames the mock, so insert a copy to keep the source mocks intact
+		m := *mock
+		err = r.mockDB.InsertMock(ctx, &m, newSetID)
+		if err != nil {
+			return fmt.Errorf("failed to copy mock to %s: %w", newSetID, err)
+		}
+	}
+
+	// the pre/post scripts and templates are only carried over when all the failures come from a single test set
+	if len(sources) == 1 {
+		conf, err := r.testSetConf.Read(ctx, sources[0])
+		if err == nil && conf != nil {
+			err = r.testSetConf.Write(ctx, newSetID, conf)
+			if err != nil {
+				return fmt.Errorf("failed to write test set config to %s: %w", newSetID, err)
+			}
+		}
+	}
+
+	r.logger.Info("extracted the failed test cases", zap.String("test-run", testRunID), zap.String("new test set", newSetID), zap.Int("test cases", extracted), zap.Strings("sources", sources))
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// requestTimeout returns the timeout in seconds of the request of the test case, its own timeout overrides the default one.
+func requestTimeout(tc *models.TestCase, defaultTimeout uint64) uint64 {
+	if tc.APITimeout > 0 {
+		return tc.APITimeout
+	}
+	return defaultTimeout
+}
+
+// isTimeout reports whether the request failed as it exceeded its timeout.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr interface{ Timeout() bool }
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// timeoutResult builds the failed result of the test case whose request timed out, it has no actual response.
+func (r *Replayer) timeoutResult(testSetID string, testCase *models.TestCase, started time.Time) *models.TestResult {
+	timeout := requestTimeout(testCase, r.config.Test.APITimeout)
+	r.logger.Warn("the request of the test case timed out", zap.String("testcase", testCase.Name), zap.String("test-set", testSetID), zap.Uint64("timeout (s)", timeout))
+	return r.failedResult(testSetID, testCase, started, fmt.Sprintf("the request timed out after %ds", timeout))
+}
+
+// failedResult builds the failed result of the test case which has no actual response, with the reason of the failure.
+func (r *Replayer) failedResult(testSetID string, testCase *models.TestCase, started time.Time, reason string) *models.TestResult {
+	result := r.newTestResult(testSetID, testCase, &models.HTTPResp{}, models.TestStatusFailed, &models.Result{
+		StatusCode: models.IntResult{Expected: testCase.HTTPResp.StatusCode},
+	}, started)
+	result.FailureReason = reason
+	result.LatencyMs = time.Since(started).Milliseconds()
+	return result
+}
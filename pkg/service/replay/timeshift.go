@@ -0,0 +1,79 @@
+//go:build linux
+
+package replay
+
+import (
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+// shiftTestCases moves the timestamps of the test cases so that the first one is recorded now and keeps the shift
+// of the test set to move its mocks alike. The mock time windows derived from the test cases then hold regardless of
+// how long ago the test set was recorded.
+func (r *Replayer) shiftTestCases(testSetID string, testCases []*models.TestCase) {
+	var first time.Time
+	for _, tc := range testCases {
+		if tc.HTTPReq.Timestamp.IsZero() {
+			continue
+		}
+		if first.IsZero() || tc.HTTPReq.Timestamp.Before(first) {
+			first = tc.HTTPReq.Timestamp
+		}
+	}
+	if first.IsZero() {
+		return
+	}
+
+	shift := time.Since(first)
+	for _, tc := range testCases {
+		if !tc.HTTPReq.Timestamp.IsZero() {
+			tc.HTTPReq.Timestamp = tc.HTTPReq.Timestamp.Add(shift)
+		}
+		if !tc.HTTPResp.Timestamp.IsZero() {
+			tc.HTTPResp.Timestamp = tc.HTTPResp.Timestamp.Add(shift)
+		}
+	}
+
+	r.mu.Lock()
+	if r.timeShifts == nil {
+		r.timeShifts = make(map[string]time.Duration)
+	}
+	r.timeShifts[testSetID] = shift
+	r.mu.Unlock()
+	r.logger.Debug("shifted the timestamps of the test set", zap.String("test-set", testSetID), zap.Duration("shift", shift))
+}
+
+// timeShift returns the shift of the timestamps of the test set, zero if the test set is not shifted.
+func (r *Replayer) timeShift(testSetID string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.timeShifts[testSetID]
+}
+
+// shiftMocks moves the timestamps of the mocks by the shift.
+func shiftMocks(mocks []*models.Mock, shift time.Duration) {
+	for _, mock := range mocks {
+		if !mock.Spec.ReqTimestampMock.IsZero() {
+			mock.Spec.ReqTimestampMock = mock.Spec.ReqTimestampMock.Add(shift)
+		}
+		if !mock.Spec.ResTimestampMock.IsZero() {
+			mock.Spec.ResTimestampMock = mock.Spec.ResTimestampMock.Add(shift)
+		}
+	}
+}
+
+// unshiftTestCase moves the timestamps of the test case back to the recorded time before it is persisted.
+func (r *Replayer) unshiftTestCase(testSetID string, tc *models.TestCase) {
+	shift := r.timeShift(testSetID)
+	if shift == 0 {
+		return
+	}
+	if !tc.HTTPReq.Timestamp.IsZero() {
+		tc.HTTPReq.Timestamp = tc.HTTPReq.Timestamp.Add(-shift)
+	}
+	if !tc.HTTPResp.Timestamp.IsZero() {
+		tc.HTTPResp.Timestamp = tc.HTTPResp.Timestamp.Add(-shift)
+	}
+}
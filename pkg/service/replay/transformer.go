@@ -0,0 +1,186 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// AddRequestTransformer appends the transformer to the ones the http requests of the test cases are run through,
+// in the order they are added.
+func (r *Replayer) AddRequestTransformer(t RequestTransformer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requestTransformers = append(r.requestTransformers, t)
+}
+
+// transformRequest runs the http request of the test case through the request transformers. The transformers get
+// a copy of the request, the recorded headers are left as is to be persisted when the response is recorded.
+func (r *Replayer) transformRequest(ctx context.Context, tc *models.TestCase) error {
+	if tc.Kind != models.HTTP {
+		return nil
+	}
+	r.mu.Lock()
+	transformers := r.requestTransformers
+	r.mu.Unlock()
+	if len(transformers) == 0 {
+		return nil
+	}
+
+	req := tc.HTTPReq
+	req.Header = make(map[string]string, len(tc.HTTPReq.Header))
+	for key, value := range tc.HTTPReq.Header {
+		req.Header[key] = value
+	}
+	out := &req
+	for _, t := range transformers {
+		transformed, err := t.Transform(ctx, out)
+		if err != nil {
+			return fmt.Errorf("failed to transform the request of the test case %s: %w", tc.Name, err)
+		}
+		if transformed != nil {
+			out = transformed
+		}
+	}
+	tc.HTTPReq = *out
+	return nil
+}
+
+// setHeader sets the header of the request, replacing the header of the same name whatever its case.
+func setHeader(req *models.HTTPReq, name, value string) {
+	for key := range req.Header {
+		if strings.EqualFold(key, name) {
+			delete(req.Header, key)
+		}
+	}
+	if req.Header == nil {
+		req.Header = map[string]string{}
+	}
+	req.Header[name] = value
+}
+
+// HeaderOverrideTransformer replaces the header of the requests with the value of the environment variable.
+type HeaderOverrideTransformer struct {
+	Header string
+	EnvVar string
+}
+
+func NewHeaderOverrideTransformer(header, envVar string) *HeaderOverrideTransformer {
+	return &HeaderOverrideTransformer{Header: header, EnvVar: envVar}
+}
+
+func (t *HeaderOverrideTransformer) Transform(_ context.Context, req *models.HTTPReq) (*models.HTTPReq, error) {
+	value, ok := os.LookupEnv(t.EnvVar)
+	if !ok {
+		return nil, fmt.Errorf("the environment variable %s of the %s header is not set", t.EnvVar, t.Header)
+	}
+	setHeader(req, t.Header, value)
+	return req, nil
+}
+
+// BearerTokenRefreshTransformer fetches a token from the token endpoint and sends it as the bearer token of the
+// Authorization header of the requests. The token is reused until the expires_in seconds of the token response,
+// or the TTL when the response has none, have elapsed. It is fetched for every request when neither is known.
+type BearerTokenRefreshTransformer struct {
+	TokenURL string
+	// Method of the token request, POST when empty
+	Method string
+	Body   string
+	Header map[string]string
+	// TokenField is the JSONPath of the token in the token response, $.access_token when empty
+	TokenField string
+	TTL        time.Duration
+	Client     *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewBearerTokenRefreshTransformer(tokenURL string, apiTimeout uint64) *BearerTokenRefreshTransformer {
+	return &BearerTokenRefreshTransformer{
+		TokenURL: tokenURL,
+		Client:   &http.Client{Timeout: time.Duration(apiTimeout) * time.Second},
+	}
+}
+
+func (t *BearerTokenRefreshTransformer) Transform(ctx context.Context, req *models.HTTPReq) (*models.HTTPReq, error) {
+	token, err := t.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	setHeader(req, "Authorization", "Bearer "+token)
+	return req, nil
+}
+
+// getToken returns the cached token while it is valid, the token is fetched once at a time for the parallel
+// test sets.
+func (t *BearerTokenRefreshTransformer) getToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && time.Now().Before(t.expiresAt) {
+		return t.token, nil
+	}
+
+	method := t.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.TokenURL, strings.NewReader(t.Body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create the token request: %w", err)
+	}
+	for key, value := range t.Header {
+		req.Header.Set(key, value)
+	}
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request the token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("unexpected status code %d of the token endpoint", resp.StatusCode)
+	}
+
+	field := t.TokenField
+	if field == "" {
+		field = "$.access_token"
+	}
+	token, err := jsonPathValue(string(body), field)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the token at %s of the token response: %w", field, err)
+	}
+	if token == "" {
+		return "", fmt.Errorf("the token at %s of the token response is empty", field)
+	}
+
+	ttl := t.TTL
+	var expiry struct {
+		ExpiresIn float64 `json:"expires_in"`
+	}
+	if json.Unmarshal(body, &expiry) == nil && expiry.ExpiresIn > 0 {
+		ttl = time.Duration(expiry.ExpiresIn * float64(time.Second))
+	}
+	t.token = token
+	// zero ttl expires the token at once, so that it is fetched for every request
+	t.expiresAt = time.Now().Add(ttl)
+	return token, nil
+}
@@ -4,8 +4,11 @@ package replay
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"go.keploy.io/server/v2/config"
 	"go.keploy.io/server/v2/pkg"
@@ -13,31 +16,25 @@ import (
 	"go.uber.org/zap"
 )
 
-type TestReportVerdict struct {
-	total  int
-	passed int
-	failed int ``
-	status bool
-}
-
+// LeftJoinNoise merges the test set noise into a copy of the global noise, the test set noise
+// takes precedence for the fields present in both. The global noise is left untouched.
 func LeftJoinNoise(globalNoise config.GlobalNoise, tsNoise config.GlobalNoise) config.GlobalNoise {
-	noise := globalNoise
-
-	if _, ok := noise["body"]; !ok {
-		noise["body"] = make(map[string][]string)
-	}
-	if tsNoiseBody, ok := tsNoise["body"]; ok {
-		for field, regexArr := range tsNoiseBody {
-			noise["body"][field] = regexArr
+	noise := config.GlobalNoise{"body": {}, "header": {}}
+	for key, fields := range globalNoise {
+		if _, ok := noise[key]; !ok {
+			noise[key] = make(map[string][]string)
+		}
+		for field, regexArr := range fields {
+			noise[key][field] = regexArr
 		}
 	}
 
-	if _, ok := noise["header"]; !ok {
-		noise["header"] = make(map[string][]string)
-	}
-	if tsNoiseHeader, ok := tsNoise["header"]; ok {
-		for field, regexArr := range tsNoiseHeader {
-			noise["header"][field] = regexArr
+	for key, fields := range tsNoise {
+		if _, ok := noise[key]; !ok {
+			noise[key] = make(map[string][]string)
+		}
+		for field, regexArr := range fields {
+			noise[key][field] = regexArr
 		}
 	}
 
@@ -82,28 +79,112 @@ type requestMockUtil struct {
 	mockName   string
 	apiTimeout uint64
 	basePath   string
+	serializer BodySerializer
+	signing    *config.RequestSigningConfig
+	maxBodyKB  int
+	// headers are added to every request, replacing the headers of the test case when overrideHeaders is set
+	headers         map[string]string
+	overrideHeaders bool
+	// tlsConfig presents the client certificate and verifies the application against the CA of the test config
+	tlsConfig *tls.Config
 }
 
-func NewRequestMockUtil(logger *zap.Logger, path, mockName string, apiTimeout uint64, basePath string) RequestMockHandler {
-	return &requestMockUtil{
+func NewRequestMockUtil(logger *zap.Logger, path, mockName string, apiTimeout uint64, basePath string, opts ...RequestMockUtilOption) RequestMockHandler {
+	t := &requestMockUtil{
 		path:       path,
 		logger:     logger,
 		mockName:   mockName,
 		apiTimeout: apiTimeout,
 		basePath:   basePath,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 func (t *requestMockUtil) SimulateRequest(ctx context.Context, _ uint64, tc *models.TestCase, testSetID string) (*models.HTTPResp, error) {
 	switch tc.Kind {
 	case models.HTTP:
 		t.logger.Debug("Before simulating the request", zap.Any("Test case", tc))
-		resp, err := pkg.SimulateHTTP(ctx, *tc, testSetID, t.logger, t.apiTimeout)
+		testCase := *tc
+		if t.serializer != nil {
+			err := t.serializeRequest(&testCase)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(t.headers) > 0 {
+			testCase.HTTPReq.Header = mergeHeaders(testCase.HTTPReq.Header, t.headers, t.overrideHeaders)
+		}
+		if t.signing != nil {
+			err := signRequest(&testCase.HTTPReq, t.signing)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign the request: %w", err)
+			}
+		}
+		resp, err := pkg.SimulateHTTP(ctx, testCase, testSetID, t.logger, requestTimeout(tc, t.apiTimeout), t.maxBodyKB, t.tlsConfig)
 		t.logger.Debug("After simulating the request", zap.Any("test case id", tc.Name))
+		if err == nil && t.serializer != nil {
+			err = t.deserializeResponse(resp)
+		}
 		return resp, err
 	}
 	return nil, nil
 }
 
+// serializeRequest encodes the json body of the test case request with the body serializer.
+func (t *requestMockUtil) serializeRequest(tc *models.TestCase) error {
+	if tc.HTTPReq.Body == "" || !json.Valid([]byte(tc.HTTPReq.Body)) {
+		return nil
+	}
+	var body interface{}
+	err := json.Unmarshal([]byte(tc.HTTPReq.Body), &body)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal the request body: %w", err)
+	}
+	data, err := t.serializer.Serialize(body)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the request body: %w", err)
+	}
+	tc.HTTPReq.Body = string(data)
+
+	header := make(map[string]string, len(tc.HTTPReq.Header))
+	for key, value := range tc.HTTPReq.Header {
+		if strings.EqualFold(key, "Content-Type") || strings.EqualFold(key, "Content-Length") {
+			continue
+		}
+		header[key] = value
+	}
+	header["Content-Type"] = t.serializer.ContentType()
+	tc.HTTPReq.Header = header
+	return nil
+}
+
+// deserializeResponse decodes the response body to json, if it is encoded in the format of the body serializer,
+// so that it is compared with the recorded json body.
+func (t *requestMockUtil) deserializeResponse(resp *models.HTTPResp) error {
+	contentType := ""
+	for key, value := range resp.Header {
+		if strings.EqualFold(key, "Content-Type") {
+			contentType = value
+		}
+	}
+	if resp.Body == "" || !strings.HasPrefix(contentType, t.serializer.ContentType()) {
+		return nil
+	}
+	var body interface{}
+	err := t.serializer.Deserialize([]byte(resp.Body), &body)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize the response body: %w", err)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the response body: %w", err)
+	}
+	resp.Body = string(data)
+	return nil
+}
+
 func (t *requestMockUtil) AfterTestHook(_ context.Context, testRunID, testSetID string, tsCnt int) (*models.TestReport, error) {
 	t.logger.Debug("AfterTestHook", zap.Any("testRunID", testRunID), zap.Any("testSetID", testSetID), zap.Any("totalTestSetCount", tsCnt))
 	return nil, nil
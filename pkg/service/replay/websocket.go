@@ -0,0 +1,215 @@
+//go:build linux
+
+package replay
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/utils"
+	"go.uber.org/zap"
+)
+
+// WSMockHandler simulates the websocket test cases, the rest of the hooks are delegated to the http request emulator.
+type WSMockHandler struct {
+	RequestMockHandler
+	logger     *zap.Logger
+	apiTimeout uint64
+}
+
+// NewWSMockHandler returns the request mock handler of the websocket test cases.
+func NewWSMockHandler(logger *zap.Logger, apiTimeout uint64, handler RequestMockHandler) RequestMockHandler {
+	return &WSMockHandler{
+		RequestMockHandler: handler,
+		logger:             logger,
+		apiTimeout:         apiTimeout,
+	}
+}
+
+// wsFrameResult is a frame of the websocket conversation in the body of the wrapped http response.
+// The payload of the json frames is kept as json, so that the noise can be applied to its fields (e.g. body.payload.ts).
+type wsFrameResult struct {
+	Opcode  int         `json:"opcode"`
+	Payload interface{} `json:"payload"`
+}
+
+// handshake headers are set by the dialer, passing them again fails the handshake
+var wsHandshakeHeaders = map[string]bool{
+	"upgrade":                  true,
+	"connection":               true,
+	"sec-websocket-key":        true,
+	"sec-websocket-version":    true,
+	"sec-websocket-extensions": true,
+	"content-length":           true,
+}
+
+// SimulateRequest connects to the application, replays the client frames in their recorded order and collects
+// the server frames. The server frames are wrapped in a http response with the handshake status code and headers,
+// and the frames as a json array body.
+func (w *WSMockHandler) SimulateRequest(ctx context.Context, _ uint64, tc *models.TestCase, _ string) (*models.HTTPResp, error) {
+	if tc.Kind != models.WS {
+		return nil, fmt.Errorf("websocket handler can't simulate the test case of kind %s", tc.Kind)
+	}
+	timeout := time.Duration(w.apiTimeout) * time.Second
+
+	header := http.Header{}
+	for key, value := range tc.WSReq.Header {
+		if wsHandshakeHeaders[strings.ToLower(key)] {
+			continue
+		}
+		header.Set(key, value)
+	}
+	dialer := websocket.Dialer{HandshakeTimeout: timeout}
+	w.logger.Debug("Before simulating the websocket request", zap.Any("Test case", tc))
+	conn, handshake, err := dialer.DialContext(ctx, tc.WSReq.URL, header)
+	if err != nil {
+		if handshake != nil {
+			// the application refused the upgrade, its response is compared with the recorded handshake
+			return &models.HTTPResp{StatusCode: handshake.StatusCode, Header: flattenHeader(handshake.Header), Body: "[]"}, nil
+		}
+		return nil, fmt.Errorf("failed to connect to the websocket: %w", err)
+	}
+	defer func() {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		if err := conn.Close(); err != nil {
+			w.logger.Debug("failed to close the websocket connection", zap.Error(err))
+		}
+	}()
+
+	var frames []wsFrameResult
+	for _, frame := range recordedConversation(tc) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if frame.Direction == models.WSDirectionClient {
+			payload := []byte(frame.Payload)
+			if frame.Opcode == websocket.BinaryMessage {
+				payload, err = base64.StdEncoding.DecodeString(frame.Payload)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode the binary frame: %w", err)
+				}
+			}
+			if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+				return nil, err
+			}
+			if err := conn.WriteMessage(frame.Opcode, payload); err != nil {
+				return nil, fmt.Errorf("failed to send the frame: %w", err)
+			}
+			continue
+		}
+
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+		opcode, payload, err := conn.ReadMessage()
+		if err != nil {
+			var netErr interface{ Timeout() bool }
+			if (errors.As(err, &netErr) && netErr.Timeout()) || websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				// fewer frames than recorded, the missing frames fail the comparison
+				w.logger.Debug("stopped reading the websocket frames", zap.String("testcase", tc.Name), zap.Error(err))
+				break
+			}
+			return nil, fmt.Errorf("failed to read the frame: %w", err)
+		}
+		frames = append(frames, wsFrame(opcode, payload))
+	}
+	w.logger.Debug("After simulating the websocket request", zap.Any("test case id", tc.Name))
+
+	body, err := json.Marshal(frames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the websocket frames: %w", err)
+	}
+	return &models.HTTPResp{StatusCode: handshake.StatusCode, Header: flattenHeader(handshake.Header), Body: string(body)}, nil
+}
+
+// recordedConversation merges the client and the server frames in the order they were recorded.
+func recordedConversation(tc *models.TestCase) []models.WSFrame {
+	frames := make([]models.WSFrame, 0, len(tc.WSReq.Frames)+len(tc.WSResp.Frames))
+	for _, frame := range tc.WSReq.Frames {
+		frame.Direction = models.WSDirectionClient
+		frames = append(frames, frame)
+	}
+	for _, frame := range tc.WSResp.Frames {
+		frame.Direction = models.WSDirectionServer
+		frames = append(frames, frame)
+	}
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Timestamp.Before(frames[j].Timestamp)
+	})
+	return frames
+}
+
+func wsFrame(opcode int, payload []byte) wsFrameResult {
+	if opcode == websocket.BinaryMessage {
+		return wsFrameResult{Opcode: opcode, Payload: base64.StdEncoding.EncodeToString(payload)}
+	}
+	var v interface{}
+	if json.Valid(payload) && json.Unmarshal(payload, &v) == nil {
+		return wsFrameResult{Opcode: opcode, Payload: v}
+	}
+	return wsFrameResult{Opcode: opcode, Payload: string(payload)}
+}
+
+// wsToHTTPResp wraps the recorded websocket response in a http response so that it is compared like the simulated one.
+func wsToHTTPResp(resp models.WSResp) models.HTTPResp {
+	frames := []wsFrameResult{}
+	for _, frame := range resp.Frames {
+		payload := []byte(frame.Payload)
+		if frame.Opcode == websocket.BinaryMessage {
+			// keep the binary payload base64 encoded, as in the simulated response
+			frames = append(frames, wsFrameResult{Opcode: frame.Opcode, Payload: frame.Payload})
+			continue
+		}
+		frames = append(frames, wsFrame(frame.Opcode, payload))
+	}
+	body, _ := json.Marshal(frames)
+	return models.HTTPResp{StatusCode: resp.StatusCode, Header: resp.Header, Body: string(body)}
+}
+
+func flattenHeader(h http.Header) map[string]string {
+	header := make(map[string]string, len(h))
+	for key, values := range h {
+		header[key] = strings.Join(values, ", ")
+	}
+	return header
+}
+
+// rewriteWSURL replaces the host of the recorded websocket url with the host of the base path,
+// or with the ip of the application container in the docker environment.
+func (r *Replayer) rewriteWSURL(tc *models.TestCase, userIP string) error {
+	if r.config.Test.BasePath != "" {
+		base, err := url.Parse(r.config.Test.BasePath)
+		if err != nil {
+			return fmt.Errorf("failed to parse the base path: %w", err)
+		}
+		u, err := url.Parse(tc.WSReq.URL)
+		if err != nil {
+			return fmt.Errorf("failed to parse the websocket url: %w", err)
+		}
+		u.Host = base.Host
+		if base.Scheme == "https" {
+			u.Scheme = "wss"
+		}
+		tc.WSReq.URL = u.String()
+		return nil
+	}
+	if !utils.IsDockerKind(utils.CmdType(r.config.CommandType)) {
+		return nil
+	}
+	replaced, err := utils.ReplaceHostToIP(tc.WSReq.URL, userIP)
+	if err != nil {
+		return err
+	}
+	tc.WSReq.URL = replaced
+	return nil
+}
@@ -0,0 +1,172 @@
+//go:build linux
+
+package replay
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// xmlElement is an element of a parsed xml body, its names are resolved to their namespace uri.
+type xmlElement struct {
+	name     xml.Name
+	attrs    map[xml.Name]string
+	text     string
+	children []*xmlElement
+}
+
+// isXMLContentType reports whether the content type of the headers is xml, e.g. application/xml, text/xml
+// or application/soap+xml.
+func isXMLContentType(header map[string]string) bool {
+	for key, value := range header {
+		if !strings.EqualFold(key, "Content-Type") {
+			continue
+		}
+		mediaType, _, err := mime.ParseMediaType(value)
+		if err != nil {
+			return false
+		}
+		return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+	}
+	return false
+}
+
+// parseXML parses the xml body into its root element. The prefixes of the element and the attribute names are
+// resolved to their namespace uri, so that the same namespace bound to different prefixes compares equal.
+func parseXML(body string) (*xmlElement, error) {
+	dec := xml.NewDecoder(strings.NewReader(body))
+	var root *xmlElement
+	var stack []*xmlElement
+	var text []*bytes.Buffer
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the xml body: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &xmlElement{name: t.Name, attrs: map[xml.Name]string{}}
+			for _, attr := range t.Attr {
+				// the namespace declarations are compared through the resolved names
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue
+				}
+				element.attrs[attr.Name] = attr.Value
+			}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, errors.New("the xml body has several root elements")
+				}
+				root = element
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+			}
+			stack = append(stack, element)
+			text = append(text, &bytes.Buffer{})
+		case xml.CharData:
+			if len(text) > 0 {
+				text[len(text)-1].Write(t)
+			}
+		case xml.EndElement:
+			stack[len(stack)-1].text = strings.TrimSpace(text[len(text)-1].String())
+			stack = stack[:len(stack)-1]
+			text = text[:len(text)-1]
+		}
+	}
+	if root == nil {
+		return nil, errors.New("the xml body has no root element")
+	}
+	return root, nil
+}
+
+// XMLEqual compares the xml bodies structurally, their element names, attributes and texts. The noise keys are the
+// lower cased dot separated paths of the local names of the elements from the root, e.g. envelope.body.token, and
+// @name for an attribute of an element, e.g. envelope.body.token.@expiry. The order of the sibling elements is
+// ignored when ignoreOrdering is set.
+func XMLEqual(expected, actual string, noise map[string][]string, ignoreOrdering bool) (bool, error) {
+	exp, err := parseXML(expected)
+	if err != nil {
+		return false, err
+	}
+	act, err := parseXML(actual)
+	if err != nil {
+		return false, err
+	}
+	return xmlElementsEqual(strings.ToLower(exp.name.Local), exp, act, noise, ignoreOrdering), nil
+}
+
+func xmlElementsEqual(path string, exp, act *xmlElement, noise map[string][]string, ignoreOrdering bool) bool {
+	if exp.name != act.name {
+		return false
+	}
+	if xmlNoisy(path, exp.text, noise) {
+		return true
+	}
+
+	for name, value := range exp.attrs {
+		attrPath := path + ".@" + strings.ToLower(name.Local)
+		actValue, ok := act.attrs[name]
+		if xmlNoisy(attrPath, value, noise) {
+			continue
+		}
+		if !ok || actValue != value {
+			return false
+		}
+	}
+	for name, value := range act.attrs {
+		if _, ok := exp.attrs[name]; !ok && !xmlNoisy(path+".@"+strings.ToLower(name.Local), value, noise) {
+			return false
+		}
+	}
+
+	if exp.text != act.text || len(exp.children) != len(act.children) {
+		return false
+	}
+	childPath := func(child *xmlElement) string {
+		return path + "." + strings.ToLower(child.name.Local)
+	}
+	if !ignoreOrdering {
+		for i := range exp.children {
+			if !xmlElementsEqual(childPath(exp.children[i]), exp.children[i], act.children[i], noise, ignoreOrdering) {
+				return false
+			}
+		}
+		return true
+	}
+	// each expected element matches a distinct actual sibling, wherever it is
+	used := make([]bool, len(act.children))
+	for _, expChild := range exp.children {
+		matched := false
+		for j, actChild := range act.children {
+			if used[j] || !xmlElementsEqual(childPath(expChild), expChild, actChild, noise, ignoreOrdering) {
+				continue
+			}
+			used[j] = true
+			matched = true
+			break
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// xmlNoisy reports whether the element or the attribute at the path is noisy, a noise key with regular
+// expressions makes it noisy only when its expected value matches one of them.
+func xmlNoisy(path, value string, noise map[string][]string) bool {
+	regexArr, isNoisy := CheckStringExist(path, noise)
+	if isNoisy && len(regexArr) != 0 {
+		isNoisy, _ = MatchesAnyRegex(value, regexArr)
+	}
+	return isNoisy
+}
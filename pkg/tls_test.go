@@ -0,0 +1,251 @@
+package pkg
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// testCA is a self-signed CA issuing the certificates of the in-process tls server and of its clients.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "keploy test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM certificate and the PKCS#8 DER private key of a leaf certificate signed by the CA.
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyDER
+}
+
+// encryptPKCS8 encrypts the PKCS#8 private key with PBES2, PBKDF2 with HMAC-SHA256 and AES-256-CBC, the scheme of
+// openssl 3.
+func encryptPKCS8(t *testing.T, keyDER []byte, passphrase string) []byte {
+	t.Helper()
+	salt := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rand.Read(iv); err != nil {
+		t.Fatal(err)
+	}
+	const iterations = 2048
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, iterations, 32, sha256.New))
+	if err != nil {
+		t.Fatal(err)
+	}
+	padding := aes.BlockSize - len(keyDER)%aes.BlockSize
+	plain := append(append([]byte{}, keyDER...), make([]byte, padding)...)
+	for i := len(keyDER); i < len(plain); i++ {
+		plain[i] = byte(padding)
+	}
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, plain)
+
+	marshal := func(v interface{}) asn1.RawValue {
+		data, err := asn1.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return asn1.RawValue{FullBytes: data}
+	}
+	der, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm: oidPBES2,
+			Parameters: marshal(pbes2Params{
+				KeyDerivationFunc: pkix.AlgorithmIdentifier{
+					Algorithm: oidPBKDF2,
+					Parameters: marshal(pbkdf2Params{
+						Salt:           salt,
+						IterationCount: iterations,
+						PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+					}),
+				},
+				EncryptionScheme: pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: marshal(iv)},
+			}),
+		},
+		EncryptedData: encrypted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der})
+}
+
+func TestClientTLSConfigWithMutualTLSServer(t *testing.T) {
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	serverCertPEM, serverKeyDER := ca.issue(t, 2, x509.ExtKeyUsageServerAuth)
+	clientCertPEM, clientKeyDER := ca.issue(t, 3, x509.ExtKeyUsageClientAuth)
+	clientKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: clientKeyDER})
+
+	// the legacy encrypted PEM keys are still produced by openssl 1.x
+	legacyBlock, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", mustECKey(t, clientKeyDER), []byte("secret"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	caFile := write("ca.pem", ca.pem)
+	otherCAFile := write("other-ca.pem", otherCA.pem)
+	certFile := write("client.pem", clientCertPEM)
+	keyFile := write("client-key.pem", clientKeyPEM)
+	encryptedKeyFile := write("client-key-pkcs8.pem", encryptPKCS8(t, clientKeyDER, "secret"))
+	legacyKeyFile := write("client-key-legacy.pem", pem.EncodeToMemory(legacyBlock))
+
+	serverCert, err := tls.X509KeyPair(serverCertPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: serverKeyDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello " + r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name               string
+		certFile           string
+		keyFile            string
+		passphrase         string
+		caFile             string
+		insecureSkipVerify bool
+		wantConfigErr      bool
+		wantRequestErr     bool
+	}{
+		{name: "client certificate verified against the CA", certFile: certFile, keyFile: keyFile, caFile: caFile},
+		{name: "encrypted PKCS#8 client key", certFile: certFile, keyFile: encryptedKeyFile, passphrase: "secret", caFile: caFile},
+		{name: "legacy encrypted PEM client key", certFile: certFile, keyFile: legacyKeyFile, passphrase: "secret", caFile: caFile},
+		{name: "insecure skip verify without the CA", certFile: certFile, keyFile: keyFile, insecureSkipVerify: true},
+		{name: "no client certificate", caFile: caFile, wantRequestErr: true},
+		{name: "server certificate of another CA", certFile: certFile, keyFile: keyFile, caFile: otherCAFile, wantRequestErr: true},
+		{name: "server certificate not verified against the system CAs", certFile: certFile, keyFile: keyFile, wantRequestErr: true},
+		{name: "wrong passphrase of the PKCS#8 key", certFile: certFile, keyFile: encryptedKeyFile, passphrase: "wrong", caFile: caFile, wantConfigErr: true},
+		{name: "wrong passphrase of the legacy key", certFile: certFile, keyFile: legacyKeyFile, passphrase: "wrong", caFile: caFile, wantConfigErr: true},
+		{name: "missing passphrase", certFile: certFile, keyFile: encryptedKeyFile, caFile: caFile, wantConfigErr: true},
+		{name: "client certificate without the key", certFile: certFile, caFile: caFile, wantConfigErr: true},
+		{name: "CA file without a certificate", caFile: keyFile, wantConfigErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := ClientTLSConfig(tt.certFile, tt.keyFile, tt.passphrase, tt.caFile, tt.insecureSkipVerify)
+			if (err != nil) != tt.wantConfigErr {
+				t.Fatalf("ClientTLSConfig() error = %v, wantErr %v", err, tt.wantConfigErr)
+			}
+			if tt.wantConfigErr {
+				return
+			}
+			tc := models.TestCase{
+				Name:    "test-1",
+				HTTPReq: models.HTTPReq{Method: http.MethodGet, URL: server.URL + "/hello", ProtoMajor: 1, ProtoMinor: 1},
+			}
+			resp, err := SimulateHTTP(context.Background(), tc, "test-set-0", zap.NewNop(), 5, 0, tlsConfig)
+			if (err != nil) != tt.wantRequestErr {
+				t.Fatalf("SimulateHTTP() error = %v, wantErr %v", err, tt.wantRequestErr)
+			}
+			if err == nil && resp.Body != "hello localhost" {
+				t.Fatalf("SimulateHTTP() body = %q, want the common name of the client certificate", resp.Body)
+			}
+		})
+	}
+}
+
+// mustECKey converts the PKCS#8 DER of the ecdsa key to its SEC 1 DER, the format of the legacy PEM keys.
+func mustECKey(t *testing.T, pkcs8 []byte) []byte {
+	t.Helper()
+	key, err := x509.ParsePKCS8PrivateKey(pkcs8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
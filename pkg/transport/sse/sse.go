@@ -0,0 +1,50 @@
+// Package sse serves the progress of the test runs as server-sent events.
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// ProgressEvent is the name of the server-sent events of the test progress.
+const ProgressEvent = "progress"
+
+// Handler streams the progress events received on the channel to the client as json encoded server-sent events,
+// until the client disconnects or the channel is closed. The handler consumes the events of the channel, so
+// a channel should back the handler of a single client, e.g. one subscribed with StreamTestProgress per request.
+func Handler(events <-chan models.TestProgressEvent) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ProgressEvent, data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
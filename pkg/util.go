@@ -5,10 +5,14 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"io/fs"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"strconv"
 	"strings"
@@ -77,16 +81,34 @@ func IsTime(stringDate string) bool {
 	return false
 }
 
-func SimulateHTTP(ctx context.Context, tc models.TestCase, testSet string, logger *zap.Logger, apiTimeout uint64) (*models.HTTPResp, error) {
+// SimulateHTTP sends the request of the test case to the application. The response body is truncated to maxBodyKB
+// kilobytes (unlimited when 0) and the response is marked as truncated, so that huge downloads are not held in memory.
+// The tls config, the default one when nil, is used for the https requests, e.g. to present a client certificate.
+func SimulateHTTP(ctx context.Context, tc models.TestCase, testSet string, logger *zap.Logger, apiTimeout uint64, maxBodyKB int, tlsConfig *tls.Config) (*models.HTTPResp, error) {
 	var resp *models.HTTPResp
 
 	logger.Info("starting test for of", zap.Any("test case", models.HighlightString(tc.Name)), zap.Any("test set", models.HighlightString(testSet)))
-	req, err := http.NewRequestWithContext(ctx, string(tc.HTTPReq.Method), tc.HTTPReq.URL, bytes.NewBufferString(tc.HTTPReq.Body))
+	body := bytes.NewBufferString(tc.HTTPReq.Body)
+	contentType := ""
+	if len(tc.HTTPReq.FormFiles) != 0 || (len(tc.HTTPReq.Form) != 0 && IsMultipartForm(tc.HTTPReq.Header)) {
+		var err error
+		body, contentType, err = MultipartBody(tc.HTTPReq)
+		if err != nil {
+			utils.LogError(logger, err, "failed to create the multipart body from the yaml document")
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, string(tc.HTTPReq.Method), tc.HTTPReq.URL, body)
 	if err != nil {
 		utils.LogError(logger, err, "failed to create a http request from the yaml document")
 		return nil, err
 	}
 	req.Header = ToHTTPHeader(tc.HTTPReq.Header)
+	if contentType != "" {
+		// the recorded boundary is kept when valid, the length of the rebuilt body may differ from the recorded one
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Del("Content-Length")
+	}
 	req.ProtoMajor = tc.HTTPReq.ProtoMajor
 	req.ProtoMinor = tc.HTTPReq.ProtoMinor
 	req.Header.Set("KEPLOY-TEST-ID", tc.Name)
@@ -108,6 +130,7 @@ func SimulateHTTP(ctx context.Context, tc models.TestCase, testSet string, logge
 			},
 			Transport: &http.Transport{
 				DisableCompression: disableCompression,
+				TLSClientConfig:    tlsConfig,
 			},
 		}
 	} else if ok && strings.EqualFold(keepAlive[0], "close") {
@@ -120,6 +143,7 @@ func SimulateHTTP(ctx context.Context, tc models.TestCase, testSet string, logge
 			Transport: &http.Transport{
 				DisableKeepAlives:  true,
 				DisableCompression: disableCompression,
+				TLSClientConfig:    tlsConfig,
 			},
 		}
 	} else {
@@ -133,6 +157,7 @@ func SimulateHTTP(ctx context.Context, tc models.TestCase, testSet string, logge
 				DisableKeepAlives:  false,
 				MaxIdleConns:       1,
 				DisableCompression: disableCompression,
+				TLSClientConfig:    tlsConfig,
 			},
 		}
 	}
@@ -143,21 +168,136 @@ func SimulateHTTP(ctx context.Context, tc models.TestCase, testSet string, logge
 		return nil, errHTTPReq
 	}
 
-	respBody, errReadRespBody := io.ReadAll(httpResp.Body)
+	var bodyReader io.Reader = httpResp.Body
+	limit := int64(maxBodyKB) * 1024
+	if maxBodyKB > 0 {
+		// one more byte tells whether the body exceeds the limit
+		bodyReader = io.LimitReader(httpResp.Body, limit+1)
+	}
+	respBody, errReadRespBody := io.ReadAll(bodyReader)
 	if errReadRespBody != nil {
 		utils.LogError(logger, errReadRespBody, "failed reading response body")
 		return nil, err
 	}
+	truncated := maxBodyKB > 0 && int64(len(respBody)) > limit
+	if truncated {
+		respBody = respBody[:limit]
+		logger.Warn("the response body exceeds the max response body size and is truncated", zap.String("testcase", tc.Name), zap.Int("max size (KB)", maxBodyKB))
+	}
 
 	resp = &models.HTTPResp{
 		StatusCode: httpResp.StatusCode,
 		Body:       string(respBody),
 		Header:     ToYamlHTTPHeader(httpResp.Header),
+		Truncated:  truncated,
 	}
 
 	return resp, errHTTPReq
 }
 
+// MultipartBody builds the multipart/form-data body from the form fields and files of the request
+// and returns it along with its content type containing the boundary. The boundary of the recorded
+// content type is preserved, a random one is used when the request has none.
+func MultipartBody(httpReq models.HTTPReq) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if boundary := multipartBoundary(httpReq.Header); boundary != "" {
+		if err := writer.SetBoundary(boundary); err != nil {
+			return nil, "", fmt.Errorf("invalid multipart boundary %q: %w", boundary, err)
+		}
+	}
+	for _, field := range httpReq.Form {
+		for _, value := range field.Values {
+			if err := writer.WriteField(field.Key, value); err != nil {
+				return nil, "", fmt.Errorf("failed to write the form field %s: %w", field.Key, err)
+			}
+		}
+	}
+	for _, file := range httpReq.FormFiles {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(file.Name), escapeQuotes(file.Filename)))
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create the form file %s: %w", file.Name, err)
+		}
+		if _, err := part.Write(file.Data); err != nil {
+			return nil, "", fmt.Errorf("failed to write the form file %s: %w", file.Name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close the multipart writer: %w", err)
+	}
+	return body, writer.FormDataContentType(), nil
+}
+
+// IsMultipartForm reports whether the content type of the headers is multipart/form-data.
+func IsMultipartForm(header map[string]string) bool {
+	mediaType, _, err := mime.ParseMediaType(headerValue(header, "Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// multipartBoundary returns the boundary of the multipart/form-data content type of the headers.
+func multipartBoundary(header map[string]string) string {
+	if !IsMultipartForm(header) {
+		return ""
+	}
+	_, params, _ := mime.ParseMediaType(headerValue(header, "Content-Type"))
+	return params["boundary"]
+}
+
+// headerValue returns the value of the header, whose name is matched case insensitively.
+func headerValue(header map[string]string, name string) string {
+	for key, value := range header {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// ParseMultipartFiles returns the file parts of the multipart/form-data body.
+func ParseMultipartFiles(body []byte, contentType string) ([]models.FormFile, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the content type: %w", err)
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	var files []models.FormFile
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the multipart body: %w", err)
+		}
+		if part.FileName() == "" {
+			continue
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the form file %s: %w", part.FormName(), err)
+		}
+		files = append(files, models.FormFile{
+			Name:        part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
 func ParseHTTPRequest(requestBytes []byte) (*http.Request, error) {
 	// Parse the request using the http.ReadRequest function
 	request, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(requestBytes)))
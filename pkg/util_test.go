@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.keploy.io/server/v2/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestSimulateHTTPRoundTripsTheMultipartForm(t *testing.T) {
+	// the binary file has the bytes which are not valid utf-8 and a line looking like a boundary
+	binary := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00, 0xff, 0xfe, '\r', '\n', '-', '-', 'x', '\r', '\n'}
+
+	tests := []struct {
+		name         string
+		contentType  string
+		wantBoundary string
+	}{
+		{name: "recorded boundary", contentType: "multipart/form-data; boundary=keploy-recorded-boundary", wantBoundary: "keploy-recorded-boundary"},
+		{name: "no boundary", contentType: "multipart/form-data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+				if err != nil {
+					t.Errorf("failed to parse the content type %q: %v", r.Header.Get("Content-Type"), err)
+				}
+				if tt.wantBoundary != "" && params["boundary"] != tt.wantBoundary {
+					t.Errorf("the boundary = %q, want the recorded %q", params["boundary"], tt.wantBoundary)
+				}
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("failed to parse the multipart form: %v", err)
+					return
+				}
+				if got := r.FormValue("description"); got != "avatar of the user" {
+					t.Errorf("the description field = %q", got)
+				}
+				file, header, err := r.FormFile("avatar")
+				if err != nil {
+					t.Errorf("failed to read the avatar file: %v", err)
+					return
+				}
+				defer file.Close()
+				data, _ := io.ReadAll(file)
+				if header.Filename != "avatar.png" || header.Header.Get("Content-Type") != "image/png" || !bytes.Equal(data, binary) {
+					t.Errorf("the avatar file = %s %s %v, want avatar.png image/png %v", header.Filename, header.Header.Get("Content-Type"), data, binary)
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer server.Close()
+
+			tc := models.TestCase{
+				Name: "test-1",
+				HTTPReq: models.HTTPReq{
+					Method:     http.MethodPost,
+					URL:        server.URL + "/users/1/avatar",
+					ProtoMajor: 1,
+					ProtoMinor: 1,
+					Header:     map[string]string{"Content-Type": tt.contentType, "Content-Length": "1"},
+					Form:       []models.FormData{{Key: "description", Values: []string{"avatar of the user"}}},
+					FormFiles:  []models.FormFile{{Name: "avatar", Filename: "avatar.png", ContentType: "image/png", Data: binary}},
+				},
+			}
+			resp, err := SimulateHTTP(context.Background(), tc, "test-set-0", zap.NewNop(), 5, 0, nil)
+			if err != nil {
+				t.Fatalf("SimulateHTTP() error = %v", err)
+			}
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("SimulateHTTP() status code = %d, want %d", resp.StatusCode, http.StatusCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ValidateRemoteURL checks that the remote url is a http(s) url, e.g. pre-signed, or a s3:// or gs:// url of an object.
+func ValidateRemoteURL(remoteURL string) error {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse the remote url: %w", err)
+	}
+	switch u.Scheme {
+	case "https", "http":
+		return nil
+	case "s3", "gs":
+		if u.Host == "" || strings.Trim(u.Path, "/") == "" {
+			return fmt.Errorf("invalid %s:// url %q, should be %s://<bucket>/<object>", u.Scheme, remoteURL, u.Scheme)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported remote url scheme: %q, should be https, s3 or gs", u.Scheme)
+	}
+}
+
+// newRemoteRequest creates the request to the remote url. The s3:// and gs:// urls are mapped to the https urls of
+// their objects, the s3 requests are signed with the aws credentials of the environment and the gs requests carry
+// the access token of GOOGLE_OAUTH_ACCESS_TOKEN, the objects are accessed anonymously without them.
+func newRemoteRequest(ctx context.Context, method, remoteURL string, body []byte) (*http.Request, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the remote url: %w", err)
+	}
+	bucket, object := u.Host, strings.TrimPrefix(u.Path, "/")
+	targetURL := remoteURL
+	switch u.Scheme {
+	case "s3":
+		targetURL = s3ObjectURL(bucket, object)
+	case "gs":
+		targetURL = gcsObjectURL(bucket, object)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "s3":
+		creds := awsCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if creds.AccessKeyID != "" && creds.SecretAccessKey != "" {
+			signS3Request(req, body, awsRegion(), creds, time.Now())
+		}
+	case "gs":
+		if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return req, nil
+}
+
+// awsRegion returns the region of the s3 buckets, us-east-1 unless set in the environment.
+func awsRegion() string {
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(env); region != "" {
+			return region
+		}
+	}
+	return "us-east-1"
+}
+
+// s3ObjectURL returns the https url of the s3 object. The buckets are addressed by the path on the endpoint of
+// AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL, e.g. of minio, and on the regional endpoint when their name has dots,
+// which the wildcard certificate of the virtual-hosted bucket names does not cover.
+func s3ObjectURL(bucket, object string) string {
+	for _, env := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if endpoint := os.Getenv(env); endpoint != "" {
+			return strings.TrimSuffix(endpoint, "/") + "/" + bucket + "/" + awsEscape(object)
+		}
+	}
+	if strings.Contains(bucket, ".") {
+		return "https://s3." + awsRegion() + ".amazonaws.com/" + bucket + "/" + awsEscape(object)
+	}
+	return "https://" + bucket + ".s3." + awsRegion() + ".amazonaws.com/" + awsEscape(object)
+}
+
+// gcsObjectURL returns the https url of the gcs object, on the emulator of STORAGE_EMULATOR_HOST when set.
+func gcsObjectURL(bucket, object string) string {
+	endpoint := "https://storage.googleapis.com"
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		endpoint = strings.TrimSuffix(host, "/")
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+	}
+	return endpoint + "/" + bucket + "/" + awsEscape(object)
+}
+
+// DownloadTestSets downloads the .tar.gz archive containing the keploy directory from the remote url, extracts it
+// into the directory and returns the path of the extracted keploy directory. The caller owns the directory.
+func DownloadTestSets(ctx context.Context, logger *zap.Logger, remoteURL string, dir string) (string, error) {
+	if err := ValidateRemoteURL(remoteURL); err != nil {
+		return "", err
+	}
+
+	req, err := newRemoteRequest(ctx, http.MethodGet, remoteURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the download request: %w", err)
+	}
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download the test sets: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			LogError(logger, err, "failed to close the response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d while downloading the test sets", resp.StatusCode)
+	}
+
+	err = extractTarGz(resp.Body, dir)
+	if err != nil {
+		return "", err
+	}
+
+	keployDir := filepath.Join(dir, "keploy")
+	if _, err := os.Stat(keployDir); err != nil {
+		// the archive contains the content of the keploy directory itself
+		keployDir = dir
+	}
+	logger.Info("downloaded the remote test sets", zap.String("url", remoteURL), zap.String("path", keployDir))
+	return keployDir, nil
+}
+
+func extractTarGz(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read the gzip archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the tar archive: %w", err)
+		}
+
+		// prevent the archive entries from being written outside of the destination
+		target := filepath.Join(dest, hdr.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dest)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid file path in the archive: %s", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o777); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o777); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o777)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			closeErr := f.Close()
+			if err != nil {
+				return err
+			}
+			if closeErr != nil {
+				return closeErr
+			}
+		}
+	}
+}
+
+// UploadReports archives the given reports directory as .tar.gz and uploads it to the remote url with a PUT request.
+func UploadReports(ctx context.Context, logger *zap.Logger, reportsDir string, remoteURL string) error {
+	if err := ValidateRemoteURL(remoteURL); err != nil {
+		return err
+	}
+
+	// the archive is buffered, the object storages need its length and its hash is signed for s3
+	var archive bytes.Buffer
+	if err := writeTarGz(&archive, reportsDir); err != nil {
+		return fmt.Errorf("failed to archive the reports: %w", err)
+	}
+
+	req, err := newRemoteRequest(ctx, http.MethodPut, remoteURL, archive.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to create the upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload the reports: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			LogError(logger, err, "failed to close the response body")
+		}
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d while uploading the reports", resp.StatusCode)
+	}
+	logger.Info("uploaded the reports", zap.String("url", remoteURL))
+	return nil
+}
+
+func writeTarGz(w io.Writer, dir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Dir(dir), path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
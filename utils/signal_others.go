@@ -4,6 +4,7 @@ package utils
 
 import (
 	"context"
+	"io"
 	"os"
 	"os/exec"
 	"syscall"
@@ -27,7 +28,9 @@ func SendSignal(logger *zap.Logger, pid int, sig syscall.Signal) error {
 	return nil
 }
 
-func ExecuteCommand(ctx context.Context, logger *zap.Logger, userCmd string, cancel func(cmd *exec.Cmd) func() error, waitDelay time.Duration) CmdError {
+// ExecuteCommand runs the command in its own process group until it exits, onStart (if not nil) is called with the pid
+// of the started process. The output of the command is copied to stdout and stderr (if not nil) as well as the terminal.
+func ExecuteCommand(ctx context.Context, logger *zap.Logger, userCmd string, cancel func(cmd *exec.Cmd) func() error, waitDelay time.Duration, onStart func(pid int), stdout, stderr io.Writer) CmdError {
 	// Run the app as the user who invoked sudo
 	username := os.Getenv("SUDO_USER")
 
@@ -52,6 +55,12 @@ func ExecuteCommand(ctx context.Context, logger *zap.Logger, userCmd string, can
 	// Set the output of the command
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if stdout != nil {
+		cmd.Stdout = io.MultiWriter(os.Stdout, stdout)
+	}
+	if stderr != nil {
+		cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	}
 
 	logger.Debug("", zap.Any("executing cli", cmd.String()))
 
@@ -59,6 +68,9 @@ func ExecuteCommand(ctx context.Context, logger *zap.Logger, userCmd string, can
 	if err != nil {
 		return CmdError{Type: Init, Err: err}
 	}
+	if onStart != nil {
+		onStart(cmd.Process.Pid)
+	}
 
 	err = cmd.Wait()
 	if err != nil {
@@ -3,7 +3,12 @@
 package utils
 
 import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/sys/windows"
@@ -34,3 +39,42 @@ func SendSignal(logger *zap.Logger, pid int, sig syscall.Signal) error {
 	logger.Debug("signal sent to process successfully", zap.Int("pid", pid), zap.String("signal", sig.String()))
 	return nil
 }
+
+// ExecuteCommand runs the command until it exits, onStart (if not nil) is called with the pid of the started
+// process. The output of the command is copied to stdout and stderr (if not nil) as well as the terminal.
+func ExecuteCommand(ctx context.Context, logger *zap.Logger, userCmd string, cancel func(cmd *exec.Cmd) func() error, waitDelay time.Duration, onStart func(pid int), stdout, stderr io.Writer) CmdError {
+	cmd := exec.CommandContext(ctx, "cmd", "/C", userCmd)
+
+	// Set the cancel function for the command
+	cmd.Cancel = cancel(cmd)
+
+	// wait after cancelling the command, before killing it
+	cmd.WaitDelay = waitDelay
+
+	// Set the output of the command
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if stdout != nil {
+		cmd.Stdout = io.MultiWriter(os.Stdout, stdout)
+	}
+	if stderr != nil {
+		cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	}
+
+	logger.Debug("", zap.Any("executing cli", cmd.String()))
+
+	err := cmd.Start()
+	if err != nil {
+		return CmdError{Type: Init, Err: err}
+	}
+	if onStart != nil {
+		onStart(cmd.Process.Pid)
+	}
+
+	err = cmd.Wait()
+	if err != nil {
+		return CmdError{Type: Runtime, Err: err}
+	}
+
+	return CmdError{}
+}